@@ -1,9 +1,15 @@
 package datastructures
 
 import (
+	"sort"
 	"strings"
+	"time"
 )
 
+// LicenseUSGovernment is the license of datasets which are a U.S. Government
+// work.
+const LicenseUSGovernment = "U.S. Government work, public domain in the United States"
+
 // These are used to aid in the reflection based understanding of the data structures
 // so that manually curated lists of fields need to be kept up to date.
 var (
@@ -16,7 +22,94 @@ func init() {
 
 // Attributions is a collection of attribution messages and tags for data used in a
 // station or observation.
+//
+// Each field is a sorted set of values so that the attributions from several
+// sources can be merged together without duplicates. Where a value only makes
+// sense alongside its dataset, (Versions and Retrieved), it is prefixed with
+// the dataset name. e.g. "GHCN-D 3", "GHCN-D 2024-05-01T00:00:00Z"
 type Attributions struct {
+	// Datasets are the names of the source datasets. e.g. "GHCN-D"
+	Datasets []string `beam:"datasets" json:"datasets"`
+	// Versions of the datasets used.
+	Versions []string `beam:"versions" json:"versions"`
+	// DOIs of the datasets used, without the "doi:" prefix.
+	DOIs []string `beam:"dois" json:"dois"`
+	// Licenses the datasets are made available under.
+	Licenses []string `beam:"licenses" json:"licenses"`
+	// Citations are the preferred citation text for the datasets.
+	Citations []string `beam:"citations" json:"citations"`
+	// Retrieved is when each dataset was downloaded, in RFC 3339 format.
+	Retrieved []string `beam:"retrieved" json:"retrieved"`
+	// Networks are the observing networks which contributed the data.
+	// e.g. "US COOP", "CoCoRaHS", "ASOS"
+	Networks []string `beam:"networks" json:"networks"`
+}
+
+// Dataset describes a source dataset, so that each importer only needs to
+// give its details to build the Attributions for its data.
+type Dataset struct {
+	// Name is used in Attributions and as the Source of the observations.
+	Name string
+	// Version and DOI are left empty if the dataset does not have one.
+	Version string
+	DOI     string
+	// License summarizes the terms the dataset is made available under.
+	License string
+	// Citation is the preferred citation text for the dataset.
+	Citation string
+	// Networks are the observing networks which contribute all of the data.
+	Networks []string
+}
+
+// Attributions returns the Attributions for data from the dataset. Retrieved
+// is when the data files were downloaded, and is left out if it is the zero
+// time.
+func (d Dataset) Attributions(retrieved time.Time) *Attributions {
+	a := &Attributions{
+		Datasets:  AddToSet(nil, d.Name),
+		DOIs:      AddToSet(nil, d.DOI),
+		Licenses:  AddToSet(nil, d.License),
+		Citations: AddToSet(nil, d.Citation),
+		Networks:  AddToSet(nil, d.Networks...),
+	}
+	if d.Version != "" {
+		a.Versions = []string{d.Name + " " + d.Version}
+	}
+	if !retrieved.IsZero() {
+		a.Retrieved = []string{d.Name + " " + retrieved.UTC().Format(time.RFC3339)}
+	}
+	return a
+}
+
+// Merge adds all of the values in other to a.
+func (a *Attributions) Merge(other *Attributions) {
+	if other == nil {
+		return
+	}
+	a.Datasets = AddToSet(a.Datasets, other.Datasets...)
+	a.Versions = AddToSet(a.Versions, other.Versions...)
+	a.DOIs = AddToSet(a.DOIs, other.DOIs...)
+	a.Licenses = AddToSet(a.Licenses, other.Licenses...)
+	a.Citations = AddToSet(a.Citations, other.Citations...)
+	a.Retrieved = AddToSet(a.Retrieved, other.Retrieved...)
+	a.Networks = AddToSet(a.Networks, other.Networks...)
+}
+
+// AddToSet returns the sorted set with the non-empty values added.
+func AddToSet(set []string, values ...string) []string {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		i := sort.SearchStrings(set, v)
+		if i < len(set) && set[i] == v {
+			continue
+		}
+		set = append(set, "")
+		copy(set[i+1:], set[i:])
+		set[i] = v
+	}
+	return set
 }
 
 func (a *Attributions) String() string {
@@ -35,7 +128,15 @@ func (a *Attributions) HeaderColumns(prefix string) []string {
 }
 
 // ValueColumns returns the values for this entity as a collection of strings
-// in the same order as the HeaderColumns.
+// in the same order as the HeaderColumns. Each set is joined with ";".
 func (a *Attributions) ValueColumns() []string {
-	return []string{}
+	return []string{
+		strings.Join(a.Datasets, ";"),
+		strings.Join(a.Versions, ";"),
+		strings.Join(a.DOIs, ";"),
+		strings.Join(a.Licenses, ";"),
+		strings.Join(a.Citations, ";"),
+		strings.Join(a.Retrieved, ";"),
+		strings.Join(a.Networks, ";"),
+	}
 }
@@ -0,0 +1,100 @@
+package datastructures
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAddToSet(t *testing.T) {
+	tests := []struct {
+		set    []string
+		values []string
+		want   []string
+	}{
+		{set: nil, values: nil, want: nil},
+		{set: nil, values: []string{"b", "a", "b", ""}, want: []string{"a", "b"}},
+		{set: []string{"a", "c"}, values: []string{"b", "c", "d"}, want: []string{"a", "b", "c", "d"}},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, AddToSet(test.set, test.values...)); diff != "" {
+			t.Errorf("AddToSet(%v, %v) diff (-want +got):\n%s", test.set, test.values, diff)
+		}
+	}
+}
+
+func TestAttributionsMerge(t *testing.T) {
+	a := &Attributions{
+		Datasets: []string{"GHCN-D"},
+		Licenses: []string{"Public domain"},
+		Networks: []string{"US COOP"},
+	}
+	a.Merge(&Attributions{
+		Datasets: []string{"ISD"},
+		Licenses: []string{"Public domain"},
+		Networks: []string{"ASOS", "US COOP"},
+	})
+	a.Merge(nil)
+
+	want := &Attributions{
+		Datasets: []string{"GHCN-D", "ISD"},
+		Licenses: []string{"Public domain"},
+		Networks: []string{"ASOS", "US COOP"},
+	}
+	if diff := cmp.Diff(want, a); diff != "" {
+		t.Errorf("Merge() diff (-want +got):\n%s", diff)
+	}
+
+	if got, want := len(a.ValueColumns()), len(a.HeaderColumns("")); got != want {
+		t.Errorf("len(ValueColumns()) = %d, want %d", got, want)
+	}
+	if got, want := a.ValueColumns()[0], "GHCN-D;ISD"; got != want {
+		t.Errorf("ValueColumns()[0] = %q, want %q", got, want)
+	}
+}
+
+func TestDatasetAttributions(t *testing.T) {
+	retrieved := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+	tests := []struct {
+		dataset   Dataset
+		retrieved time.Time
+		want      *Attributions
+	}{
+		{
+			dataset: Dataset{Name: "EPA AQS", License: LicenseUSGovernment, Citation: "AQS"},
+			want: &Attributions{
+				Datasets:  []string{"EPA AQS"},
+				Licenses:  []string{LicenseUSGovernment},
+				Citations: []string{"AQS"},
+			},
+		},
+		{
+			dataset: Dataset{
+				Name:     "GHCN-D",
+				Version:  "3",
+				DOI:      "10.7289/V5D21VHZ",
+				License:  LicenseUSGovernment,
+				Citation: "GHCN-D",
+				Networks: []string{"US COOP", "CoCoRaHS"},
+			},
+			retrieved: retrieved,
+			want: &Attributions{
+				Datasets:  []string{"GHCN-D"},
+				Versions:  []string{"GHCN-D 3"},
+				DOIs:      []string{"10.7289/V5D21VHZ"},
+				Licenses:  []string{LicenseUSGovernment},
+				Citations: []string{"GHCN-D"},
+				Retrieved: []string{"GHCN-D 2024-05-01T19:30:00Z"},
+				Networks:  []string{"CoCoRaHS", "US COOP"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, test.dataset.Attributions(test.retrieved)); diff != "" {
+			t.Errorf("%+v.Attributions(%v) diff (-want +got):\n%s", test.dataset, test.retrieved, diff)
+		}
+	}
+}
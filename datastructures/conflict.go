@@ -0,0 +1,37 @@
+package datastructures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Conflict records a value from one source that lost out to another sources
+// value for the same field when the stations records were merged.
+type Conflict struct {
+	// Field is the path to the field in the Station, e.g. "Name" or
+	// "Geography.ElevationMeters".
+	Field string `beam:"field" json:"field"`
+	// Source is the source the losing value came from.
+	Source string `beam:"source" json:"source"`
+	// Value is the losing value, formatted as a string.
+	Value string `beam:"value" json:"value"`
+	// ChosenSource is the source whose value was used instead.
+	ChosenSource string `beam:"chosen_source" json:"chosen_source"`
+	// Reason is the rule that decided between the two, one of "precedence",
+	// "recency", "precision", or "order".
+	Reason string `beam:"reason" json:"reason"`
+}
+
+func (c *Conflict) String() string {
+	return fmt.Sprintf("%s:%s=%s<%s(%s)", c.Field, c.Source, c.Value, c.ChosenSource, c.Reason)
+}
+
+// conflictsString returns the given conflicts as a single value suitable for
+// using in one CSV column.
+func conflictsString(conflicts []*Conflict) string {
+	parts := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,29 @@
+package datastructures
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ElementCoverage is the period of record for one type of element (such as
+// precipitation or max temperature) reported by a station.
+type ElementCoverage struct {
+	// Element is the source specific code for the element. e.g., "PRCP", "TMAX"
+	Element   string `beam:"element" json:"element"`
+	FirstYear int32  `beam:"first_year" json:"first_year"`
+	LastYear  int32  `beam:"last_year" json:"last_year"`
+}
+
+func (c *ElementCoverage) String() string {
+	return fmt.Sprintf("%s:%d-%d", c.Element, c.FirstYear, c.LastYear)
+}
+
+// coverageString returns the given coverages as a single value suitable for
+// using in one CSV column.
+func coverageString(coverage []*ElementCoverage) string {
+	parts := make([]string, len(coverage))
+	for i, c := range coverage {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ";")
+}
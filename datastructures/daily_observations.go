@@ -1,6 +1,10 @@
 package datastructures
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 var (
 	dailyObsFields []string
@@ -13,18 +17,107 @@ func init() {
 // DailyObservation holds daily summary weather observation information.
 // This generally covers the min, mean, and max of the values tracked in the
 // Observation type.
+//
+// All values are in SI units, (or the common meteorological ones for them such
+// as hPa and mm), and are UnsetValue when the source did not report them.
 type DailyObservation struct {
-	StationID string
-	Date      string
-	TempCMin  float64
-	TempCMean float64
-	TempCMax  float64
+	StationID string `beam:"station_id" json:"station_id"`
+	// Source is the dataset the observation came from, matching the name in
+	// the datasets Attributions. e.g. "GHCN-D"
+	Source string `beam:"source" json:"source"`
+	// License and Citation are the terms of, and the preferred citation for,
+	// the dataset, so that the observation carries them wherever it is
+	// published.
+	License  string `beam:"license" json:"license"`
+	Citation string `beam:"citation" json:"citation"`
+	// Date is the day the summary covers, output as YYYY-MM-DD.
+	Date Date `beam:"date" json:"date"`
+	// Timezone is the IANA timezone of the station whose local day Date is,
+	// or "" if the summary is for the UTC day or the zone is unknown.
+	Timezone string `beam:"timezone" json:"timezone"`
+
+	TempCMin  float64 `beam:"temp_c_min" json:"temp_c_min"`
+	TempCMean float64 `beam:"temp_c_mean" json:"temp_c_mean"`
+	TempCMax  float64 `beam:"temp_c_max" json:"temp_c_max"`
+
+	DewPointCMean float64 `beam:"dew_point_c_mean" json:"dew_point_c_mean"`
+	// RelativeHumidityMean is the mean relative humidity in percent.
+	RelativeHumidityMean float64 `beam:"relative_humidity_mean" json:"relative_humidity_mean"`
+
+	// Precipitation totals for the day.
+	PrecipMM    float64 `beam:"precip_mm" json:"precip_mm"`
+	SnowfallMM  float64 `beam:"snowfall_mm" json:"snowfall_mm"`
+	SnowDepthMM float64 `beam:"snow_depth_mm" json:"snow_depth_mm"`
+
+	WindSpeedMPSMean float64 `beam:"wind_speed_mps_mean" json:"wind_speed_mps_mean"`
+	// WindSpeedMPSMax is the fastest sustained wind speed.
+	WindSpeedMPSMax float64 `beam:"wind_speed_mps_max" json:"wind_speed_mps_max"`
+	// WindDirectionDegMax is the direction, in degrees clockwise from true
+	// north, the fastest sustained wind came from.
+	WindDirectionDegMax float64 `beam:"wind_direction_deg_max" json:"wind_direction_deg_max"`
+	WindGustMPSMax      float64 `beam:"wind_gust_mps_max" json:"wind_gust_mps_max"`
+
+	PressureStationHPaMean  float64 `beam:"pressure_station_hpa_mean" json:"pressure_station_hpa_mean"`
+	PressureSeaLevelHPaMean float64 `beam:"pressure_sea_level_hpa_mean" json:"pressure_sea_level_hpa_mean"`
+
+	VisibilityMMean float64 `beam:"visibility_m_mean" json:"visibility_m_mean"`
+
+	// SunshineMinutes is the total minutes of sunshine.
+	SunshineMinutes float64 `beam:"sunshine_minutes" json:"sunshine_minutes"`
+	// SunshinePercent is the percent of the possible sunshine for the day.
+	SunshinePercent float64 `beam:"sunshine_percent" json:"sunshine_percent"`
+
+	// The number of observations the means were computed from, for sources
+	// that report it.
+	TempCount             int32 `beam:"temp_count" json:"temp_count"`
+	DewPointCount         int32 `beam:"dew_point_count" json:"dew_point_count"`
+	PressureStationCount  int32 `beam:"pressure_station_count" json:"pressure_station_count"`
+	PressureSeaLevelCount int32 `beam:"pressure_sea_level_count" json:"pressure_sea_level_count"`
+	VisibilityCount       int32 `beam:"visibility_count" json:"visibility_count"`
+	WindSpeedCount        int32 `beam:"wind_speed_count" json:"wind_speed_count"`
+
+	// Weather are the METAR present weather codes for the types of weather
+	// which occurred during the day, e.g. "FG", "RA", "TS".
+	Weather []string `beam:"weather" json:"weather"`
+
+	// Flags are the quality flags for the measured values, keyed by field.
+	Flags Flags `beam:"flags" json:"flags"`
+}
+
+// Interval returns the UTC instants the summarized day starts and ends at.
+// The end is exclusive.
+func (a *DailyObservation) Interval() (start, end time.Time, err error) {
+	return a.Date.Interval(a.Timezone)
 }
 
 // EmptyDailyObservation returns a pre-set empty value with the missing sentinel
 // values set on all relevant fields.
 func EmptyDailyObservation() *DailyObservation {
-	return &DailyObservation{}
+	return &DailyObservation{
+		TempCMin:                UnsetValue,
+		TempCMean:               UnsetValue,
+		TempCMax:                UnsetValue,
+		DewPointCMean:           UnsetValue,
+		RelativeHumidityMean:    UnsetValue,
+		PrecipMM:                UnsetValue,
+		SnowfallMM:              UnsetValue,
+		SnowDepthMM:             UnsetValue,
+		WindSpeedMPSMean:        UnsetValue,
+		WindSpeedMPSMax:         UnsetValue,
+		WindDirectionDegMax:     UnsetValue,
+		WindGustMPSMax:          UnsetValue,
+		PressureStationHPaMean:  UnsetValue,
+		PressureSeaLevelHPaMean: UnsetValue,
+		VisibilityMMean:         UnsetValue,
+		SunshineMinutes:         UnsetValue,
+		SunshinePercent:         UnsetValue,
+		TempCount:               UnsetValue,
+		DewPointCount:           UnsetValue,
+		PressureStationCount:    UnsetValue,
+		PressureSeaLevelCount:   UnsetValue,
+		VisibilityCount:         UnsetValue,
+		WindSpeedCount:          UnsetValue,
+	}
 }
 
 func (a *DailyObservation) String() string {
@@ -43,7 +136,39 @@ func (a *DailyObservation) HeaderColumns(prefix string) []string {
 }
 
 // ValueColumns returns the values for this entity as a collection of strings
-// in the same order as the HeaderColumns.
+// in the same order as the HeaderColumns. Weather codes are joined with " ".
 func (a *DailyObservation) ValueColumns() []string {
-	return []string{}
+	return []string{
+		a.StationID,
+		a.Source,
+		a.License,
+		a.Citation,
+		a.Date.String(),
+		a.Timezone,
+		floatOrUnsetString(a.TempCMin),
+		floatOrUnsetString(a.TempCMean),
+		floatOrUnsetString(a.TempCMax),
+		floatOrUnsetString(a.DewPointCMean),
+		floatOrUnsetString(a.RelativeHumidityMean),
+		floatOrUnsetString(a.PrecipMM),
+		floatOrUnsetString(a.SnowfallMM),
+		floatOrUnsetString(a.SnowDepthMM),
+		floatOrUnsetString(a.WindSpeedMPSMean),
+		floatOrUnsetString(a.WindSpeedMPSMax),
+		floatOrUnsetString(a.WindDirectionDegMax),
+		floatOrUnsetString(a.WindGustMPSMax),
+		floatOrUnsetString(a.PressureStationHPaMean),
+		floatOrUnsetString(a.PressureSeaLevelHPaMean),
+		floatOrUnsetString(a.VisibilityMMean),
+		floatOrUnsetString(a.SunshineMinutes),
+		floatOrUnsetString(a.SunshinePercent),
+		fmt.Sprintf("%d", a.TempCount),
+		fmt.Sprintf("%d", a.DewPointCount),
+		fmt.Sprintf("%d", a.PressureStationCount),
+		fmt.Sprintf("%d", a.PressureSeaLevelCount),
+		fmt.Sprintf("%d", a.VisibilityCount),
+		fmt.Sprintf("%d", a.WindSpeedCount),
+		strings.Join(a.Weather, " "),
+		a.Flags.String(),
+	}
 }
@@ -0,0 +1,89 @@
+package datastructures
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDailyObservationColumns(t *testing.T) {
+	o := EmptyDailyObservation()
+	o.StationID = "USW00023234"
+	o.Source = "GHCN-D"
+	o.License = "CC0"
+	o.Citation = "GHCN-D, 2023."
+	o.Date = Date{2023, 1, 1}
+	o.Timezone = "America/Los_Angeles"
+	o.TempCMax = 13.9
+	o.PrecipMM = 5.6
+	o.TempCount = 24
+	o.Weather = []string{"RA", "TS"}
+	o.Flags.Set("TempCMax", &Flag{Quality: QualityPassed, Original: "  W"})
+	o.Flags.Set("PrecipMM", &Flag{Quality: QualityTrace, Original: "T W"})
+
+	headers := o.HeaderColumns("")
+	values := o.ValueColumns()
+	if len(headers) != len(values) {
+		t.Fatalf("len(HeaderColumns) = %d, len(ValueColumns) = %d, want them to match", len(headers), len(values))
+	}
+
+	want := map[string]string{
+		"StationID":       "USW00023234",
+		"Source":          "GHCN-D",
+		"License":         "CC0",
+		"Citation":        "GHCN-D, 2023.",
+		"Date":            "2023-01-01",
+		"Timezone":        "America/Los_Angeles",
+		"TempCMin":        UnsetValueString,
+		"TempCMax":        "13.90",
+		"PrecipMM":        "5.60",
+		"WindGustMPSMax":  UnsetValueString,
+		"TempCount":       "24",
+		"WindSpeedCount":  UnsetValueString,
+		"SunshinePercent": UnsetValueString,
+		"Weather":         "RA TS",
+		"Flags":           "PrecipMM=trace(T W);TempCMax=passed(  W)",
+	}
+	for i, h := range headers {
+		if w, ok := want[h]; ok && values[i] != w {
+			t.Errorf("column %s = %q, want %q", h, values[i], w)
+		}
+	}
+
+	// Every float and count field should start out unset.
+	empty := EmptyDailyObservation().ValueColumns()
+	for i, h := range headers[6 : len(headers)-2] {
+		if got := empty[i+6]; got != UnsetValueString {
+			t.Errorf("EmptyDailyObservation() column %s = %q, want %q", h, got, UnsetValueString)
+		}
+	}
+}
+
+func TestDailyObservationJSON(t *testing.T) {
+	o := EmptyDailyObservation()
+	o.StationID = "USW00023234"
+	o.Date = Date{2023, 1, 1}
+	o.WindGustMPSMax = 20.1
+	o.Flags.Set("WindGustMPSMax", &Flag{Quality: QualitySuspect, Original: "S"})
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	for _, want := range []string{`"station_id":"USW00023234"`, `"date":"2023-01-01"`, `"wind_gust_mps_max":20.1`, `"temp_c_min":-9999`,
+		`"flags":{"WindGustMPSMax":{"quality":"suspect","original":"S"}}`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("json.Marshal() = %s, want it to contain %s", b, want)
+		}
+	}
+
+	var got DailyObservation
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if diff := cmp.Diff(o, &got); diff != "" {
+		t.Errorf("json round trip diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,166 @@
+package datastructures
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts for the date and time formats used in the output.
+const (
+	// DateLayout is the ISO 8601 calendar date format, YYYY-MM-DD.
+	DateLayout = "2006-01-02"
+	// TimeLayout is the ISO 8601 UTC timestamp format used for instants.
+	TimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// Date is a civil calendar date with no time of day or timezone. The zero
+// value is an unknown date.
+//
+// Daily summaries are reported for the local day at the station, so use In or
+// Interval with the stations timezone to turn a Date into instants.
+type Date struct {
+	Year  int `beam:"year"`
+	Month int `beam:"month"`
+	Day   int `beam:"day"`
+}
+
+// NewDate returns the Date for the given year, month and day, normalizing
+// out of range values the same way time.Date does, e.g. October 32 becomes
+// November 1.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns the Date of the given time in its location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: int(m), Day: d}
+}
+
+// ParseDate parses a date in either the ISO 8601 YYYY-MM-DD form or the
+// compact YYYYMMDD form used by many of the NOAA sources. An empty string
+// returns the zero Date.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+
+	layout := DateLayout
+	if len(s) == 8 {
+		layout = "20060102"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("datastructures: invalid date %q", s)
+	}
+	return DateOf(t), nil
+}
+
+// IsZero reports if the date is unknown.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String returns the date in ISO 8601 YYYY-MM-DD form, or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Compact returns the date in YYYYMMDD form, or "" for the zero Date.
+func (d Date) Compact() string {
+	if d.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%04d%02d%02d", d.Year, d.Month, d.Day)
+}
+
+// Compare returns -1, 0 or +1 if d is before, the same as, or after o.
+func (d Date) Compare(o Date) int {
+	switch {
+	case d.Year != o.Year:
+		return cmp.Compare(d.Year, o.Year)
+	case d.Month != o.Month:
+		return cmp.Compare(d.Month, o.Month)
+	}
+	return cmp.Compare(d.Day, o.Day)
+}
+
+// Before reports if d is before o.
+func (d Date) Before(o Date) bool {
+	return d.Compare(o) < 0
+}
+
+// After reports if d is after o.
+func (d Date) After(o Date) bool {
+	return d.Compare(o) > 0
+}
+
+// AddDays returns the date n days after d.
+func (d Date) AddDays(n int) Date {
+	return NewDate(d.Year, time.Month(d.Month), d.Day+n)
+}
+
+// In returns the instant the date starts at in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, loc)
+}
+
+// Interval returns the UTC instants the date starts and ends at in the
+// named IANA timezone. The end is exclusive. An empty timezone is UTC.
+func (d Date) Interval(tz string) (start, end time.Time, err error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("datastructures: unknown timezone %q: %v", tz, err)
+	}
+	return d.In(loc).UTC(), d.AddDays(1).In(loc).UTC(), nil
+}
+
+// MarshalText implements encoding.TextMarshaler so dates appear as
+// YYYY-MM-DD strings in JSON.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(b []byte) error {
+	v, err := ParseDate(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// ParseUTC parses a date in YYYYMMDD or YYYY-MM-DD form and a time of day in
+// HHMM or HH:MM form, both already in UTC, into an instant.
+func ParseUTC(date, hhmm string) (time.Time, error) {
+	d, err := ParseDate(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d.IsZero() {
+		return time.Time{}, fmt.Errorf("datastructures: missing date")
+	}
+
+	hm := strings.ReplaceAll(strings.TrimSpace(hhmm), ":", "")
+	t, err := time.Parse("1504", hm)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("datastructures: invalid time of day %q", hhmm)
+	}
+	return d.In(time.UTC).Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute), nil
+}
+
+// FormatTime returns the instant in UTC in the ISO 8601 TimeLayout, or "" for
+// the zero time.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(TimeLayout)
+}
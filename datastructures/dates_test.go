@@ -0,0 +1,187 @@
+package datastructures
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    Date
+		wantErr bool
+	}{
+		{have: "", want: Date{}},
+		{have: "20230101", want: Date{2023, 1, 1}},
+		{have: "2023-01-01", want: Date{2023, 1, 1}},
+		{have: " 18900229 ", wantErr: true},
+		{have: "2024-02-29", want: Date{2024, 2, 29}},
+		{have: "2023-13-01", wantErr: true},
+		{have: "2023/01/01", wantErr: true},
+		{have: "230101", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDate(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseDate(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseDate(%q) = %v, want %v", test.have, got, test.want)
+		}
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+	tests := []struct {
+		have        Date
+		wantString  string
+		wantCompact string
+	}{
+		{have: Date{}, wantString: "", wantCompact: ""},
+		{have: Date{2023, 1, 2}, wantString: "2023-01-02", wantCompact: "20230102"},
+		{have: Date{812, 12, 31}, wantString: "0812-12-31", wantCompact: "08121231"},
+	}
+
+	for _, test := range tests {
+		if got := test.have.String(); got != test.wantString {
+			t.Errorf("%#v.String() = %q, want %q", test.have, got, test.wantString)
+		}
+		if got := test.have.Compact(); got != test.wantCompact {
+			t.Errorf("%#v.Compact() = %q, want %q", test.have, got, test.wantCompact)
+		}
+	}
+}
+
+func TestDateCompare(t *testing.T) {
+	tests := []struct {
+		a, b Date
+		want int
+	}{
+		{a: Date{2023, 1, 1}, b: Date{2023, 1, 1}, want: 0},
+		{a: Date{2022, 12, 31}, b: Date{2023, 1, 1}, want: -1},
+		{a: Date{2023, 2, 1}, b: Date{2023, 1, 31}, want: 1},
+		{a: Date{2023, 1, 2}, b: Date{2023, 1, 10}, want: -1},
+		{a: Date{}, b: Date{1900, 1, 1}, want: -1},
+	}
+
+	for _, test := range tests {
+		if got := test.a.Compare(test.b); got != test.want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", test.a, test.b, got, test.want)
+		}
+		if got := test.a.Before(test.b); got != (test.want < 0) {
+			t.Errorf("%v.Before(%v) = %v, want %v", test.a, test.b, got, test.want < 0)
+		}
+		if got := test.a.After(test.b); got != (test.want > 0) {
+			t.Errorf("%v.After(%v) = %v, want %v", test.a, test.b, got, test.want > 0)
+		}
+	}
+}
+
+func TestDateAddDays(t *testing.T) {
+	tests := []struct {
+		have Date
+		n    int
+		want Date
+	}{
+		{have: Date{2023, 1, 31}, n: 1, want: Date{2023, 2, 1}},
+		{have: Date{2024, 2, 28}, n: 1, want: Date{2024, 2, 29}},
+		{have: Date{2023, 2, 28}, n: 1, want: Date{2023, 3, 1}},
+		{have: Date{2023, 1, 1}, n: -1, want: Date{2022, 12, 31}},
+	}
+
+	for _, test := range tests {
+		if got := test.have.AddDays(test.n); got != test.want {
+			t.Errorf("%v.AddDays(%d) = %v, want %v", test.have, test.n, got, test.want)
+		}
+	}
+}
+
+func TestDateInterval(t *testing.T) {
+	tests := []struct {
+		have      Date
+		tz        string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			have:      Date{2023, 1, 1},
+			tz:        "",
+			wantStart: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			have:      Date{2023, 1, 1},
+			tz:        "America/Los_Angeles",
+			wantStart: time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2023, 1, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			// The day DST starts is only 23 hours long.
+			have:      Date{2023, 3, 12},
+			tz:        "America/New_York",
+			wantStart: time.Date(2023, 3, 12, 5, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2023, 3, 13, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			have:    Date{2023, 1, 1},
+			tz:      "Mars/Olympus_Mons",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		start, end, err := test.have.Interval(test.tz)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%v.Interval(%q) error = %v, wantErr %v", test.have, test.tz, err, test.wantErr)
+			continue
+		}
+		if !start.Equal(test.wantStart) || !end.Equal(test.wantEnd) {
+			t.Errorf("%v.Interval(%q) = %v, %v, want %v, %v", test.have, test.tz, start, end, test.wantStart, test.wantEnd)
+		}
+	}
+}
+
+func TestParseUTC(t *testing.T) {
+	tests := []struct {
+		date, hhmm string
+		want       time.Time
+		wantErr    bool
+	}{
+		{date: "20230101", hhmm: "0056", want: time.Date(2023, 1, 1, 0, 56, 0, 0, time.UTC)},
+		{date: "2023-07-04", hhmm: "23:59", want: time.Date(2023, 7, 4, 23, 59, 0, 0, time.UTC)},
+		{date: "20230101", hhmm: "2400", wantErr: true},
+		{date: "20230101", hhmm: "", wantErr: true},
+		{date: "", hhmm: "1200", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseUTC(test.date, test.hhmm)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseUTC(%q, %q) error = %v, wantErr %v", test.date, test.hhmm, err, test.wantErr)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("ParseUTC(%q, %q) = %v, want %v", test.date, test.hhmm, got, test.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		have time.Time
+		want string
+	}{
+		{have: time.Time{}, want: ""},
+		{have: time.Date(2023, 1, 1, 0, 56, 0, 0, time.UTC), want: "2023-01-01T00:56:00Z"},
+		{have: time.Date(2023, 1, 1, 16, 56, 0, 0, time.FixedZone("PST", -8*3600)), want: "2023-01-02T00:56:00Z"},
+	}
+
+	for _, test := range tests {
+		if got := FormatTime(test.have); got != test.want {
+			t.Errorf("FormatTime(%v) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
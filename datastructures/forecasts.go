@@ -0,0 +1,133 @@
+package datastructures
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	forecastFields []string
+)
+
+func init() {
+	forecastFields = fields(&ForecastPeriod{})
+}
+
+// How the conditions of a ForecastPeriod apply, as used in TAF reports.
+const (
+	// ForecastChangeBase is the first period of a forecast.
+	ForecastChangeBase = ""
+	// ForecastChangeFrom replaces the prevailing conditions from its start.
+	ForecastChangeFrom = "FM"
+	// ForecastChangeBecoming is a gradual change to the conditions
+	// sometime during the period, which then prevail.
+	ForecastChangeBecoming = "BECMG"
+	// ForecastChangeTemporary is a temporary fluctuation from the
+	// prevailing conditions during the period.
+	ForecastChangeTemporary = "TEMPO"
+	// ForecastChangeProbable is a chance of the conditions during the
+	// period, with the percent given in the Probability.
+	ForecastChangeProbable = "PROB"
+)
+
+// ForecastPeriod holds the conditions forecast for a station for one period of
+// a forecast, such as one of the change groups of a TAF.
+//
+// Times are in UTC, and all values are in SI units, (or the common
+// meteorological ones for them), and are UnsetValue when not forecast.
+// Values which are not forecast in a change period are generally those of
+// the prevailing conditions before it.
+type ForecastPeriod struct {
+	StationID string `beam:"station_id" json:"station_id"`
+	// Source is the dataset the forecast came from, matching the name in
+	// the datasets Attributions.
+	Source string `beam:"source" json:"source"`
+	// Issued is when the forecast was issued.
+	Issued time.Time `beam:"issued" json:"issued"`
+	// Start and End are the period the conditions apply to, with End
+	// exclusive.
+	Start time.Time `beam:"start" json:"start"`
+	End   time.Time `beam:"end" json:"end"`
+	// Change is one of the ForecastChange constants.
+	Change string `beam:"change" json:"change"`
+	// Probability is the percent probability of the conditions, or
+	// UnsetValue if none was given.
+	Probability float64 `beam:"probability" json:"probability"`
+
+	// WindDirectionDeg is the direction the wind is coming from in degrees
+	// clockwise from true north. Calm and variable winds have an unset
+	// direction.
+	WindDirectionDeg float64 `beam:"wind_direction_deg" json:"wind_direction_deg"`
+	WindSpeedMPS     float64 `beam:"wind_speed_mps" json:"wind_speed_mps"`
+	WindGustMPS      float64 `beam:"wind_gust_mps" json:"wind_gust_mps"`
+
+	VisibilityM float64 `beam:"visibility_m" json:"visibility_m"`
+
+	// CloudLayers are the forecast layers from the lowest up.
+	CloudLayers []*CloudLayer `beam:"cloud_layers" json:"cloud_layers"`
+	// CeilingM is the height above ground level in meters of the lowest
+	// broken, overcast or obscured layer.
+	CeilingM float64 `beam:"ceiling_m" json:"ceiling_m"`
+
+	// PresentWeather are the METAR weather codes forecast, e.g. "-RA", "BR".
+	PresentWeather []string `beam:"present_weather" json:"present_weather"`
+
+	// Report is the raw text the forecast was decoded from, if any, kept for
+	// provenance.
+	Report string `beam:"report" json:"report"`
+}
+
+// EmptyForecastPeriod returns a pre-set empty value with the missing sentinel
+// values set on all relevant fields.
+func EmptyForecastPeriod() *ForecastPeriod {
+	return &ForecastPeriod{
+		Probability:      UnsetValue,
+		WindDirectionDeg: UnsetValue,
+		WindSpeedMPS:     UnsetValue,
+		WindGustMPS:      UnsetValue,
+		VisibilityM:      UnsetValue,
+		CeilingM:         UnsetValue,
+	}
+}
+
+func (f *ForecastPeriod) String() string {
+	return f.CSV(",")
+}
+
+// CSV returns this elements values as a CSV string.
+func (f *ForecastPeriod) CSV(delim string) string {
+	return strings.Join(f.ValueColumns(), delim)
+}
+
+// HeaderColumns returns the labels for the columns in this entity.
+func (f *ForecastPeriod) HeaderColumns(prefix string) []string {
+	return prefixLabels(prefix, forecastFields)
+}
+
+// ValueColumns returns the values for this entity as a collection of strings
+// in the same order as the HeaderColumns. Cloud layers are joined with ";"
+// and weather codes with " ".
+func (f *ForecastPeriod) ValueColumns() []string {
+	layers := make([]string, len(f.CloudLayers))
+	for i, l := range f.CloudLayers {
+		layers[i] = l.String()
+	}
+
+	return []string{
+		f.StationID,
+		f.Source,
+		FormatTime(f.Issued),
+		FormatTime(f.Start),
+		FormatTime(f.End),
+		f.Change,
+		floatOrUnsetString(f.Probability),
+		floatOrUnsetString(f.WindDirectionDeg),
+		floatOrUnsetString(f.WindSpeedMPS),
+		floatOrUnsetString(f.WindGustMPS),
+		floatOrUnsetString(f.VisibilityM),
+		strings.Join(layers, ";"),
+		floatOrUnsetString(f.CeilingM),
+		strings.Join(f.PresentWeather, " "),
+		f.Report,
+	}
+}
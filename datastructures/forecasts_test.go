@@ -0,0 +1,50 @@
+package datastructures
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForecastPeriodCSV(t *testing.T) {
+	tests := []struct {
+		have *ForecastPeriod
+		want string
+	}{
+		{
+			have: EmptyForecastPeriod(),
+			want: ",,,,,,-9999,-9999,-9999,-9999,-9999,,-9999,,",
+		},
+		{
+			have: &ForecastPeriod{
+				StationID:        "KSFO",
+				Source:           "TAF",
+				Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+				Start:            time.Date(2023, 1, 1, 20, 0, 0, 0, time.UTC),
+				End:              time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+				Change:           ForecastChangeTemporary,
+				Probability:      30,
+				WindDirectionDeg: 290,
+				WindSpeedMPS:     6,
+				WindGustMPS:      UnsetValue,
+				VisibilityM:      4828,
+				CloudLayers: []*CloudLayer{
+					{Cover: CloudCoverBroken, BaseM: 305},
+				},
+				CeilingM:       305,
+				PresentWeather: []string{"-RA", "BR"},
+				Report:         "TAF KSFO 011720Z",
+			},
+			want: "KSFO,TAF,2023-01-01T17:20:00Z,2023-01-01T20:00:00Z,2023-01-02T00:00:00Z,TEMPO,30.00,290.00,6.00,-9999,4828.00," +
+				"BKN:305,305.00,-RA BR,TAF KSFO 011720Z",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.CSV(","); got != test.want {
+			t.Errorf("CSV(%v) = %q, want %q", test.have, got, test.want)
+		}
+		if got, want := len(test.have.ValueColumns()), len(test.have.HeaderColumns("")); got != want {
+			t.Errorf("len(ValueColumns()) = %d, want %d", got, want)
+		}
+	}
+}
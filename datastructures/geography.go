@@ -61,11 +61,21 @@ type Geography struct {
 
 	ElevationMeters int32 `beam:"elevation_meters" json:"elevation_meters"`
 
+	// LandUse and LocationSetting describe the area around the entity as
+	// given by the source, e.g. "RESIDENTIAL" and "SUBURBAN" for EPA AQS.
+	LandUse         string `beam:"land_use" json:"land_use"`
+	LocationSetting string `beam:"location_setting" json:"location_setting"`
+
 	// Locations using other geographic systems for working with earth locations.
 
 	// S2CellID is the s2geometry.io CellID for the entity.
 	S2CellID uint64 `beam:"s2_cell_id" json:"s2_cell_id"`
-	// TODO(rsned): Add H3 Geo, Plus Codes, GeoHash, and others.
+	// GeoHash is the geohash.org encoding of the entities location.
+	GeoHash string `beam:"geohash" json:"geohash"`
+	// PlusCode is the Open Location Code (plus.codes) for the entity.
+	PlusCode string `beam:"plus_code" json:"plus_code"`
+	// H3Cell is the h3geo.org resolution 15 cell index for the entity.
+	H3Cell uint64 `beam:"h3_cell" json:"h3_cell"`
 
 	// Timezone is a TZData string like "PST8PDT", "AEST", "Etc/GMT-13",
 	// "US/Pacific", or "America/Los_Angeles". No explicit offsets are stored
@@ -90,6 +100,9 @@ func (g *Geography) HeaderColumns(prefix string) []string {
 	return prefixLabels(prefix, geographyFields)
 }
 
+// ValueColumns returns the Geography fields in HeaderColumns order. The S2 and
+// H3 cell IDs are both written as 0x-prefixed lowercase hex so the two columns
+// read alike and parse back with strconv.ParseUint(s, 0, 64).
 func (g *Geography) ValueColumns() []string {
 	return []string{
 		g.Continent,
@@ -109,7 +122,12 @@ func (g *Geography) ValueColumns() []string {
 		fmt.Sprintf("%d", g.LngE7),
 		g.Datum,
 		fmt.Sprintf("%d", g.ElevationMeters),
+		g.LandUse,
+		g.LocationSetting,
 		fmt.Sprintf("0x%x", g.S2CellID),
+		g.GeoHash,
+		g.PlusCode,
+		fmt.Sprintf("0x%x", g.H3Cell),
 		g.Timezone,
 	}
 }
@@ -3,6 +3,7 @@ package datastructures
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -51,3 +52,13 @@ func floatOrUnsetString(v float64) string {
 	}
 	return fmt.Sprintf("%0.2f", v)
 }
+
+// exactFloatOrUnsetString is like floatOrUnsetString, but keeps all of the
+// significant digits, for values such as pollutant concentrations in ppm
+// which are too small to round to two decimal places.
+func exactFloatOrUnsetString(v float64) string {
+	if v == UnsetValue {
+		return UnsetValueString
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
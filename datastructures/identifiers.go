@@ -1,6 +1,7 @@
 package datastructures
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -27,22 +28,48 @@ type Identifiers struct {
 	GhcnID    string `beam:"ghcn_id" json:"ghcn_id"`
 	GhcnIDAlt string `beam:"ghcn_id_alt" json:"ghcn_id_alt"`
 
+	// UsafID and WbanID are the US Air Force and Weather Bureau Army Navy
+	// identifiers used together to key the NOAA ISD family of datasets.
+	UsafID string `beam:"usaf_id" json:"usaf_id"`
+	WbanID string `beam:"wban_id" json:"wban_id"`
+
 	IATA string `beam:"iata" json:"iata"`
 	ICAO string `beam:"icao" json:"icao"`
 
 	// RegionalAviationCodes is a map of ISO 3166-1 region code to the aviation
 	// code from that regions air authority.
 	// e.g., US => "SFO"
-	RegionalAviationCodes map[string]string `beam:"regional_aviation_codes" json:regional_aviation_codes"`
+	RegionalAviationCodes map[string]string `beam:"regional_aviation_codes" json:"regional_aviation_codes"`
 
 	// RegionalSpecificIDs is a map of ISO 3166-1 region codes to the collection
 	// of identifiers assigned by that regions authority.
 	// e.g., US => [EPA:11432 ICOADS:3312 HCDN:AL293]
+	//
+	// The collection is kept as a sorted, space separated set, so use
+	// AddRegionalID and RegionalIDsIn rather than setting it directly.
 	RegionalIDs map[string]string `beam:"regional_ids" json:"regional_ids"`
 
 	// TODO(rsned): Add more identifiers.
 }
 
+// AddRegionalID adds the identifier to the collection for the ISO 3166-1
+// region, keeping any identifiers it already has.
+func (i *Identifiers) AddRegionalID(region, id string) {
+	if region == "" || id == "" {
+		return
+	}
+	if i.RegionalIDs == nil {
+		i.RegionalIDs = map[string]string{}
+	}
+	ids := append(i.RegionalIDsIn(region), strings.Fields(id)...)
+	i.RegionalIDs[region] = strings.Join(AddToSet(nil, ids...), " ")
+}
+
+// RegionalIDsIn returns the identifiers for the ISO 3166-1 region, sorted.
+func (i *Identifiers) RegionalIDsIn(region string) []string {
+	return strings.Fields(i.RegionalIDs[region])
+}
+
 func (i *Identifiers) String() string {
 	return i.CSV(",")
 }
@@ -66,9 +93,27 @@ func (i *Identifiers) ValueColumns() []string {
 		i.WmoID,
 		i.GhcnID,
 		i.GhcnIDAlt,
-		i.ICAO,
+		i.UsafID,
+		i.WbanID,
 		i.IATA,
-		"map of regional airport codes",
-		"map of regional ids",
+		i.ICAO,
+		mapString(i.RegionalAviationCodes),
+		mapString(i.RegionalIDs),
+	}
+}
+
+// mapString returns the given map as a single value suitable for using in one
+// CSV column, as key=value pairs sorted by key.
+func mapString(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + m[k]
 	}
+	return strings.Join(parts, ";")
 }
@@ -0,0 +1,58 @@
+package datastructures
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAddRegionalID(t *testing.T) {
+	tests := []struct {
+		have   map[string]string
+		region string
+		id     string
+		want   map[string]string
+	}{
+		{
+			have:   nil,
+			region: "US",
+			id:     "EPA:06-075-0005",
+			want:   map[string]string{"US": "EPA:06-075-0005"},
+		},
+		{
+			// Other IDs in the region are kept, not overwritten.
+			have:   map[string]string{"US": "HCDN:AL293", "CA": "EPA:CC-040-0005"},
+			region: "US",
+			id:     "EPA:06-075-0005",
+			want:   map[string]string{"US": "EPA:06-075-0005 HCDN:AL293", "CA": "EPA:CC-040-0005"},
+		},
+		{
+			// Adding an ID already in the region is a no-op.
+			have:   map[string]string{"US": "EPA:06-075-0005 HCDN:AL293"},
+			region: "US",
+			id:     "HCDN:AL293",
+			want:   map[string]string{"US": "EPA:06-075-0005 HCDN:AL293"},
+		},
+		{
+			// Adding a collection merges the two sets.
+			have:   map[string]string{"US": "ICOADS:3312"},
+			region: "US",
+			id:     "HCDN:AL293 EPA:11432",
+			want:   map[string]string{"US": "EPA:11432 HCDN:AL293 ICOADS:3312"},
+		},
+		{
+			have:   nil,
+			region: "US",
+			id:     "",
+			want:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		ids := &Identifiers{RegionalIDs: test.have}
+		ids.AddRegionalID(test.region, test.id)
+		if diff := cmp.Diff(test.want, ids.RegionalIDs); diff != "" {
+			t.Errorf("AddRegionalID(%q, %q) to %v diff (-want +got):\n%s", test.region, test.id, test.have, diff)
+		}
+	}
+}
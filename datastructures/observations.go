@@ -1,7 +1,9 @@
 package datastructures
 
 import (
+	"fmt"
 	"strings"
+	"time"
 )
 
 var (
@@ -12,25 +14,158 @@ func init() {
 	obsFields = fields(&Observation{})
 }
 
+// Sky cover amounts for a CloudLayer, as used in METAR reports.
+const (
+	CloudCoverClear     = "CLR" // No clouds detected below 12,000ft (ASOS) or clear sky (SKC).
+	CloudCoverFew       = "FEW" // 1-2 oktas.
+	CloudCoverScattered = "SCT" // 3-4 oktas.
+	CloudCoverBroken    = "BKN" // 5-7 oktas.
+	CloudCoverOvercast  = "OVC" // 8 oktas.
+	// CloudCoverObscured is a vertical visibility into an obscured sky, (VV),
+	// where the layer base is the vertical visibility.
+	CloudCoverObscured = "VV"
+)
+
+// CloudLayer is one layer of cloud reported in an Observation.
+type CloudLayer struct {
+	// Cover is one of the CloudCover constants.
+	Cover string `beam:"cover" json:"cover"`
+	// BaseM is the height of the base of the layer above ground level in
+	// meters, or UnsetValue if unknown.
+	BaseM float64 `beam:"base_m" json:"base_m"`
+	// Type is the convective cloud type if given, "CB" or "TCU".
+	Type string `beam:"type" json:"type"`
+}
+
+func (c *CloudLayer) String() string {
+	s := c.Cover
+	if c.BaseM != UnsetValue {
+		s += fmt.Sprintf(":%.0f", c.BaseM)
+	}
+	if c.Type != "" {
+		s += ":" + c.Type
+	}
+	return s
+}
+
+// RunwayVisualRange is the visual range along one runway reported in an
+// Observation.
+type RunwayVisualRange struct {
+	// Runway is the runway designator, e.g. "28L".
+	Runway string `beam:"runway" json:"runway"`
+	// RangeM is the visual range in meters, or the lowest of a variable range.
+	RangeM float64 `beam:"range_m" json:"range_m"`
+	// MaxRangeM is the highest of a variable range in meters, or UnsetValue.
+	MaxRangeM float64 `beam:"max_range_m" json:"max_range_m"`
+	// Limit is "M" when the range is below the lowest value the sensor can
+	// report, "P" when it is above the highest, and otherwise "".
+	Limit string `beam:"limit" json:"limit"`
+	// Trend is "U" for rising, "D" for falling, "N" for no change, or "".
+	Trend string `beam:"trend" json:"trend"`
+}
+
+func (r *RunwayVisualRange) String() string {
+	s := fmt.Sprintf("%s:%s%.0f", r.Runway, r.Limit, r.RangeM)
+	if r.MaxRangeM != UnsetValue {
+		s += fmt.Sprintf("-%.0f", r.MaxRangeM)
+	}
+	if r.Trend != "" {
+		s += ":" + r.Trend
+	}
+	return s
+}
+
 // Observation holds the set of all potentially useful fields at a given point in time.
-// Dates and Times are expected to be in UTC time, adjusted as needed by the importer tools.
+// Times are expected to be in UTC, adjusted as needed by the importer tools.
 // Times are expected to only be at minute level granularity.  No seconds are stored.
 // Any expectations on rounding and window times will be in the accompanying documentation.
 // e.g., If an observation is for a 10 minute period, is the time recorded as 00 or 05, or 09?
+//
+// All values are in SI units, (or the common meteorological ones for them such
+// as hPa and mm), and are UnsetValue when the source did not report them.
 type Observation struct {
-	StationID string
-	Date      string // Date UTC in YYYYMMDD format.
-	Time      string // Time UTC in 24 HR HHMM format.
+	StationID string `beam:"station_id" json:"station_id"`
+	// Source is the dataset the observation came from, matching the name in
+	// the datasets Attributions. e.g. "ISD-Lite"
+	Source string `beam:"source" json:"source"`
+	// License and Citation are the terms of, and the preferred citation for,
+	// the dataset, so that the observation carries them wherever it is
+	// published.
+	License  string `beam:"license" json:"license"`
+	Citation string `beam:"citation" json:"citation"`
+	// Time is the UTC instant of the observation. It is output in the ISO
+	// 8601 TimeLayout, e.g. "2023-01-01T00:56:00Z".
+	Time time.Time `beam:"time" json:"time"`
+
+	TempC     float64 `beam:"temp_c" json:"temp_c"`
+	DewPointC float64 `beam:"dew_point_c" json:"dew_point_c"`
+	// RelativeHumidity is in percent.
+	RelativeHumidity float64 `beam:"relative_humidity" json:"relative_humidity"`
 
-	TempC float64
-	// TODO(rsned): Add additional value types.
+	PressureStationHPa  float64 `beam:"pressure_station_hpa" json:"pressure_station_hpa"`
+	PressureSeaLevelHPa float64 `beam:"pressure_sea_level_hpa" json:"pressure_sea_level_hpa"`
+	// PressureAltimeterHPa is the altimeter setting.
+	PressureAltimeterHPa float64 `beam:"pressure_altimeter_hpa" json:"pressure_altimeter_hpa"`
+
+	// WindDirectionDeg is the direction the wind is coming from in degrees
+	// clockwise from true north. Calm winds have a speed of 0 and an unset
+	// direction.
+	WindDirectionDeg float64 `beam:"wind_direction_deg" json:"wind_direction_deg"`
+	WindSpeedMPS     float64 `beam:"wind_speed_mps" json:"wind_speed_mps"`
+	WindGustMPS      float64 `beam:"wind_gust_mps" json:"wind_gust_mps"`
+
+	VisibilityM float64 `beam:"visibility_m" json:"visibility_m"`
+	// RunwayVisualRanges are the visual ranges reported for each runway.
+	RunwayVisualRanges []*RunwayVisualRange `beam:"runway_visual_ranges" json:"runway_visual_ranges"`
+
+	// SkyCoverOktas is the total sky cover in eighths.
+	SkyCoverOktas float64 `beam:"sky_cover_oktas" json:"sky_cover_oktas"`
+	// CloudLayers are the reported layers from the lowest up.
+	CloudLayers []*CloudLayer `beam:"cloud_layers" json:"cloud_layers"`
+	// CeilingM is the height above ground level in meters of the lowest
+	// broken, overcast or obscured layer.
+	CeilingM float64 `beam:"ceiling_m" json:"ceiling_m"`
+
+	// Precipitation totals for the periods ending at the observation time.
+	Precip1HrMM  float64 `beam:"precip_1hr_mm" json:"precip_1hr_mm"`
+	Precip3HrMM  float64 `beam:"precip_3hr_mm" json:"precip_3hr_mm"`
+	Precip6HrMM  float64 `beam:"precip_6hr_mm" json:"precip_6hr_mm"`
+	Precip24HrMM float64 `beam:"precip_24hr_mm" json:"precip_24hr_mm"`
+
+	// PresentWeather are the METAR present weather codes, e.g. "-RA", "BR".
+	PresentWeather []string `beam:"present_weather" json:"present_weather"`
+
+	SnowDepthMM float64 `beam:"snow_depth_mm" json:"snow_depth_mm"`
+
+	// Report is the raw text the observation was decoded from, if any, such
+	// as a METAR report, kept for provenance.
+	Report string `beam:"report" json:"report"`
+
+	// Flags are the quality flags for the measured values, keyed by field.
+	Flags Flags `beam:"flags" json:"flags"`
 }
 
 // EmptyObservation returns a pre-set empty value with the missing sentinel
 // values set on all relevant fields.
 func EmptyObservation() *Observation {
 	return &Observation{
-		TempC: UnsetValue,
+		TempC:                UnsetValue,
+		DewPointC:            UnsetValue,
+		RelativeHumidity:     UnsetValue,
+		PressureStationHPa:   UnsetValue,
+		PressureSeaLevelHPa:  UnsetValue,
+		PressureAltimeterHPa: UnsetValue,
+		WindDirectionDeg:     UnsetValue,
+		WindSpeedMPS:         UnsetValue,
+		WindGustMPS:          UnsetValue,
+		VisibilityM:          UnsetValue,
+		SkyCoverOktas:        UnsetValue,
+		CeilingM:             UnsetValue,
+		Precip1HrMM:          UnsetValue,
+		Precip3HrMM:          UnsetValue,
+		Precip6HrMM:          UnsetValue,
+		Precip24HrMM:         UnsetValue,
+		SnowDepthMM:          UnsetValue,
 	}
 }
 
@@ -50,12 +185,59 @@ func (o *Observation) HeaderColumns(prefix string) []string {
 }
 
 // ValueColumns returns the values for this entity as a collection of strings
-// in the same order as the HeaderColumns.
+// in the same order as the HeaderColumns. Runway visual ranges and cloud
+// layers are joined with ";" and present weather codes with " ".
 func (o *Observation) ValueColumns() []string {
+	rvrs := make([]string, len(o.RunwayVisualRanges))
+	for i, r := range o.RunwayVisualRanges {
+		rvrs[i] = r.String()
+	}
+	layers := make([]string, len(o.CloudLayers))
+	for i, l := range o.CloudLayers {
+		layers[i] = l.String()
+	}
+
 	return []string{
 		o.StationID,
-		o.Date,
-		o.Time,
+		o.Source,
+		o.License,
+		o.Citation,
+		FormatTime(o.Time),
 		floatOrUnsetString(o.TempC),
+		floatOrUnsetString(o.DewPointC),
+		floatOrUnsetString(o.RelativeHumidity),
+		floatOrUnsetString(o.PressureStationHPa),
+		floatOrUnsetString(o.PressureSeaLevelHPa),
+		floatOrUnsetString(o.PressureAltimeterHPa),
+		floatOrUnsetString(o.WindDirectionDeg),
+		floatOrUnsetString(o.WindSpeedMPS),
+		floatOrUnsetString(o.WindGustMPS),
+		floatOrUnsetString(o.VisibilityM),
+		strings.Join(rvrs, ";"),
+		floatOrUnsetString(o.SkyCoverOktas),
+		strings.Join(layers, ";"),
+		floatOrUnsetString(o.CeilingM),
+		floatOrUnsetString(o.Precip1HrMM),
+		floatOrUnsetString(o.Precip3HrMM),
+		floatOrUnsetString(o.Precip6HrMM),
+		floatOrUnsetString(o.Precip24HrMM),
+		strings.Join(o.PresentWeather, " "),
+		floatOrUnsetString(o.SnowDepthMM),
+		o.Report,
+		o.Flags.String(),
+	}
+}
+
+// Ceiling returns the height of the lowest broken, overcast or obscured
+// layer, or UnsetValue if there is none.
+func Ceiling(layers []*CloudLayer) float64 {
+	for _, l := range layers {
+		switch l.Cover {
+		case CloudCoverBroken, CloudCoverOvercast, CloudCoverObscured:
+			if l.BaseM != UnsetValue {
+				return l.BaseM
+			}
+		}
 	}
+	return UnsetValue
 }
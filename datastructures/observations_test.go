@@ -1,26 +1,88 @@
 package datastructures
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestObservationCSV(t *testing.T) {
 	tests := []struct {
 		have *Observation
 		want string
 	}{
-		//
+		{
+			have: EmptyObservation(),
+			want: ",,,,,-9999,-9999,-9999,-9999,-9999,-9999,-9999,-9999,-9999,-9999,,-9999,,-9999,-9999,-9999,-9999,-9999,,-9999,,",
+		},
 		{
 			have: &Observation{
-				StationID: "",
-				TempC:     -9999,
+				StationID:            "72494023234",
+				Source:               "ISD-Lite",
+				License:              "Public domain",
+				Citation:             "ISD-Lite.",
+				Time:                 time.Date(2023, 1, 1, 0, 56, 0, 0, time.UTC),
+				TempC:                11.1,
+				DewPointC:            8.3,
+				RelativeHumidity:     83,
+				PressureStationHPa:   UnsetValue,
+				PressureSeaLevelHPa:  1015.2,
+				PressureAltimeterHPa: 1015.3,
+				WindDirectionDeg:     UnsetValue,
+				WindSpeedMPS:         0,
+				WindGustMPS:          UnsetValue,
+				VisibilityM:          16093,
+				RunwayVisualRanges: []*RunwayVisualRange{
+					{Runway: "28L", RangeM: 1829, MaxRangeM: UnsetValue, Limit: "P"},
+					{Runway: "10R", RangeM: 305, MaxRangeM: 610, Trend: "U"},
+				},
+				SkyCoverOktas: 8,
+				CloudLayers: []*CloudLayer{
+					{Cover: CloudCoverFew, BaseM: 457},
+					{Cover: CloudCoverBroken, BaseM: 1219, Type: "CB"},
+					{Cover: CloudCoverOvercast, BaseM: UnsetValue},
+				},
+				CeilingM:       1219,
+				Precip1HrMM:    0.3,
+				Precip3HrMM:    UnsetValue,
+				Precip6HrMM:    UnsetValue,
+				Precip24HrMM:   UnsetValue,
+				PresentWeather: []string{"-RA", "BR"},
+				SnowDepthMM:    UnsetValue,
+				Report:         "METAR KSFO 010056Z",
+				Flags: Flags{
+					"TempC": {Quality: QualityPassed, Original: "1"},
+				},
 			},
-			want: ",,,-9999",
+			want: "72494023234,ISD-Lite,Public domain,ISD-Lite.,2023-01-01T00:56:00Z,11.10,8.30,83.00,-9999,1015.20,1015.30,-9999,0.00,-9999,16093.00,28L:P1829;10R:305-610:U,8.00," +
+				"FEW:457;BKN:1219:CB;OVC,1219.00,0.30,-9999,-9999,-9999,-RA BR,-9999,METAR KSFO 010056Z,TempC=passed(1)",
 		},
 	}
 
 	for _, test := range tests {
-
 		if got := test.have.CSV(","); got != test.want {
-			t.Errorf("CSV(%q) = %q, want %q", test.have, got, test.want)
+			t.Errorf("CSV(%v) = %q, want %q", test.have, got, test.want)
+		}
+		if got, want := len(test.have.ValueColumns()), len(test.have.HeaderColumns("")); got != want {
+			t.Errorf("len(ValueColumns()) = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestCeiling(t *testing.T) {
+	tests := []struct {
+		have []*CloudLayer
+		want float64
+	}{
+		{have: nil, want: UnsetValue},
+		{have: []*CloudLayer{{Cover: CloudCoverClear, BaseM: UnsetValue}}, want: UnsetValue},
+		{have: []*CloudLayer{{Cover: CloudCoverFew, BaseM: 300}, {Cover: CloudCoverScattered, BaseM: 600}}, want: UnsetValue},
+		{have: []*CloudLayer{{Cover: CloudCoverScattered, BaseM: 600}, {Cover: CloudCoverBroken, BaseM: 900}, {Cover: CloudCoverOvercast, BaseM: 1200}}, want: 900},
+		{have: []*CloudLayer{{Cover: CloudCoverObscured, BaseM: 60}}, want: 60},
+	}
+
+	for _, test := range tests {
+		if got := Ceiling(test.have); got != test.want {
+			t.Errorf("Ceiling(%v) = %v, want %v", test.have, got, test.want)
 		}
 	}
 }
@@ -0,0 +1,130 @@
+package datastructures
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	pollutantObsFields []string
+)
+
+func init() {
+	pollutantObsFields = fields(&PollutantObservation{})
+}
+
+// PollutantObservation holds one air quality measurement, (a pollutant
+// concentration, or a meteorological value measured alongside them), of one
+// parameter by one monitor at a site.
+//
+// Unlike Observation, the value is kept in the units the source reported it
+// in, as the concentrations are compared against standards in those units.
+// Values are UnsetValue when the source did not report them.
+type PollutantObservation struct {
+	StationID string `beam:"station_id" json:"station_id"`
+	// Source is the dataset the observation came from, matching the name in
+	// the datasets Attributions. e.g. "EPA AQS"
+	Source string `beam:"source" json:"source"`
+	// License and Citation are the terms of, and the preferred citation for,
+	// the dataset, so that the observation carries them wherever it is
+	// published.
+	License  string `beam:"license" json:"license"`
+	Citation string `beam:"citation" json:"citation"`
+	// Time is the UTC start of the sample period.
+	Time time.Time `beam:"time" json:"time"`
+
+	// ParameterCode is the source specific code for what was measured, e.g.
+	// "44201" for ozone in EPA AQS.
+	ParameterCode string `beam:"parameter_code" json:"parameter_code"`
+	ParameterName string `beam:"parameter_name" json:"parameter_name"`
+	// POC, (Parameter Occurrence Code), tells apart multiple monitors for the
+	// same parameter at a site.
+	POC int32 `beam:"poc" json:"poc"`
+	// MethodCode is the source specific code for the sampling and analysis
+	// method used.
+	MethodCode string `beam:"method_code" json:"method_code"`
+
+	// SampleDuration is the length of time each sample covers as given by the
+	// source, e.g. "1 HOUR", "8-HR RUN AVG BEGIN HOUR" or "24 HOUR".
+	SampleDuration string `beam:"sample_duration" json:"sample_duration"`
+	// PollutantStandard is the standard the summary was computed for, if
+	// any, e.g. "Ozone 8-hour 2015".
+	PollutantStandard string `beam:"pollutant_standard" json:"pollutant_standard"`
+
+	// Units is the units of Value and MaxValue, e.g. "Parts per million".
+	Units string `beam:"units" json:"units"`
+	// Value is the sample measurement, or the mean of the samples for
+	// summaries.
+	Value float64 `beam:"value" json:"value"`
+	// MaxValue and MaxTime are the highest sample in a summary and the UTC
+	// start of its sample period.
+	MaxValue float64   `beam:"max_value" json:"max_value"`
+	MaxTime  time.Time `beam:"max_time" json:"max_time"`
+	// ObservationCount and ObservationPercent are the number of samples a
+	// summary was computed from, and that as a percent of those scheduled.
+	ObservationCount   int32   `beam:"observation_count" json:"observation_count"`
+	ObservationPercent float64 `beam:"observation_percent" json:"observation_percent"`
+
+	// AQI is the Air Quality Index for the value, for the parameters which
+	// have one.
+	AQI float64 `beam:"aqi" json:"aqi"`
+
+	// Qualifiers are the source specific codes qualifying the value, such as
+	// exceptional events or quality control notes.
+	Qualifiers []string `beam:"qualifiers" json:"qualifiers"`
+}
+
+// EmptyPollutantObservation returns a pre-set empty value with the missing
+// sentinel values set on all relevant fields.
+func EmptyPollutantObservation() *PollutantObservation {
+	return &PollutantObservation{
+		POC:                UnsetValue,
+		Value:              UnsetValue,
+		MaxValue:           UnsetValue,
+		ObservationCount:   UnsetValue,
+		ObservationPercent: UnsetValue,
+		AQI:                UnsetValue,
+	}
+}
+
+func (p *PollutantObservation) String() string {
+	return p.CSV(",")
+}
+
+// CSV returns this elements values as a CSV string.
+func (p *PollutantObservation) CSV(delim string) string {
+	return strings.Join(p.ValueColumns(), delim)
+}
+
+// HeaderColumns returns the labels for the columns in this entity.
+func (p *PollutantObservation) HeaderColumns(prefix string) []string {
+	// TODO(rsned): Cache the list by prefix to save on redundant work.
+	return prefixLabels(prefix, pollutantObsFields)
+}
+
+// ValueColumns returns the values for this entity as a collection of strings
+// in the same order as the HeaderColumns. Qualifiers are joined with " ".
+func (p *PollutantObservation) ValueColumns() []string {
+	return []string{
+		p.StationID,
+		p.Source,
+		p.License,
+		p.Citation,
+		FormatTime(p.Time),
+		p.ParameterCode,
+		p.ParameterName,
+		fmt.Sprintf("%d", p.POC),
+		p.MethodCode,
+		p.SampleDuration,
+		p.PollutantStandard,
+		p.Units,
+		exactFloatOrUnsetString(p.Value),
+		exactFloatOrUnsetString(p.MaxValue),
+		FormatTime(p.MaxTime),
+		fmt.Sprintf("%d", p.ObservationCount),
+		floatOrUnsetString(p.ObservationPercent),
+		floatOrUnsetString(p.AQI),
+		strings.Join(p.Qualifiers, " "),
+	}
+}
@@ -0,0 +1,52 @@
+package datastructures
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollutantObservationCSV(t *testing.T) {
+	tests := []struct {
+		have *PollutantObservation
+		want string
+	}{
+		{
+			have: EmptyPollutantObservation(),
+			want: ",,,,,,,-9999,,,,,-9999,-9999,,-9999,-9999,-9999,",
+		},
+		{
+			have: &PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             "EPA AQS",
+				License:            "Public domain",
+				Citation:           "EPA AQS.",
+				Time:               time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC),
+				ParameterCode:      "44201",
+				ParameterName:      "Ozone",
+				POC:                1,
+				MethodCode:         "087",
+				SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+				PollutantStandard:  "Ozone 8-hour 2015",
+				Units:              "Parts per million",
+				Value:              0.031529,
+				MaxValue:           0.041,
+				MaxTime:            time.Date(2023, 7, 1, 19, 0, 0, 0, time.UTC),
+				ObservationCount:   17,
+				ObservationPercent: 100,
+				AQI:                38,
+				Qualifiers:         []string{"IT", "1"},
+			},
+			want: "06-075-0005,EPA AQS,Public domain,EPA AQS.,2023-07-01T08:00:00Z,44201,Ozone,1,087,8-HR RUN AVG BEGIN HOUR,Ozone 8-hour 2015," +
+				"Parts per million,0.031529,0.041,2023-07-01T19:00:00Z,17,100.00,38.00,IT 1",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.CSV(","); got != test.want {
+			t.Errorf("CSV(%v) = %q, want %q", test.have, got, test.want)
+		}
+		if got, want := len(test.have.ValueColumns()), len(test.have.HeaderColumns("")); got != want {
+			t.Errorf("len(ValueColumns()) = %d, want %d", got, want)
+		}
+	}
+}
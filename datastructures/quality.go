@@ -0,0 +1,80 @@
+package datastructures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Quality is the normalized quality of a single measured value. Each source
+// has its own set of flags, which the importers map onto these.
+type Quality string
+
+const (
+	// QualityPassed is a value that passed all of the sources checks, or a
+	// source that does no checks.
+	QualityPassed Quality = "passed"
+	// QualitySuspect is a value the source has marked as questionable but
+	// not wrong.
+	QualitySuspect Quality = "suspect"
+	// QualityFailed is a value that failed one or more of the sources checks.
+	QualityFailed Quality = "failed"
+	// QualityEstimated is a value that was estimated, interpolated or
+	// otherwise not directly measured.
+	QualityEstimated Quality = "estimated"
+	// QualityTrace is a trace amount of precipitation, too small to measure.
+	// The value is 0.
+	QualityTrace Quality = "trace"
+)
+
+// Flag is the quality of one measured value along with the original flags
+// from the source it was derived from.
+type Flag struct {
+	Quality Quality `beam:"quality" json:"quality"`
+	// Original is the sources flag(s) for the value, as they appear in the
+	// source. e.g. GHCN-D flags are the M, Q and S flags in order.
+	Original string `beam:"original" json:"original"`
+}
+
+func (f *Flag) String() string {
+	if f.Original == "" {
+		return string(f.Quality)
+	}
+	return fmt.Sprintf("%s(%s)", f.Quality, f.Original)
+}
+
+// Flags holds the Flag for each measured value, keyed by the name of the field
+// the value is stored in. e.g. "TempCMax"
+type Flags map[string]*Flag
+
+// Set sets the flag for the field, creating the map if needed.
+func (f *Flags) Set(field string, flag *Flag) {
+	if *f == nil {
+		*f = Flags{}
+	}
+	(*f)[field] = flag
+}
+
+// Quality returns the quality of the field, or "" if it has no flag.
+func (f Flags) Quality(field string) Quality {
+	if flag, ok := f[field]; ok {
+		return flag.Quality
+	}
+	return ""
+}
+
+// String returns the flags as a single value suitable for using in one CSV
+// column, sorted by field. e.g. "PrecipMM=trace(T  );TempCMax=passed"
+func (f Flags) String() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + f[k].String()
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,22 @@
+package datastructures
+
+import "testing"
+
+func TestFlags(t *testing.T) {
+	var f Flags
+	if got := f.Quality("TempC"); got != "" {
+		t.Errorf("Quality() on nil Flags = %q, want \"\"", got)
+	}
+	if got := f.String(); got != "" {
+		t.Errorf("String() on nil Flags = %q, want \"\"", got)
+	}
+
+	f.Set("TempC", &Flag{Quality: QualityFailed, Original: "X"})
+	f.Set("PrecipMM", &Flag{Quality: QualityEstimated})
+	if got := f.Quality("TempC"); got != QualityFailed {
+		t.Errorf("Quality(TempC) = %q, want %q", got, QualityFailed)
+	}
+	if got, want := f.String(), "PrecipMM=estimated;TempC=failed(X)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -38,9 +38,19 @@ type Station struct {
 	// were incorporated to the data about this Station.
 	Attributions *Attributions `beam:"attributions"`
 
-	StartDate   string `beam:"start_date"`
-	EndDate     string `beam:"end_date"`
-	LastUpdated string `beam:"last_updated"`
+	// StartDate and EndDate are the period of record of the station.
+	StartDate Date `beam:"start_date"`
+	EndDate   Date `beam:"end_date"`
+	// LastUpdated is the date the station information was last imported.
+	LastUpdated Date `beam:"last_updated"`
+
+	// Coverage is the period of record for each of the elements this station
+	// has reported, sorted by element.
+	Coverage []*ElementCoverage `beam:"coverage"`
+
+	// Conflicts are the values from other sources which were not used when
+	// the sources records for this station were merged.
+	Conflicts []*Conflict `beam:"conflicts"`
 }
 
 func EmptyStation() *Station {
@@ -66,7 +76,8 @@ func (s *Station) HeaderColumns(prefix string) []string {
 	cols = append(cols, s.Identifiers.HeaderColumns("ids")...)
 	cols = append(cols, s.Geography.HeaderColumns("geo")...)
 	cols = append(cols, s.Attributions.HeaderColumns("attr")...)
-	cols = append(cols, prefixLabels(prefix, stationFields)[2:]...)
+	// Skip over the nested types labels since they were expanded above.
+	cols = append(cols, prefixLabels(prefix, stationFields)[5:]...)
 
 	return cols
 }
@@ -80,9 +91,11 @@ func (s *Station) ValueColumns() []string {
 	cols = append(cols, s.Geography.ValueColumns()...)
 	cols = append(cols, s.Attributions.ValueColumns()...)
 	cols = append(cols, []string{
-		s.StartDate,
-		s.EndDate,
-		s.LastUpdated,
+		s.StartDate.String(),
+		s.EndDate.String(),
+		s.LastUpdated.String(),
+		coverageString(s.Coverage),
+		conflictsString(s.Conflicts),
 	}...)
 	return cols
 }
@@ -0,0 +1,42 @@
+package datastructures
+
+import "testing"
+
+func TestStationColumns(t *testing.T) {
+	s := EmptyStation()
+	s.Identifiers.RegionalAviationCodes = map[string]string{"US": "SFO"}
+	s.Identifiers.AddRegionalID("US", "HCDN:AL293")
+	s.Identifiers.AddRegionalID("US", "EPA:06-075-0005")
+	s.Identifiers.AddRegionalID("CA", "EPA:CC-040-0005")
+	s.Coverage = []*ElementCoverage{
+		{Element: "PRCP", FirstYear: 1893, LastYear: 2023},
+		{Element: "TMAX", FirstYear: 1945, LastYear: 2023},
+	}
+	s.Conflicts = []*Conflict{
+		{Field: "Geography.ElevationMeters", Source: "isd", Value: "4", ChosenSource: "ghcnd", Reason: "precedence"},
+	}
+
+	headers := s.HeaderColumns("")
+	values := s.ValueColumns()
+	if len(headers) != len(values) {
+		t.Errorf("len(HeaderColumns) = %d, len(ValueColumns) = %d, want them to match\nheaders: %v\nvalues: %v",
+			len(headers), len(values), headers, values)
+	}
+
+	columns := map[string]string{}
+	for i, h := range headers {
+		columns[h] = values[i]
+	}
+	if got, want := columns["ids.RegionalAviationCodes"], "US=SFO"; got != want {
+		t.Errorf("RegionalAviationCodes column = %q, want %q", got, want)
+	}
+	if got, want := columns["ids.RegionalIDs"], "CA=EPA:CC-040-0005;US=EPA:06-075-0005 HCDN:AL293"; got != want {
+		t.Errorf("RegionalIDs column = %q, want %q", got, want)
+	}
+	if got, want := values[len(values)-1], "Geography.ElevationMeters:isd=4<ghcnd(precedence)"; got != want {
+		t.Errorf("Conflicts column = %q, want %q", got, want)
+	}
+	if got, want := values[len(values)-2], "PRCP:1893-2023;TMAX:1945-2023"; got != want {
+		t.Errorf("Coverage column = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package aqi
+
+import (
+	"fmt"
+	"math"
+)
+
+// The names of the AQHI health risk categories.
+const (
+	AQHILowRisk      = "Low Risk"
+	AQHIModerateRisk = "Moderate Risk"
+	AQHIHighRisk     = "High Risk"
+	AQHIVeryHighRisk = "Very High Risk"
+)
+
+// AQHI returns the Canadian Air Quality Health Index from the 3 hour average
+// concentrations of ozone and NO2 in ppb, and PM2.5 in µg/m³.
+//
+//	AQHI = 1000/10.4 * ((e^(0.000537*O3) - 1) + (e^(0.000871*NO2) - 1) + (e^(0.000487*PM2.5) - 1))
+//
+// The index is rounded to the nearest whole number, with a minimum of 1. It
+// is open ended, with values above 10 reported as "10+".
+func AQHI(ozonePPB, no2PPB, pm25 float64) (int, error) {
+	for _, c := range []float64{ozonePPB, no2PPB, pm25} {
+		if !present(c) {
+			return 0, fmt.Errorf("aqi: AQHI needs ozone, NO2 and PM2.5")
+		}
+	}
+
+	v := 1000 / 10.4 * ((math.Exp(0.000537*ozonePPB) - 1) +
+		(math.Exp(0.000871*no2PPB) - 1) +
+		(math.Exp(0.000487*pm25) - 1))
+	return max(int(math.Round(v)), 1), nil
+}
+
+// AQHICategory returns the name of the AQHI health risk category for the
+// index.
+func AQHICategory(index int) string {
+	switch {
+	case index <= 3:
+		return AQHILowRisk
+	case index <= 6:
+		return AQHIModerateRisk
+	case index <= 10:
+		return AQHIHighRisk
+	}
+	return AQHIVeryHighRisk
+}
@@ -0,0 +1,55 @@
+package aqi
+
+import (
+	"testing"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestAQHI(t *testing.T) {
+	tests := []struct {
+		ozone, no2, pm25 float64
+		want             int
+		wantErr          bool
+	}{
+		// Bad inputs.
+		{ozone: ds.UnsetValue, no2: 20, pm25: 10, wantErr: true},
+		{ozone: 30, no2: ds.UnsetValue, pm25: 10, wantErr: true},
+		{ozone: 30, no2: 20, pm25: ds.UnsetValue, wantErr: true},
+		// Normal cases.
+		{ozone: 0, no2: 0, pm25: 0, want: 1},
+		{ozone: 30, no2: 20, pm25: 10, want: 4},
+		{ozone: 60, no2: 80, pm25: 100, want: 15},
+	}
+
+	for _, test := range tests {
+		got, err := AQHI(test.ozone, test.no2, test.pm25)
+		if (err != nil) != test.wantErr {
+			t.Errorf("AQHI(%v, %v, %v) error = %v, wantErr %v", test.ozone, test.no2, test.pm25, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("AQHI(%v, %v, %v) = %d, want %d", test.ozone, test.no2, test.pm25, got, test.want)
+		}
+	}
+}
+
+func TestAQHICategory(t *testing.T) {
+	tests := []struct {
+		have int
+		want string
+	}{
+		{have: 1, want: AQHILowRisk},
+		{have: 3, want: AQHILowRisk},
+		{have: 4, want: AQHIModerateRisk},
+		{have: 7, want: AQHIHighRisk},
+		{have: 10, want: AQHIHighRisk},
+		{have: 11, want: AQHIVeryHighRisk},
+	}
+
+	for _, test := range tests {
+		if got := AQHICategory(test.have); got != test.want {
+			t.Errorf("AQHICategory(%d) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
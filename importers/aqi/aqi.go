@@ -0,0 +1,267 @@
+package aqi
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Pollutant is a pollutant measured over one averaging period, as the indexes
+// have separate breakpoints for each averaging period.
+type Pollutant string
+
+// The pollutants and averaging periods used by the indexes.
+const (
+	Ozone8Hour  Pollutant = "O3 8-hour"
+	Ozone1Hour  Pollutant = "O3 1-hour"
+	PM25        Pollutant = "PM2.5 24-hour"
+	PM25OneHour Pollutant = "PM2.5 1-hour"
+	PM10        Pollutant = "PM10 24-hour"
+	PM10OneHour Pollutant = "PM10 1-hour"
+	CO          Pollutant = "CO 8-hour"
+	SO2         Pollutant = "SO2 1-hour"
+	SO224Hour   Pollutant = "SO2 24-hour"
+	NO2         Pollutant = "NO2 1-hour"
+)
+
+// The names of the US AQI categories.
+const (
+	CategoryGood                        = "Good"
+	CategoryModerate                    = "Moderate"
+	CategoryUnhealthyForSensitiveGroups = "Unhealthy for Sensitive Groups"
+	CategoryUnhealthy                   = "Unhealthy"
+	CategoryVeryUnhealthy               = "Very Unhealthy"
+	CategoryHazardous                   = "Hazardous"
+)
+
+// decimals are the number of decimal places the US AQI concentrations are
+// truncated to before finding their breakpoints.
+var decimals = map[Pollutant]int{
+	Ozone8Hour: 3,
+	Ozone1Hour: 3,
+	PM25:       1,
+	PM10:       0,
+	CO:         1,
+	SO2:        0,
+	SO224Hour:  0,
+	NO2:        0,
+}
+
+// Breakpoint is one row of a breakpoint table: the concentrations from Low
+// to High, (inclusive), have the indexes from IndexLow to IndexHigh.
+type Breakpoint struct {
+	Low, High           float64
+	IndexLow, IndexHigh int
+}
+
+// Table is a US AQI breakpoint table.
+type Table struct {
+	// Name identifies the table, e.g. "US EPA 2024".
+	Name string
+	// Breakpoints are the rows of the table for each pollutant, in order.
+	// Pollutants are only defined over part of the index for some averaging
+	// periods, e.g. 8-hour ozone is not used above an AQI of 300.
+	Breakpoints map[Pollutant][]Breakpoint
+}
+
+// US2024 is the US AQI table with the PM2.5 breakpoints revised in 2024, in
+// use since May 6, 2024. It also joins the 301-400 and 401-500 rows for all
+// of the pollutants into one.
+var US2024 = &Table{
+	Name: "US EPA 2024",
+	Breakpoints: map[Pollutant][]Breakpoint{
+		Ozone8Hour: {
+			{0.000, 0.054, 0, 50},
+			{0.055, 0.070, 51, 100},
+			{0.071, 0.085, 101, 150},
+			{0.086, 0.105, 151, 200},
+			{0.106, 0.200, 201, 300},
+		},
+		Ozone1Hour: {
+			{0.125, 0.164, 101, 150},
+			{0.165, 0.204, 151, 200},
+			{0.205, 0.404, 201, 300},
+			{0.405, 0.604, 301, 500},
+		},
+		PM25: {
+			{0.0, 9.0, 0, 50},
+			{9.1, 35.4, 51, 100},
+			{35.5, 55.4, 101, 150},
+			{55.5, 125.4, 151, 200},
+			{125.5, 225.4, 201, 300},
+			{225.5, 325.4, 301, 500},
+		},
+		PM10: {
+			{0, 54, 0, 50},
+			{55, 154, 51, 100},
+			{155, 254, 101, 150},
+			{255, 354, 151, 200},
+			{355, 424, 201, 300},
+			{425, 604, 301, 500},
+		},
+		CO: {
+			{0.0, 4.4, 0, 50},
+			{4.5, 9.4, 51, 100},
+			{9.5, 12.4, 101, 150},
+			{12.5, 15.4, 151, 200},
+			{15.5, 30.4, 201, 300},
+			{30.5, 50.4, 301, 500},
+		},
+		SO2: {
+			{0, 35, 0, 50},
+			{36, 75, 51, 100},
+			{76, 185, 101, 150},
+			{186, 304, 151, 200},
+		},
+		SO224Hour: {
+			{305, 604, 201, 300},
+			{605, 1004, 301, 500},
+		},
+		NO2: {
+			{0, 53, 0, 50},
+			{54, 100, 51, 100},
+			{101, 360, 101, 150},
+			{361, 649, 151, 200},
+			{650, 1249, 201, 300},
+			{1250, 2049, 301, 500},
+		},
+	},
+}
+
+// US2015 is the US AQI table in use from the 2015 ozone revision until May 6,
+// 2024, for checking the AQI reported for data from before then.
+var US2015 = &Table{
+	Name: "US EPA 2015",
+	Breakpoints: map[Pollutant][]Breakpoint{
+		Ozone8Hour: US2024.Breakpoints[Ozone8Hour],
+		Ozone1Hour: {
+			{0.125, 0.164, 101, 150},
+			{0.165, 0.204, 151, 200},
+			{0.205, 0.404, 201, 300},
+			{0.405, 0.504, 301, 400},
+			{0.505, 0.604, 401, 500},
+		},
+		PM25: {
+			{0.0, 12.0, 0, 50},
+			{12.1, 35.4, 51, 100},
+			{35.5, 55.4, 101, 150},
+			{55.5, 150.4, 151, 200},
+			{150.5, 250.4, 201, 300},
+			{250.5, 350.4, 301, 400},
+			{350.5, 500.4, 401, 500},
+		},
+		PM10: {
+			{0, 54, 0, 50},
+			{55, 154, 51, 100},
+			{155, 254, 101, 150},
+			{255, 354, 151, 200},
+			{355, 424, 201, 300},
+			{425, 504, 301, 400},
+			{505, 604, 401, 500},
+		},
+		CO: {
+			{0.0, 4.4, 0, 50},
+			{4.5, 9.4, 51, 100},
+			{9.5, 12.4, 101, 150},
+			{12.5, 15.4, 151, 200},
+			{15.5, 30.4, 201, 300},
+			{30.5, 40.4, 301, 400},
+			{40.5, 50.4, 401, 500},
+		},
+		SO2: US2024.Breakpoints[SO2],
+		SO224Hour: {
+			{305, 604, 201, 300},
+			{605, 804, 301, 400},
+			{805, 1004, 401, 500},
+		},
+		NO2: {
+			{0, 53, 0, 50},
+			{54, 100, 51, 100},
+			{101, 360, 101, 150},
+			{361, 649, 151, 200},
+			{650, 1249, 201, 300},
+			{1250, 1649, 301, 400},
+			{1650, 2049, 401, 500},
+		},
+	},
+}
+
+// us2024Start is when US2024 replaced US2015.
+var us2024Start = time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+
+// TableFor returns the US AQI table in use at the given time.
+func TableFor(t time.Time) *Table {
+	if t.Before(us2024Start) {
+		return US2015
+	}
+	return US2024
+}
+
+// Index returns the AQI for the concentration of the pollutant.
+//
+// The concentration is first truncated to the precision of the table, (e.g.
+// 0.0549 ppm of ozone is 0.054). Small negative concentrations, which
+// instruments can report near zero, are taken as zero. Concentrations above
+// the top of the table give indexes above 500 using its highest row.
+//
+// An error is returned if the pollutant is not in the table, or is not used
+// at the concentration, such as 1-hour ozone below 0.125 ppm or 8-hour ozone
+// above 0.200 ppm. The 1-hour SO2 and 8-hour ozone indexes are not used above
+// 200 and 300, where the 24-hour SO2 and 1-hour ozone ones are used instead.
+func (t *Table) Index(p Pollutant, c float64) (int, error) {
+	bps, ok := t.Breakpoints[p]
+	if !ok {
+		return 0, fmt.Errorf("aqi: %s has no breakpoints for %s", t.Name, p)
+	}
+	if c == ds.UnsetValue || math.IsNaN(c) {
+		return 0, fmt.Errorf("aqi: missing concentration for %s", p)
+	}
+
+	c = truncate(math.Max(c, 0), decimals[p])
+	if c < bps[0].Low {
+		return 0, fmt.Errorf("aqi: %s is not used below %v", p, bps[0].Low)
+	}
+	for _, bp := range bps {
+		if c <= bp.High {
+			return bp.index(c), nil
+		}
+	}
+
+	last := bps[len(bps)-1]
+	if last.IndexHigh < 500 {
+		return 0, fmt.Errorf("aqi: %s is not used above %v", p, last.High)
+	}
+	return last.index(c), nil
+}
+
+// index returns the index for the concentration, interpolated along the row.
+func (bp Breakpoint) index(c float64) int {
+	return int(math.Round(float64(bp.IndexHigh-bp.IndexLow)/(bp.High-bp.Low)*(c-bp.Low))) + bp.IndexLow
+}
+
+// Category returns the name of the US AQI category for the index.
+func Category(index int) string {
+	switch {
+	case index <= 50:
+		return CategoryGood
+	case index <= 100:
+		return CategoryModerate
+	case index <= 150:
+		return CategoryUnhealthyForSensitiveGroups
+	case index <= 200:
+		return CategoryUnhealthy
+	case index <= 300:
+		return CategoryVeryUnhealthy
+	}
+	return CategoryHazardous
+}
+
+// truncate returns v truncated to the given number of decimal places. A small
+// tolerance is added so that values such as 0.055, which are just below their
+// decimal value as a float64, are not truncated down a step.
+func truncate(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Floor(v*scale+1e-6) / scale
+}
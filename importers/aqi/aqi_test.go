@@ -0,0 +1,106 @@
+package aqi
+
+import (
+	"testing"
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		table   *Table
+		p       Pollutant
+		have    float64
+		want    int
+		wantErr bool
+	}{
+		// Bad inputs.
+		{table: US2024, p: PM25OneHour, have: 10, wantErr: true},
+		{table: US2024, p: PM25, have: ds.UnsetValue, wantErr: true},
+		{table: US2024, p: Ozone8Hour, have: 0.201, wantErr: true},
+		{table: US2024, p: Ozone1Hour, have: 0.100, wantErr: true},
+		{table: US2024, p: SO2, have: 305, wantErr: true},
+		{table: US2024, p: SO224Hour, have: 200, wantErr: true},
+		// Breakpoint edges.
+		{table: US2024, p: PM25, have: 0, want: 0},
+		{table: US2024, p: PM25, have: 9.0, want: 50},
+		{table: US2024, p: PM25, have: 9.1, want: 51},
+		{table: US2024, p: PM25, have: 325.4, want: 500},
+		{table: US2024, p: Ozone8Hour, have: 0.055, want: 51},
+		{table: US2024, p: Ozone8Hour, have: 0.200, want: 300},
+		{table: US2024, p: Ozone1Hour, have: 0.125, want: 101},
+		{table: US2024, p: SO224Hour, have: 305, want: 201},
+		{table: US2024, p: NO2, have: 100, want: 100},
+		{table: US2024, p: CO, have: 4.4, want: 50},
+		// Truncation.
+		{table: US2024, p: PM25, have: 9.09, want: 50},
+		{table: US2024, p: Ozone8Hour, have: 0.0549, want: 50},
+		{table: US2024, p: PM10, have: 54.9, want: 50},
+		{table: US2024, p: PM25, have: -0.5, want: 0},
+		// Normal cases.
+		{table: US2024, p: Ozone8Hour, have: 0.041, want: 38},
+		{table: US2024, p: PM25, have: 12.0, want: 56},
+		{table: US2024, p: PM25, have: 38.5, want: 108},
+		{table: US2024, p: PM10, have: 505, want: 390},
+		// Beyond the top of the table.
+		{table: US2024, p: PM25, have: 425.4, want: 699},
+		// The table before the 2024 revision.
+		{table: US2015, p: PM25, have: 12.0, want: 50},
+		{table: US2015, p: PM25, have: 12.1, want: 51},
+		{table: US2015, p: PM25, have: 38.5, want: 108},
+		{table: US2015, p: PM10, have: 505, want: 401},
+		{table: US2015, p: Ozone8Hour, have: 0.041, want: 38},
+	}
+
+	for _, test := range tests {
+		got, err := test.table.Index(test.p, test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s.Index(%s, %v) error = %v, wantErr %v", test.table.Name, test.p, test.have, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s.Index(%s, %v) = %d, want %d", test.table.Name, test.p, test.have, got, test.want)
+		}
+	}
+}
+
+func TestTableFor(t *testing.T) {
+	tests := []struct {
+		have time.Time
+		want *Table
+	}{
+		{have: time.Date(2020, 9, 9, 0, 0, 0, 0, time.UTC), want: US2015},
+		{have: time.Date(2024, 5, 5, 23, 0, 0, 0, time.UTC), want: US2015},
+		{have: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), want: US2024},
+		{have: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), want: US2024},
+	}
+
+	for _, test := range tests {
+		if got := TableFor(test.have); got != test.want {
+			t.Errorf("TableFor(%v) = %s, want %s", test.have, got.Name, test.want.Name)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		have int
+		want string
+	}{
+		{have: 0, want: CategoryGood},
+		{have: 50, want: CategoryGood},
+		{have: 51, want: CategoryModerate},
+		{have: 101, want: CategoryUnhealthyForSensitiveGroups},
+		{have: 151, want: CategoryUnhealthy},
+		{have: 201, want: CategoryVeryUnhealthy},
+		{have: 301, want: CategoryHazardous},
+		{have: 699, want: CategoryHazardous},
+	}
+
+	for _, test := range tests {
+		if got := Category(test.have); got != test.want {
+			t.Errorf("Category(%d) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package aqi
+
+import (
+	"fmt"
+	"math"
+)
+
+// The names of the CAQI bands.
+const (
+	CAQIVeryLow  = "Very Low"
+	CAQILow      = "Low"
+	CAQIMedium   = "Medium"
+	CAQIHigh     = "High"
+	CAQIVeryHigh = "Very High"
+)
+
+// caqiBandWidth is the width of each CAQI band in index points.
+const caqiBandWidth = 25
+
+// caqiGrid are the concentrations, in µg/m³, at the top of each of the CAQI
+// bands below "Very High" for each pollutant, (the indexes 25, 50, 75 and
+// 100).
+var caqiGrid = map[Pollutant][]float64{
+	NO2:         {50, 100, 200, 400},
+	PM10OneHour: {25, 50, 90, 180},
+	PM10:        {15, 30, 50, 100},
+	PM25OneHour: {15, 30, 55, 110},
+	PM25:        {10, 20, 30, 60},
+	Ozone1Hour:  {60, 120, 180, 240},
+	CO:          {5000, 7500, 10000, 20000},
+	SO2:         {50, 100, 350, 500},
+}
+
+// CAQI returns the European Common Air Quality Index for the concentration
+// of the pollutant in µg/m³, (including CO and ozone, unlike the US AQI).
+//
+// The pollutant is one of NO2, Ozone1Hour, SO2, PM10OneHour and
+// PM25OneHour for the hourly index, or PM10 and PM25 for the daily index, or
+// CO for its 8 hour mean. The index is interpolated along the band the
+// concentration is in, and the "Very High" band is open ended, continuing at
+// the rate of the "High" band below it. The CAQI for a site is the highest of
+// the indexes for each of its pollutants.
+func CAQI(p Pollutant, c float64) (int, error) {
+	grid, ok := caqiGrid[p]
+	if !ok {
+		return 0, fmt.Errorf("aqi: CAQI is not defined for %s", p)
+	}
+	if !present(c) {
+		return 0, fmt.Errorf("aqi: missing concentration for %s", p)
+	}
+
+	c = math.Max(c, 0)
+	band, lo := 0, 0.0
+	for band < len(grid)-1 && c > grid[band] {
+		lo = grid[band]
+		band++
+	}
+	return int(math.Round(float64(band*caqiBandWidth) + (c-lo)/(grid[band]-lo)*caqiBandWidth)), nil
+}
+
+// CAQICategory returns the name of the CAQI band for the index.
+func CAQICategory(index int) string {
+	switch {
+	case index < 25:
+		return CAQIVeryLow
+	case index < 50:
+		return CAQILow
+	case index < 75:
+		return CAQIMedium
+	case index <= 100:
+		return CAQIHigh
+	}
+	return CAQIVeryHigh
+}
@@ -0,0 +1,63 @@
+package aqi
+
+import (
+	"testing"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestCAQI(t *testing.T) {
+	tests := []struct {
+		p       Pollutant
+		have    float64
+		want    int
+		wantErr bool
+	}{
+		// Bad inputs.
+		{p: Ozone8Hour, have: 100, wantErr: true},
+		{p: NO2, have: ds.UnsetValue, wantErr: true},
+		// Band edges.
+		{p: NO2, have: 0, want: 0},
+		{p: NO2, have: 50, want: 25},
+		{p: NO2, have: 400, want: 100},
+		// Normal cases.
+		{p: NO2, have: 75, want: 38},
+		{p: PM25, have: 45, want: 88},
+		{p: CO, have: 15000, want: 88},
+		{p: Ozone1Hour, have: 90, want: 38},
+		// The "Very High" band is open ended.
+		{p: NO2, have: 600, want: 125},
+	}
+
+	for _, test := range tests {
+		got, err := CAQI(test.p, test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("CAQI(%s, %v) error = %v, wantErr %v", test.p, test.have, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("CAQI(%s, %v) = %d, want %d", test.p, test.have, got, test.want)
+		}
+	}
+}
+
+func TestCAQICategory(t *testing.T) {
+	tests := []struct {
+		have int
+		want string
+	}{
+		{have: 0, want: CAQIVeryLow},
+		{have: 24, want: CAQIVeryLow},
+		{have: 25, want: CAQILow},
+		{have: 50, want: CAQIMedium},
+		{have: 75, want: CAQIHigh},
+		{have: 100, want: CAQIHigh},
+		{have: 101, want: CAQIVeryHigh},
+	}
+
+	for _, test := range tests {
+		if got := CAQICategory(test.have); got != test.want {
+			t.Errorf("CAQICategory(%d) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
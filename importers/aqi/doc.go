@@ -0,0 +1,30 @@
+/*
+Package aqi computes air quality indexes from pollutant concentrations, so that
+an index can be given for sites which only report the concentrations, and the
+index values reported by the sources can be checked.
+
+The US EPA Air Quality Index, (AQI), is computed from the breakpoint tables in
+the EPA Technical Assistance Document for the Reporting of Daily Air Quality.
+The PM2.5 breakpoints were revised in May 2024, so both the current table, (see
+US2024), and the one used before it, (see US2015), are available, with TableFor
+choosing between them by date.
+
+	index, err := aqi.US2024.Index(aqi.PM25, 38.5) // 108, "Unhealthy for Sensitive Groups"
+
+Concentrations are in the units of the EPA tables, which are the units EPA AQS
+reports them in: ppm for ozone and CO, ppb for SO2 and NO2, and µg/m³ for
+particulates. The AQI for a site is the highest of the indexes for each of its
+pollutants.
+
+NowCast gives the concentration to use for a current, hourly, AQI from the
+recent hourly concentrations of PM2.5, PM10 and ozone, weighting the recent
+hours more when the concentrations are changing.
+
+Two other national indexes are available:
+
+  - AQHI, the Canadian Air Quality Health Index, from the 3 hour average
+    concentrations of ozone, NO2 and PM2.5.
+  - CAQI, the European Common Air Quality Index, from hourly or daily
+    concentrations all in µg/m³.
+*/
+package aqi
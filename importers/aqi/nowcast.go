@@ -0,0 +1,78 @@
+package aqi
+
+import (
+	"fmt"
+	"math"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// nowCastHours are the number of recent hours NowCast uses for each
+// pollutant.
+var nowCastHours = map[Pollutant]int{
+	PM25:       12,
+	PM10:       12,
+	Ozone8Hour: 8,
+}
+
+// minNowCastWeight is the smallest weight factor NowCast uses, so that each
+// hour counts at least half as much as the one after it.
+const minNowCastWeight = 0.5
+
+// NowCast returns the NowCast concentration for the pollutant from its hourly
+// concentrations, most recent first, to give a current AQI with Index.
+//
+// The pollutant is PM25 or PM10, which use the last 12 hours, or Ozone8Hour,
+// which uses the last 8. Extra hours are ignored, and missing hours are
+// ds.UnsetValue. At least two of the three most recent hours are needed.
+//
+// Each hour is weighted by w^n, for the hour n hours before the most recent,
+// where w is the ratio of the lowest to the highest concentration, (but no
+// lower than 0.5). Steady concentrations are averaged nearly evenly, and
+// changing ones are weighted towards the most recent hours.
+func NowCast(p Pollutant, hourly []float64) (float64, error) {
+	hours, ok := nowCastHours[p]
+	if !ok {
+		return 0, fmt.Errorf("aqi: NowCast is not defined for %s", p)
+	}
+	if len(hourly) > hours {
+		hourly = hourly[:hours]
+	}
+
+	recent := 0
+	for i, c := range hourly {
+		if i < 3 && present(c) {
+			recent++
+		}
+	}
+	if recent < 2 {
+		return 0, fmt.Errorf("aqi: NowCast for %s needs at least 2 of the last 3 hours", p)
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, c := range hourly {
+		if present(c) {
+			lo, hi = math.Min(lo, c), math.Max(hi, c)
+		}
+	}
+	w := 1.0
+	if hi > 0 {
+		w = math.Max(lo/hi, minNowCastWeight)
+	}
+
+	var sum, weights float64
+	for i, c := range hourly {
+		if !present(c) {
+			continue
+		}
+		weight := math.Pow(w, float64(i))
+		sum += weight * c
+		weights += weight
+	}
+	return truncate(sum/weights, decimals[p]), nil
+}
+
+// present reports if the hourly concentration was measured.
+func present(c float64) bool {
+	return c != ds.UnsetValue && !math.IsNaN(c)
+}
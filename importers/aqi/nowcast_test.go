@@ -0,0 +1,49 @@
+package aqi
+
+import (
+	"testing"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestNowCast(t *testing.T) {
+	const unset = ds.UnsetValue
+	steady := []float64{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10}
+
+	tests := []struct {
+		p       Pollutant
+		have    []float64
+		want    float64
+		wantErr bool
+	}{
+		// Bad inputs.
+		{p: CO, have: steady, wantErr: true},
+		{p: PM25, have: nil, wantErr: true},
+		{p: PM25, have: []float64{unset, unset, 10, 10}, wantErr: true},
+		{p: PM25, have: []float64{10}, wantErr: true},
+		// Normal cases.
+		{p: PM25, have: steady, want: 10},
+		{p: PM25, have: []float64{0, 0, 0}, want: 0},
+		{p: PM25, have: []float64{12, 10, 8}, want: 10.5},
+		// The weight factor is no lower than 0.5.
+		{p: PM25, have: []float64{20, 10}, want: 16.6},
+		{p: PM25, have: []float64{40, 10}, want: 30},
+		// Missing hours are skipped.
+		{p: PM25, have: []float64{unset, 20, 10}, want: 16.6},
+		// PM10 is truncated to whole µg/m³.
+		{p: PM10, have: []float64{50.7, 40}, want: 45},
+		// Ozone only uses the last 8 hours.
+		{p: Ozone8Hour, have: []float64{0.040, 0.040, 0.040, 0.040, 0.040, 0.040, 0.040, 0.040, 1.0}, want: 0.040},
+	}
+
+	for _, test := range tests {
+		got, err := NowCast(test.p, test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("NowCast(%s, %v) error = %v, wantErr %v", test.p, test.have, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("NowCast(%s, %v) = %v, want %v", test.p, test.have, got, test.want)
+		}
+	}
+}
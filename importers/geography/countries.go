@@ -0,0 +1,649 @@
+package geography
+
+import (
+	"strings"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Continent names as stored in Geography.Continent.
+const (
+	ContinentAfrica       = "Africa"
+	ContinentAntarctica   = "Antarctica"
+	ContinentAsia         = "Asia"
+	ContinentEurope       = "Europe"
+	ContinentNorthAmerica = "North America"
+	ContinentOceania      = "Oceania"
+	ContinentSouthAmerica = "South America"
+)
+
+// MetaRegion values as stored in Geography.MetaRegion.
+const (
+	MetaRegionNA   = "NA"
+	MetaRegionSA   = "SA"
+	MetaRegionEMEA = "EMEA"
+	MetaRegionAPAC = "APAC"
+)
+
+// Region holds the ISO 3166-1 info for a country or territory along with the
+// larger groupings it falls into.
+type Region struct {
+	// Code is the ISO 3166-1 Alpha-2 Region Code.
+	Code string
+	// Name is the ISO 3166-1 English Display name.
+	Name       string
+	Continent  string
+	MetaRegion string
+}
+
+// Apply sets the region level fields on the given Geography.
+func (r *Region) Apply(g *ds.Geography) {
+	g.RegionCode = r.Code
+	g.RegionName = r.Name
+	g.Continent = r.Continent
+	g.MetaRegion = r.MetaRegion
+}
+
+// RegionForCode returns the Region for the given ISO 3166-1 Alpha-2 code.
+func RegionForCode(code string) (*Region, bool) {
+	r, ok := regions[strings.ToUpper(strings.TrimSpace(code))]
+	return r, ok
+}
+
+// RegionForFIPS returns the Region for the given FIPS 10-4 country code, as used
+// by NOAA in GHCN IDs and station lists.
+//
+// Some FIPS codes are for places that do not have their own ISO 3166-1 code (e.g.,
+// Coral Sea Islands, Wake Island) and are returned as part of their parent ISO
+// region. FIPS codes with no ISO equivalent at all (e.g., Spratly Islands) are
+// not found.
+func RegionForFIPS(fips string) (*Region, bool) {
+	code, ok := fipsToISO[strings.ToUpper(strings.TrimSpace(fips))]
+	if !ok {
+		return nil, false
+	}
+	return RegionForCode(code)
+}
+
+// metaRegion returns the MetaRegion for the given region code and continent.
+// Mostly this is driven by the continent, but the Middle East, Caucasus, and
+// Central Asia are grouped into EMEA rather than APAC.
+func metaRegion(code, continent string) string {
+	switch continent {
+	case ContinentNorthAmerica:
+		return MetaRegionNA
+	case ContinentSouthAmerica:
+		return MetaRegionSA
+	case ContinentEurope, ContinentAfrica:
+		return MetaRegionEMEA
+	case ContinentAsia:
+		if emeaAsia[code] {
+			return MetaRegionEMEA
+		}
+		return MetaRegionAPAC
+	case ContinentOceania:
+		return MetaRegionAPAC
+	}
+	return ""
+}
+
+// emeaAsia is the set of Asian regions that fall into EMEA.
+var emeaAsia = map[string]bool{
+	"AE": true,
+	"AM": true,
+	"AZ": true,
+	"BH": true,
+	"GE": true,
+	"IL": true,
+	"IQ": true,
+	"IR": true,
+	"JO": true,
+	"KG": true,
+	"KW": true,
+	"KZ": true,
+	"LB": true,
+	"OM": true,
+	"PS": true,
+	"QA": true,
+	"SA": true,
+	"SY": true,
+	"TJ": true,
+	"TM": true,
+	"TR": true,
+	"UZ": true,
+	"YE": true,
+}
+
+// regions is the set of all ISO 3166-1 regions keyed by Alpha-2 code. Kosovo is
+// included using its commonly used user-assigned code XK.
+var regions = func() map[string]*Region {
+	m := make(map[string]*Region, len(isoRegions))
+	for _, r := range isoRegions {
+		r.MetaRegion = metaRegion(r.Code, r.Continent)
+		m[r.Code] = r
+	}
+	return m
+}()
+
+var isoRegions = []*Region{
+	{Code: "AD", Name: "Andorra", Continent: ContinentEurope},
+	{Code: "AE", Name: "United Arab Emirates", Continent: ContinentAsia},
+	{Code: "AF", Name: "Afghanistan", Continent: ContinentAsia},
+	{Code: "AG", Name: "Antigua and Barbuda", Continent: ContinentNorthAmerica},
+	{Code: "AI", Name: "Anguilla", Continent: ContinentNorthAmerica},
+	{Code: "AL", Name: "Albania", Continent: ContinentEurope},
+	{Code: "AM", Name: "Armenia", Continent: ContinentAsia},
+	{Code: "AO", Name: "Angola", Continent: ContinentAfrica},
+	{Code: "AQ", Name: "Antarctica", Continent: ContinentAntarctica},
+	{Code: "AR", Name: "Argentina", Continent: ContinentSouthAmerica},
+	{Code: "AS", Name: "American Samoa", Continent: ContinentOceania},
+	{Code: "AT", Name: "Austria", Continent: ContinentEurope},
+	{Code: "AU", Name: "Australia", Continent: ContinentOceania},
+	{Code: "AW", Name: "Aruba", Continent: ContinentNorthAmerica},
+	{Code: "AX", Name: "Åland Islands", Continent: ContinentEurope},
+	{Code: "AZ", Name: "Azerbaijan", Continent: ContinentAsia},
+	{Code: "BA", Name: "Bosnia and Herzegovina", Continent: ContinentEurope},
+	{Code: "BB", Name: "Barbados", Continent: ContinentNorthAmerica},
+	{Code: "BD", Name: "Bangladesh", Continent: ContinentAsia},
+	{Code: "BE", Name: "Belgium", Continent: ContinentEurope},
+	{Code: "BF", Name: "Burkina Faso", Continent: ContinentAfrica},
+	{Code: "BG", Name: "Bulgaria", Continent: ContinentEurope},
+	{Code: "BH", Name: "Bahrain", Continent: ContinentAsia},
+	{Code: "BI", Name: "Burundi", Continent: ContinentAfrica},
+	{Code: "BJ", Name: "Benin", Continent: ContinentAfrica},
+	{Code: "BL", Name: "Saint Barthélemy", Continent: ContinentNorthAmerica},
+	{Code: "BM", Name: "Bermuda", Continent: ContinentNorthAmerica},
+	{Code: "BN", Name: "Brunei Darussalam", Continent: ContinentAsia},
+	{Code: "BO", Name: "Bolivia", Continent: ContinentSouthAmerica},
+	{Code: "BQ", Name: "Bonaire, Sint Eustatius and Saba", Continent: ContinentNorthAmerica},
+	{Code: "BR", Name: "Brazil", Continent: ContinentSouthAmerica},
+	{Code: "BS", Name: "Bahamas", Continent: ContinentNorthAmerica},
+	{Code: "BT", Name: "Bhutan", Continent: ContinentAsia},
+	{Code: "BV", Name: "Bouvet Island", Continent: ContinentAntarctica},
+	{Code: "BW", Name: "Botswana", Continent: ContinentAfrica},
+	{Code: "BY", Name: "Belarus", Continent: ContinentEurope},
+	{Code: "BZ", Name: "Belize", Continent: ContinentNorthAmerica},
+	{Code: "CA", Name: "Canada", Continent: ContinentNorthAmerica},
+	{Code: "CC", Name: "Cocos (Keeling) Islands", Continent: ContinentAsia},
+	{Code: "CD", Name: "Congo, Democratic Republic of the", Continent: ContinentAfrica},
+	{Code: "CF", Name: "Central African Republic", Continent: ContinentAfrica},
+	{Code: "CG", Name: "Congo", Continent: ContinentAfrica},
+	{Code: "CH", Name: "Switzerland", Continent: ContinentEurope},
+	{Code: "CI", Name: "Côte d'Ivoire", Continent: ContinentAfrica},
+	{Code: "CK", Name: "Cook Islands", Continent: ContinentOceania},
+	{Code: "CL", Name: "Chile", Continent: ContinentSouthAmerica},
+	{Code: "CM", Name: "Cameroon", Continent: ContinentAfrica},
+	{Code: "CN", Name: "China", Continent: ContinentAsia},
+	{Code: "CO", Name: "Colombia", Continent: ContinentSouthAmerica},
+	{Code: "CR", Name: "Costa Rica", Continent: ContinentNorthAmerica},
+	{Code: "CU", Name: "Cuba", Continent: ContinentNorthAmerica},
+	{Code: "CV", Name: "Cabo Verde", Continent: ContinentAfrica},
+	{Code: "CW", Name: "Curaçao", Continent: ContinentNorthAmerica},
+	{Code: "CX", Name: "Christmas Island", Continent: ContinentAsia},
+	{Code: "CY", Name: "Cyprus", Continent: ContinentEurope},
+	{Code: "CZ", Name: "Czechia", Continent: ContinentEurope},
+	{Code: "DE", Name: "Germany", Continent: ContinentEurope},
+	{Code: "DJ", Name: "Djibouti", Continent: ContinentAfrica},
+	{Code: "DK", Name: "Denmark", Continent: ContinentEurope},
+	{Code: "DM", Name: "Dominica", Continent: ContinentNorthAmerica},
+	{Code: "DO", Name: "Dominican Republic", Continent: ContinentNorthAmerica},
+	{Code: "DZ", Name: "Algeria", Continent: ContinentAfrica},
+	{Code: "EC", Name: "Ecuador", Continent: ContinentSouthAmerica},
+	{Code: "EE", Name: "Estonia", Continent: ContinentEurope},
+	{Code: "EG", Name: "Egypt", Continent: ContinentAfrica},
+	{Code: "EH", Name: "Western Sahara", Continent: ContinentAfrica},
+	{Code: "ER", Name: "Eritrea", Continent: ContinentAfrica},
+	{Code: "ES", Name: "Spain", Continent: ContinentEurope},
+	{Code: "ET", Name: "Ethiopia", Continent: ContinentAfrica},
+	{Code: "FI", Name: "Finland", Continent: ContinentEurope},
+	{Code: "FJ", Name: "Fiji", Continent: ContinentOceania},
+	{Code: "FK", Name: "Falkland Islands (Malvinas)", Continent: ContinentSouthAmerica},
+	{Code: "FM", Name: "Micronesia", Continent: ContinentOceania},
+	{Code: "FO", Name: "Faroe Islands", Continent: ContinentEurope},
+	{Code: "FR", Name: "France", Continent: ContinentEurope},
+	{Code: "GA", Name: "Gabon", Continent: ContinentAfrica},
+	{Code: "GB", Name: "United Kingdom", Continent: ContinentEurope},
+	{Code: "GD", Name: "Grenada", Continent: ContinentNorthAmerica},
+	{Code: "GE", Name: "Georgia", Continent: ContinentAsia},
+	{Code: "GF", Name: "French Guiana", Continent: ContinentSouthAmerica},
+	{Code: "GG", Name: "Guernsey", Continent: ContinentEurope},
+	{Code: "GH", Name: "Ghana", Continent: ContinentAfrica},
+	{Code: "GI", Name: "Gibraltar", Continent: ContinentEurope},
+	{Code: "GL", Name: "Greenland", Continent: ContinentNorthAmerica},
+	{Code: "GM", Name: "Gambia", Continent: ContinentAfrica},
+	{Code: "GN", Name: "Guinea", Continent: ContinentAfrica},
+	{Code: "GP", Name: "Guadeloupe", Continent: ContinentNorthAmerica},
+	{Code: "GQ", Name: "Equatorial Guinea", Continent: ContinentAfrica},
+	{Code: "GR", Name: "Greece", Continent: ContinentEurope},
+	{Code: "GS", Name: "South Georgia and the South Sandwich Islands", Continent: ContinentAntarctica},
+	{Code: "GT", Name: "Guatemala", Continent: ContinentNorthAmerica},
+	{Code: "GU", Name: "Guam", Continent: ContinentOceania},
+	{Code: "GW", Name: "Guinea-Bissau", Continent: ContinentAfrica},
+	{Code: "GY", Name: "Guyana", Continent: ContinentSouthAmerica},
+	{Code: "HK", Name: "Hong Kong", Continent: ContinentAsia},
+	{Code: "HM", Name: "Heard Island and McDonald Islands", Continent: ContinentAntarctica},
+	{Code: "HN", Name: "Honduras", Continent: ContinentNorthAmerica},
+	{Code: "HR", Name: "Croatia", Continent: ContinentEurope},
+	{Code: "HT", Name: "Haiti", Continent: ContinentNorthAmerica},
+	{Code: "HU", Name: "Hungary", Continent: ContinentEurope},
+	{Code: "ID", Name: "Indonesia", Continent: ContinentAsia},
+	{Code: "IE", Name: "Ireland", Continent: ContinentEurope},
+	{Code: "IL", Name: "Israel", Continent: ContinentAsia},
+	{Code: "IM", Name: "Isle of Man", Continent: ContinentEurope},
+	{Code: "IN", Name: "India", Continent: ContinentAsia},
+	{Code: "IO", Name: "British Indian Ocean Territory", Continent: ContinentAsia},
+	{Code: "IQ", Name: "Iraq", Continent: ContinentAsia},
+	{Code: "IR", Name: "Iran", Continent: ContinentAsia},
+	{Code: "IS", Name: "Iceland", Continent: ContinentEurope},
+	{Code: "IT", Name: "Italy", Continent: ContinentEurope},
+	{Code: "JE", Name: "Jersey", Continent: ContinentEurope},
+	{Code: "JM", Name: "Jamaica", Continent: ContinentNorthAmerica},
+	{Code: "JO", Name: "Jordan", Continent: ContinentAsia},
+	{Code: "JP", Name: "Japan", Continent: ContinentAsia},
+	{Code: "KE", Name: "Kenya", Continent: ContinentAfrica},
+	{Code: "KG", Name: "Kyrgyzstan", Continent: ContinentAsia},
+	{Code: "KH", Name: "Cambodia", Continent: ContinentAsia},
+	{Code: "KI", Name: "Kiribati", Continent: ContinentOceania},
+	{Code: "KM", Name: "Comoros", Continent: ContinentAfrica},
+	{Code: "KN", Name: "Saint Kitts and Nevis", Continent: ContinentNorthAmerica},
+	{Code: "KP", Name: "Korea, Democratic People's Republic of", Continent: ContinentAsia},
+	{Code: "KR", Name: "Korea, Republic of", Continent: ContinentAsia},
+	{Code: "KW", Name: "Kuwait", Continent: ContinentAsia},
+	{Code: "KY", Name: "Cayman Islands", Continent: ContinentNorthAmerica},
+	{Code: "KZ", Name: "Kazakhstan", Continent: ContinentAsia},
+	{Code: "LA", Name: "Lao People's Democratic Republic", Continent: ContinentAsia},
+	{Code: "LB", Name: "Lebanon", Continent: ContinentAsia},
+	{Code: "LC", Name: "Saint Lucia", Continent: ContinentNorthAmerica},
+	{Code: "LI", Name: "Liechtenstein", Continent: ContinentEurope},
+	{Code: "LK", Name: "Sri Lanka", Continent: ContinentAsia},
+	{Code: "LR", Name: "Liberia", Continent: ContinentAfrica},
+	{Code: "LS", Name: "Lesotho", Continent: ContinentAfrica},
+	{Code: "LT", Name: "Lithuania", Continent: ContinentEurope},
+	{Code: "LU", Name: "Luxembourg", Continent: ContinentEurope},
+	{Code: "LV", Name: "Latvia", Continent: ContinentEurope},
+	{Code: "LY", Name: "Libya", Continent: ContinentAfrica},
+	{Code: "MA", Name: "Morocco", Continent: ContinentAfrica},
+	{Code: "MC", Name: "Monaco", Continent: ContinentEurope},
+	{Code: "MD", Name: "Moldova", Continent: ContinentEurope},
+	{Code: "ME", Name: "Montenegro", Continent: ContinentEurope},
+	{Code: "MF", Name: "Saint Martin (French part)", Continent: ContinentNorthAmerica},
+	{Code: "MG", Name: "Madagascar", Continent: ContinentAfrica},
+	{Code: "MH", Name: "Marshall Islands", Continent: ContinentOceania},
+	{Code: "MK", Name: "North Macedonia", Continent: ContinentEurope},
+	{Code: "ML", Name: "Mali", Continent: ContinentAfrica},
+	{Code: "MM", Name: "Myanmar", Continent: ContinentAsia},
+	{Code: "MN", Name: "Mongolia", Continent: ContinentAsia},
+	{Code: "MO", Name: "Macao", Continent: ContinentAsia},
+	{Code: "MP", Name: "Northern Mariana Islands", Continent: ContinentOceania},
+	{Code: "MQ", Name: "Martinique", Continent: ContinentNorthAmerica},
+	{Code: "MR", Name: "Mauritania", Continent: ContinentAfrica},
+	{Code: "MS", Name: "Montserrat", Continent: ContinentNorthAmerica},
+	{Code: "MT", Name: "Malta", Continent: ContinentEurope},
+	{Code: "MU", Name: "Mauritius", Continent: ContinentAfrica},
+	{Code: "MV", Name: "Maldives", Continent: ContinentAsia},
+	{Code: "MW", Name: "Malawi", Continent: ContinentAfrica},
+	{Code: "MX", Name: "Mexico", Continent: ContinentNorthAmerica},
+	{Code: "MY", Name: "Malaysia", Continent: ContinentAsia},
+	{Code: "MZ", Name: "Mozambique", Continent: ContinentAfrica},
+	{Code: "NA", Name: "Namibia", Continent: ContinentAfrica},
+	{Code: "NC", Name: "New Caledonia", Continent: ContinentOceania},
+	{Code: "NE", Name: "Niger", Continent: ContinentAfrica},
+	{Code: "NF", Name: "Norfolk Island", Continent: ContinentOceania},
+	{Code: "NG", Name: "Nigeria", Continent: ContinentAfrica},
+	{Code: "NI", Name: "Nicaragua", Continent: ContinentNorthAmerica},
+	{Code: "NL", Name: "Netherlands", Continent: ContinentEurope},
+	{Code: "NO", Name: "Norway", Continent: ContinentEurope},
+	{Code: "NP", Name: "Nepal", Continent: ContinentAsia},
+	{Code: "NR", Name: "Nauru", Continent: ContinentOceania},
+	{Code: "NU", Name: "Niue", Continent: ContinentOceania},
+	{Code: "NZ", Name: "New Zealand", Continent: ContinentOceania},
+	{Code: "OM", Name: "Oman", Continent: ContinentAsia},
+	{Code: "PA", Name: "Panama", Continent: ContinentNorthAmerica},
+	{Code: "PE", Name: "Peru", Continent: ContinentSouthAmerica},
+	{Code: "PF", Name: "French Polynesia", Continent: ContinentOceania},
+	{Code: "PG", Name: "Papua New Guinea", Continent: ContinentOceania},
+	{Code: "PH", Name: "Philippines", Continent: ContinentAsia},
+	{Code: "PK", Name: "Pakistan", Continent: ContinentAsia},
+	{Code: "PL", Name: "Poland", Continent: ContinentEurope},
+	{Code: "PM", Name: "Saint Pierre and Miquelon", Continent: ContinentNorthAmerica},
+	{Code: "PN", Name: "Pitcairn", Continent: ContinentOceania},
+	{Code: "PR", Name: "Puerto Rico", Continent: ContinentNorthAmerica},
+	{Code: "PS", Name: "Palestine, State of", Continent: ContinentAsia},
+	{Code: "PT", Name: "Portugal", Continent: ContinentEurope},
+	{Code: "PW", Name: "Palau", Continent: ContinentOceania},
+	{Code: "PY", Name: "Paraguay", Continent: ContinentSouthAmerica},
+	{Code: "QA", Name: "Qatar", Continent: ContinentAsia},
+	{Code: "RE", Name: "Réunion", Continent: ContinentAfrica},
+	{Code: "RO", Name: "Romania", Continent: ContinentEurope},
+	{Code: "RS", Name: "Serbia", Continent: ContinentEurope},
+	{Code: "RU", Name: "Russian Federation", Continent: ContinentEurope},
+	{Code: "RW", Name: "Rwanda", Continent: ContinentAfrica},
+	{Code: "SA", Name: "Saudi Arabia", Continent: ContinentAsia},
+	{Code: "SB", Name: "Solomon Islands", Continent: ContinentOceania},
+	{Code: "SC", Name: "Seychelles", Continent: ContinentAfrica},
+	{Code: "SD", Name: "Sudan", Continent: ContinentAfrica},
+	{Code: "SE", Name: "Sweden", Continent: ContinentEurope},
+	{Code: "SG", Name: "Singapore", Continent: ContinentAsia},
+	{Code: "SH", Name: "Saint Helena, Ascension and Tristan da Cunha", Continent: ContinentAfrica},
+	{Code: "SI", Name: "Slovenia", Continent: ContinentEurope},
+	{Code: "SJ", Name: "Svalbard and Jan Mayen", Continent: ContinentEurope},
+	{Code: "SK", Name: "Slovakia", Continent: ContinentEurope},
+	{Code: "SL", Name: "Sierra Leone", Continent: ContinentAfrica},
+	{Code: "SM", Name: "San Marino", Continent: ContinentEurope},
+	{Code: "SN", Name: "Senegal", Continent: ContinentAfrica},
+	{Code: "SO", Name: "Somalia", Continent: ContinentAfrica},
+	{Code: "SR", Name: "Suriname", Continent: ContinentSouthAmerica},
+	{Code: "SS", Name: "South Sudan", Continent: ContinentAfrica},
+	{Code: "ST", Name: "Sao Tome and Principe", Continent: ContinentAfrica},
+	{Code: "SV", Name: "El Salvador", Continent: ContinentNorthAmerica},
+	{Code: "SX", Name: "Sint Maarten (Dutch part)", Continent: ContinentNorthAmerica},
+	{Code: "SY", Name: "Syrian Arab Republic", Continent: ContinentAsia},
+	{Code: "SZ", Name: "Eswatini", Continent: ContinentAfrica},
+	{Code: "TC", Name: "Turks and Caicos Islands", Continent: ContinentNorthAmerica},
+	{Code: "TD", Name: "Chad", Continent: ContinentAfrica},
+	{Code: "TF", Name: "French Southern Territories", Continent: ContinentAntarctica},
+	{Code: "TG", Name: "Togo", Continent: ContinentAfrica},
+	{Code: "TH", Name: "Thailand", Continent: ContinentAsia},
+	{Code: "TJ", Name: "Tajikistan", Continent: ContinentAsia},
+	{Code: "TK", Name: "Tokelau", Continent: ContinentOceania},
+	{Code: "TL", Name: "Timor-Leste", Continent: ContinentAsia},
+	{Code: "TM", Name: "Turkmenistan", Continent: ContinentAsia},
+	{Code: "TN", Name: "Tunisia", Continent: ContinentAfrica},
+	{Code: "TO", Name: "Tonga", Continent: ContinentOceania},
+	{Code: "TR", Name: "Türkiye", Continent: ContinentAsia},
+	{Code: "TT", Name: "Trinidad and Tobago", Continent: ContinentNorthAmerica},
+	{Code: "TV", Name: "Tuvalu", Continent: ContinentOceania},
+	{Code: "TW", Name: "Taiwan", Continent: ContinentAsia},
+	{Code: "TZ", Name: "Tanzania", Continent: ContinentAfrica},
+	{Code: "UA", Name: "Ukraine", Continent: ContinentEurope},
+	{Code: "UG", Name: "Uganda", Continent: ContinentAfrica},
+	{Code: "UM", Name: "United States Minor Outlying Islands", Continent: ContinentOceania},
+	{Code: "US", Name: "United States", Continent: ContinentNorthAmerica},
+	{Code: "UY", Name: "Uruguay", Continent: ContinentSouthAmerica},
+	{Code: "UZ", Name: "Uzbekistan", Continent: ContinentAsia},
+	{Code: "VA", Name: "Holy See", Continent: ContinentEurope},
+	{Code: "VC", Name: "Saint Vincent and the Grenadines", Continent: ContinentNorthAmerica},
+	{Code: "VE", Name: "Venezuela", Continent: ContinentSouthAmerica},
+	{Code: "VG", Name: "Virgin Islands (British)", Continent: ContinentNorthAmerica},
+	{Code: "VI", Name: "Virgin Islands (U.S.)", Continent: ContinentNorthAmerica},
+	{Code: "VN", Name: "Viet Nam", Continent: ContinentAsia},
+	{Code: "VU", Name: "Vanuatu", Continent: ContinentOceania},
+	{Code: "WF", Name: "Wallis and Futuna", Continent: ContinentOceania},
+	{Code: "WS", Name: "Samoa", Continent: ContinentOceania},
+	{Code: "XK", Name: "Kosovo", Continent: ContinentEurope},
+	{Code: "YE", Name: "Yemen", Continent: ContinentAsia},
+	{Code: "YT", Name: "Mayotte", Continent: ContinentAfrica},
+	{Code: "ZA", Name: "South Africa", Continent: ContinentAfrica},
+	{Code: "ZM", Name: "Zambia", Continent: ContinentAfrica},
+	{Code: "ZW", Name: "Zimbabwe", Continent: ContinentAfrica},
+}
+
+// fipsToISO maps FIPS 10-4 country codes to ISO 3166-1 Alpha-2 codes. It was
+// seeded from the codes in ghcnd-countries.txt (see ghcnd.ParseCountryLine)
+// and extended with the other FIPS 10-4 codes for small islands and territories.
+var fipsToISO = map[string]string{
+	"AA": "AW", // Aruba
+	"AC": "AG", // Antigua and Barbuda
+	"AE": "AE", // United Arab Emirates
+	"AF": "AF", // Afghanistan
+	"AG": "DZ", // Algeria
+	"AJ": "AZ", // Azerbaijan
+	"AL": "AL", // Albania
+	"AM": "AM", // Armenia
+	"AN": "AD", // Andorra
+	"AO": "AO", // Angola
+	"AQ": "AS", // American Samoa
+	"AR": "AR", // Argentina
+	"AS": "AU", // Australia
+	"AT": "AU", // Ashmore and Cartier Islands
+	"AU": "AT", // Austria
+	"AV": "AI", // Anguilla
+	"AY": "AQ", // Antarctica
+	"BA": "BH", // Bahrain
+	"BB": "BB", // Barbados
+	"BC": "BW", // Botswana
+	"BD": "BM", // Bermuda
+	"BE": "BE", // Belgium
+	"BF": "BS", // Bahamas, The
+	"BG": "BD", // Bangladesh
+	"BH": "BZ", // Belize
+	"BK": "BA", // Bosnia and Herzegovina
+	"BL": "BO", // Bolivia
+	"BM": "MM", // Burma
+	"BN": "BJ", // Benin
+	"BO": "BY", // Belarus
+	"BP": "SB", // Solomon Islands
+	"BQ": "UM", // Navassa Island
+	"BR": "BR", // Brazil
+	"BT": "BT", // Bhutan
+	"BU": "BG", // Bulgaria
+	"BV": "BV", // Bouvet Island
+	"BX": "BN", // Brunei
+	"BY": "BI", // Burundi
+	"CA": "CA", // Canada
+	"CB": "KH", // Cambodia
+	"CD": "TD", // Chad
+	"CE": "LK", // Sri Lanka
+	"CF": "CG", // Congo (Brazzaville)
+	"CG": "CD", // Congo (Kinshasa)
+	"CH": "CN", // China
+	"CI": "CL", // Chile
+	"CJ": "KY", // Cayman Islands
+	"CK": "CC", // Cocos (Keeling) Islands
+	"CM": "CM", // Cameroon
+	"CN": "KM", // Comoros
+	"CO": "CO", // Colombia
+	"CQ": "MP", // Northern Mariana Islands
+	"CR": "AU", // Coral Sea Islands
+	"CS": "CR", // Costa Rica
+	"CT": "CF", // Central African Republic
+	"CU": "CU", // Cuba
+	"CV": "CV", // Cape Verde
+	"CW": "CK", // Cook Islands
+	"CY": "CY", // Cyprus
+	"DA": "DK", // Denmark
+	"DJ": "DJ", // Djibouti
+	"DO": "DM", // Dominica
+	"DR": "DO", // Dominican Republic
+	"EC": "EC", // Ecuador
+	"EG": "EG", // Egypt
+	"EI": "IE", // Ireland
+	"EK": "GQ", // Equatorial Guinea
+	"EN": "EE", // Estonia
+	"ER": "ER", // Eritrea
+	"ES": "SV", // El Salvador
+	"ET": "ET", // Ethiopia
+	"EU": "TF", // Europa Island
+	"EZ": "CZ", // Czech Republic
+	"FG": "GF", // French Guiana
+	"FI": "FI", // Finland
+	"FJ": "FJ", // Fiji
+	"FK": "FK", // Falkland Islands (Islas Malvinas)
+	"FM": "FM", // Federated States of Micronesia
+	"FO": "FO", // Faroe Islands
+	"FP": "PF", // French Polynesia
+	"FQ": "UM", // Baker Island
+	"FR": "FR", // France
+	"FS": "TF", // French Southern and Antarctic Lands
+	"GA": "GM", // Gambia, The
+	"GB": "GA", // Gabon
+	"GG": "GE", // Georgia
+	"GH": "GH", // Ghana
+	"GI": "GI", // Gibraltar
+	"GJ": "GD", // Grenada
+	"GK": "GG", // Guernsey
+	"GL": "GL", // Greenland
+	"GM": "DE", // Germany
+	"GO": "TF", // Glorioso Islands
+	"GP": "GP", // Guadeloupe
+	"GQ": "GU", // Guam
+	"GR": "GR", // Greece
+	"GT": "GT", // Guatemala
+	"GV": "GN", // Guinea
+	"GY": "GY", // Guyana
+	"GZ": "PS", // Gaza Strip
+	"HA": "HT", // Haiti
+	"HK": "HK", // Hong Kong
+	"HM": "HM", // Heard Island and McDonald Islands
+	"HO": "HN", // Honduras
+	"HQ": "UM", // Howland Island
+	"HR": "HR", // Croatia
+	"HU": "HU", // Hungary
+	"IC": "IS", // Iceland
+	"ID": "ID", // Indonesia
+	"IM": "IM", // Isle of Man
+	"IN": "IN", // India
+	"IO": "IO", // British Indian Ocean Territory
+	"IR": "IR", // Iran
+	"IS": "IL", // Israel
+	"IT": "IT", // Italy
+	"IV": "CI", // Cote D'Ivoire
+	"IZ": "IQ", // Iraq
+	"JA": "JP", // Japan
+	"JE": "JE", // Jersey
+	"JM": "JM", // Jamaica
+	"JN": "SJ", // Jan Mayen
+	"JO": "JO", // Jordan
+	"JQ": "UM", // Johnston Atoll
+	"JU": "TF", // Juan De Nova Island
+	"KE": "KE", // Kenya
+	"KG": "KG", // Kyrgyzstan
+	"KN": "KP", // Korea, North
+	"KQ": "UM", // Kingman Reef
+	"KR": "KI", // Kiribati
+	"KS": "KR", // Korea, South
+	"KT": "CX", // Christmas Island
+	"KU": "KW", // Kuwait
+	"KV": "XK", // Kosovo
+	"KZ": "KZ", // Kazakhstan
+	"LA": "LA", // Laos
+	"LE": "LB", // Lebanon
+	"LG": "LV", // Latvia
+	"LH": "LT", // Lithuania
+	"LI": "LR", // Liberia
+	"LO": "SK", // Slovakia
+	"LQ": "UM", // Palmyra Atoll
+	"LS": "LI", // Liechtenstein
+	"LT": "LS", // Lesotho
+	"LU": "LU", // Luxembourg
+	"LY": "LY", // Libya
+	"MA": "MG", // Madagascar
+	"MB": "MQ", // Martinique
+	"MC": "MO", // Macau S.A.R
+	"MD": "MD", // Moldova
+	"MF": "YT", // Mayotte
+	"MG": "MN", // Mongolia
+	"MH": "MS", // Montserrat
+	"MI": "MW", // Malawi
+	"MJ": "ME", // Montenegro
+	"MK": "MK", // Macedonia
+	"ML": "ML", // Mali
+	"MN": "MC", // Monaco
+	"MO": "MA", // Morocco
+	"MP": "MU", // Mauritius
+	"MQ": "UM", // Midway Islands
+	"MR": "MR", // Mauritania
+	"MT": "MT", // Malta
+	"MU": "OM", // Oman
+	"MV": "MV", // Maldives
+	"MX": "MX", // Mexico
+	"MY": "MY", // Malaysia
+	"MZ": "MZ", // Mozambique
+	"NC": "NC", // New Caledonia
+	"NE": "NU", // Niue
+	"NF": "NF", // Norfolk Island
+	"NG": "NE", // Niger
+	"NH": "VU", // Vanuatu
+	"NI": "NG", // Nigeria
+	"NL": "NL", // Netherlands
+	"NN": "SX", // Sint Maarten
+	"NO": "NO", // Norway
+	"NP": "NP", // Nepal
+	"NR": "NR", // Nauru
+	"NS": "SR", // Suriname
+	"NT": "CW", // Netherlands Antilles
+	"NU": "NI", // Nicaragua
+	"NZ": "NZ", // New Zealand
+	"OD": "SS", // South Sudan
+	"PA": "PY", // Paraguay
+	"PC": "PN", // Pitcairn Islands
+	"PE": "PE", // Peru
+	"PK": "PK", // Pakistan
+	"PL": "PL", // Poland
+	"PM": "PA", // Panama
+	"PO": "PT", // Portugal
+	"PP": "PG", // Papua New Guinea
+	"PS": "PW", // Palau
+	"PU": "GW", // Guinea-Bissau
+	"QA": "QA", // Qatar
+	"RE": "RE", // Reunion
+	"RI": "RS", // Serbia
+	"RM": "MH", // Marshall Islands
+	"RN": "MF", // Saint Martin
+	"RO": "RO", // Romania
+	"RP": "PH", // Philippines
+	"RQ": "PR", // Puerto Rico
+	"RS": "RU", // Russia
+	"RW": "RW", // Rwanda
+	"SA": "SA", // Saudi Arabia
+	"SB": "PM", // Saint Pierre and Miquelon
+	"SC": "KN", // Saint Kitts and Nevis
+	"SE": "SC", // Seychelles
+	"SF": "ZA", // South Africa
+	"SG": "SN", // Senegal
+	"SH": "SH", // Saint Helena
+	"SI": "SI", // Slovenia
+	"SL": "SL", // Sierra Leone
+	"SM": "SM", // San Marino
+	"SN": "SG", // Singapore
+	"SO": "SO", // Somalia
+	"SP": "ES", // Spain
+	"ST": "LC", // Saint Lucia
+	"SU": "SD", // Sudan
+	"SV": "SJ", // Svalbard
+	"SW": "SE", // Sweden
+	"SX": "GS", // South Georgia and the South Sandwich Islands
+	"SY": "SY", // Syria
+	"SZ": "CH", // Switzerland
+	"TB": "BL", // Saint Barthelemy
+	"TD": "TT", // Trinidad and Tobago
+	"TE": "TF", // Tromelin Island
+	"TH": "TH", // Thailand
+	"TI": "TJ", // Tajikistan
+	"TK": "TC", // Turks and Caicos Islands
+	"TL": "TK", // Tokelau
+	"TN": "TO", // Tonga
+	"TO": "TG", // Togo
+	"TP": "ST", // Sao Tome and Principe
+	"TS": "TN", // Tunisia
+	"TT": "TL", // Timor-Leste
+	"TU": "TR", // Turkey
+	"TV": "TV", // Tuvalu
+	"TW": "TW", // Taiwan
+	"TX": "TM", // Turkmenistan
+	"TZ": "TZ", // Tanzania
+	"UC": "CW", // Curacao
+	"UG": "UG", // Uganda
+	"UK": "GB", // United Kingdom
+	"UP": "UA", // Ukraine
+	"US": "US", // United States
+	"UV": "BF", // Burkina Faso
+	"UY": "UY", // Uruguay
+	"UZ": "UZ", // Uzbekistan
+	"VC": "VC", // Saint Vincent and the Grenadines
+	"VE": "VE", // Venezuela
+	"VI": "VG", // British Virgin Islands
+	"VM": "VN", // Vietnam
+	"VQ": "VI", // Virgin Islands (U.S.)
+	"VT": "VA", // Holy See (Vatican City)
+	"WA": "NA", // Namibia
+	"WE": "PS", // West Bank
+	"WF": "WF", // Wallis and Futuna
+	"WI": "EH", // Western Sahara
+	"WQ": "UM", // Wake Island
+	"WS": "WS", // Samoa
+	"WZ": "SZ", // Swaziland
+	"YM": "YE", // Yemen
+	"ZA": "ZM", // Zambia
+	"ZI": "ZW", // Zimbabwe
+}
@@ -0,0 +1,110 @@
+package geography
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestFIPSCodesHaveRegions(t *testing.T) {
+	for fips, code := range fipsToISO {
+		if _, ok := RegionForCode(code); !ok {
+			t.Errorf("FIPS code %q maps to %q which is not a known region", fips, code)
+		}
+	}
+}
+
+func TestRegionsHaveMetaRegions(t *testing.T) {
+	for code, r := range regions {
+		if r.Continent == ContinentAntarctica {
+			continue
+		}
+		if r.MetaRegion == "" {
+			t.Errorf("region %q (%s) has no MetaRegion", code, r.Name)
+		}
+	}
+}
+
+func TestRegionForFIPS(t *testing.T) {
+	tests := []struct {
+		have   string
+		want   *ds.Geography
+		wantOK bool
+	}{
+		{
+			have: "",
+		},
+		{
+			// Spratly Islands have no ISO code.
+			have: "PG",
+		},
+		{
+			have: "US",
+			want: &ds.Geography{
+				Continent:  "North America",
+				MetaRegion: "NA",
+				RegionCode: "US",
+				RegionName: "United States",
+			},
+			wantOK: true,
+		},
+		{
+			// FIPS and ISO codes that are the same but mean different places.
+			have: "AS",
+			want: &ds.Geography{
+				Continent:  "Oceania",
+				MetaRegion: "APAC",
+				RegionCode: "AU",
+				RegionName: "Australia",
+			},
+			wantOK: true,
+		},
+		{
+			have: "gm",
+			want: &ds.Geography{
+				Continent:  "Europe",
+				MetaRegion: "EMEA",
+				RegionCode: "DE",
+				RegionName: "Germany",
+			},
+			wantOK: true,
+		},
+		{
+			have: "SA",
+			want: &ds.Geography{
+				Continent:  "Asia",
+				MetaRegion: "EMEA",
+				RegionCode: "SA",
+				RegionName: "Saudi Arabia",
+			},
+			wantOK: true,
+		},
+		{
+			have: "AY",
+			want: &ds.Geography{
+				Continent:  "Antarctica",
+				RegionCode: "AQ",
+				RegionName: "Antarctica",
+			},
+			wantOK: true,
+		},
+	}
+
+	for _, test := range tests {
+		r, ok := RegionForFIPS(test.have)
+		if ok != test.wantOK {
+			t.Errorf("RegionForFIPS(%q) = _, %v, want %v", test.have, ok, test.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		got := &ds.Geography{}
+		r.Apply(got)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("RegionForFIPS(%q).Apply() = %+v, want %+v\ndiff: %s", test.have, got, test.want, diff)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+/*
+Package geography holds the tools for normalizing the location information from
+the various data sources into the common forms used in datastructures.Geography.
+
+Each source tends to use its own codes for countries and subdivisions (FIPS 10-4,
+US postal codes, etc.) so the importers use this package to convert them to the
+ISO 3166 forms so that downstream users don't need per source code tables.
+*/
+package geography
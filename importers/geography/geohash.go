@@ -0,0 +1,44 @@
+package geography
+
+// geohashAlphabet is the base32 alphabet used by geohash.org.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashLength is the number of characters to encode points with. At 12
+// characters a geohash cell is about 37mm x 19mm.
+const geohashLength = 12
+
+// geohash returns the geohash.org encoding for the given point with the given
+// number of characters.
+func geohash(lat, lng float64, length int) string {
+	latLo, latHi := -90.0, 90.0
+	lngLo, lngHi := -180.0, 180.0
+
+	code := make([]byte, length)
+	even := true
+	for i := 0; i < length; i++ {
+		ch := 0
+		for bit := 4; bit >= 0; bit-- {
+			// Bits alternate between longitude and latitude, starting with longitude.
+			if even {
+				mid := (lngLo + lngHi) / 2
+				if lng >= mid {
+					ch |= 1 << bit
+					lngLo = mid
+				} else {
+					lngHi = mid
+				}
+			} else {
+				mid := (latLo + latHi) / 2
+				if lat >= mid {
+					ch |= 1 << bit
+					latLo = mid
+				} else {
+					latHi = mid
+				}
+			}
+			even = !even
+		}
+		code[i] = geohashAlphabet[ch]
+	}
+	return string(code)
+}
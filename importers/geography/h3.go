@@ -0,0 +1,811 @@
+package geography
+
+import "math"
+
+// This is a minimal port of the parts of the h3geo.org library needed to
+// compute the cell containing a given point, (latLngToCell). See
+// github.com/uber/h3 for the full library and for more detailed explanations
+// of the steps and tables.
+
+const (
+	// h3Resolution is the resolution points are indexed at. Resolution 15
+	// cells are about 0.9m² so, as with the S2 leaf cell, the cell for any
+	// coarser resolution can be found from it.
+	h3Resolution = 15
+	// h3MaxResolution is the finest resolution H3 defines.
+	h3MaxResolution = 15
+
+	h3NumFaces     = 20
+	h3CellMode     = 1
+	h3ModeOffset   = 59
+	h3ResOffset    = 52
+	h3BCOffset     = 45
+	h3DigitBits    = 3
+	h3DigitMask    = 7
+	h3MaxFaceCoord = 2
+	// h3Init is an index with all of its fields zero, except for the digits
+	// which are all 7, (unused).
+	h3Init = uint64(35184372088831)
+
+	// h3Res0UGnomonic is the scaling factor from hex2d resolution 0 unit
+	// length (or distance between adjacent cell center points on the plane)
+	// to gnomonic unit length.
+	h3Res0UGnomonic = 0.38196601125010500003
+	// h3Ap7RotRadians is the rotation angle between Class II and Class III
+	// resolution axes, asin(sqrt(3/28)).
+	h3Ap7RotRadians = 0.333473172251832115336090755351601070065900389
+	h3Sin60         = 0.8660254037844386467637231707529361834714
+	h3Epsilon       = 0.0000000000000001
+)
+
+// The H3 digits, one for each of the 7 children of a cell.
+const (
+	h3CenterDigit = 0
+	h3KAxesDigit  = 1
+	h3JAxesDigit  = 2
+	h3JKAxesDigit = 3
+	h3IAxesDigit  = 4
+	h3IKAxesDigit = 5
+	h3IJAxesDigit = 6
+	h3NumDigits   = 7
+)
+
+// h3IJK is a hex coordinate on one of the icosahedron faces, with i, j and k
+// axes 120° apart. It is normalized when at most two of the components are
+// non-zero and all of them are positive.
+type h3IJK struct {
+	i, j, k int
+}
+
+// h3UnitVecs are the unit vectors for each of the H3 digits.
+var h3UnitVecs = [h3NumDigits]h3IJK{
+	{0, 0, 0}, // Center.
+	{0, 0, 1}, // K axis.
+	{0, 1, 0}, // J axis.
+	{0, 1, 1}, // J == K.
+	{1, 0, 0}, // I axis.
+	{1, 0, 1}, // I == K.
+	{1, 1, 0}, // I == J.
+}
+
+var (
+	// h3FaceCenterPoint are the icosahedron face centers as x/y/z on the unit
+	// sphere.
+	h3FaceCenterPoint = [h3NumFaces][3]float64{
+		{0.2199307791404606, 0.6583691780274996, 0.7198475378926182},
+		{-0.2139234834501421, 0.1478171829550703, 0.9656017935214205},
+		{0.1092625278784797, -0.4811951572873210, 0.8697775121287253},
+		{0.7428567301586791, -0.3593941678278028, 0.5648005936517033},
+		{0.8112534709140969, 0.3448953237639384, 0.4721387736413930},
+		{-0.1055498149613921, 0.9794457296411413, 0.1718874610009365},
+		{-0.8075407579970092, 0.1533552485898818, 0.5695261994882688},
+		{-0.2846148069787907, -0.8644080972654206, 0.4144792552473539},
+		{0.7405621473854482, -0.6673299564565524, -0.0789837646326737},
+		{0.8512303986474293, 0.4722343788582681, -0.2289137388687808},
+		{-0.7405621473854481, 0.6673299564565524, 0.0789837646326737},
+		{-0.8512303986474292, -0.4722343788582682, 0.2289137388687808},
+		{0.1055498149613919, -0.9794457296411413, -0.1718874610009365},
+		{0.8075407579970092, -0.1533552485898819, -0.5695261994882688},
+		{0.2846148069787908, 0.8644080972654204, -0.4144792552473539},
+		{-0.7428567301586791, 0.3593941678278027, -0.5648005936517033},
+		{-0.8112534709140971, -0.3448953237639382, -0.4721387736413930},
+		{-0.2199307791404607, -0.6583691780274996, -0.7198475378926182},
+		{0.2139234834501420, -0.1478171829550704, -0.9656017935214205},
+		{-0.1092625278784796, 0.4811951572873210, -0.8697775121287253},
+	}
+
+	// h3FaceCenterGeo are the icosahedron face centers as lat/lng in radians.
+	h3FaceCenterGeo = [h3NumFaces][2]float64{
+		{0.803582649718989942, 1.248397419617396099},
+		{1.307747883455638156, 2.536945009877921159},
+		{1.054751253523952054, -1.347517358900396623},
+		{0.600191595538186799, -0.450603909469755746},
+		{0.491715428198773866, 0.401988202911306943},
+		{0.172745327415618701, 1.678146885280433686},
+		{0.605929321571350690, 2.953923329812411617},
+		{0.427370518328979641, -1.888876200336285401},
+		{-0.079066118549212831, -0.733429513380867741},
+		{-0.230961644455383637, 0.506495587332349035},
+		{0.079066118549212831, 2.408163140208925497},
+		{0.230961644455383637, -2.635097066257444203},
+		{-0.172745327415618701, -1.463445768309359553},
+		{-0.605929321571350690, -0.187669323777381622},
+		{-0.427370518328979641, 1.252716453253507838},
+		{-0.600191595538186799, 2.690988744120037492},
+		{-0.491715428198773866, -2.739604450678486295},
+		{-0.803582649718989942, -1.893195233972397139},
+		{-1.307747883455638156, -0.604647643711872080},
+		{-1.054751253523952054, 1.794075294689396615},
+	}
+
+	// h3FaceAxesAzimuth are the azimuths in radians from each face center to
+	// its first vertex, (the direction of the Class II i axis).
+	h3FaceAxesAzimuth = [h3NumFaces]float64{
+		5.619958268523939882,
+		5.760339081714187279,
+		0.780213654393430055,
+		0.430469363979999913,
+		6.130269123335111400,
+		2.692877706530642877,
+		2.982963003477243874,
+		3.532912002790141181,
+		3.494305004259568154,
+		3.003214169499538391,
+		5.930472956509811562,
+		0.138378484090254847,
+		0.448714947059150361,
+		0.158629650112549365,
+		5.891865957979238535,
+		2.711123289609793325,
+		3.294508837434268316,
+		3.804819692245439833,
+		3.664438879055192436,
+		2.361378999196363184,
+	}
+
+	// h3PentagonCWOffsetFaces are the 12 pentagon base cells, with the faces
+	// on which their children are rotated clockwise rather than
+	// counterclockwise out of the deleted k axis, (-1 for none).
+	h3PentagonCWOffsetFaces = map[int][2]int{
+		4:   {-1, -1},
+		14:  {2, 6},
+		24:  {1, 5},
+		38:  {3, 7},
+		49:  {0, 9},
+		58:  {4, 8},
+		63:  {11, 15},
+		72:  {12, 16},
+		83:  {10, 19},
+		97:  {13, 17},
+		107: {14, 18},
+		117: {-1, -1},
+	}
+)
+
+// h3BaseCellRotation is a base cell and the number of 60° counterclockwise
+// rotations from the face's coordinate system into the base cell's.
+type h3BaseCellRotation struct {
+	baseCell, ccwRot60 int
+}
+
+// h3Cell returns the H3 index of the cell containing the point at the given
+// resolution.
+func h3Cell(lat, lng float64, res int) uint64 {
+	if res < 0 || res > h3MaxResolution || math.IsNaN(lat) || math.IsNaN(lng) {
+		return 0
+	}
+	face, x, y := h3GeoToHex2d(lat*math.Pi/180, lng*math.Pi/180, res)
+	return h3FaceIJKToH3(face, h3Hex2dToIJK(x, y), res)
+}
+
+// h3GeoToHex2d returns the icosahedron face containing the point, (in
+// radians), and its 2D hex coordinates relative to the face center at the
+// given resolution.
+func h3GeoToHex2d(lat, lng float64, res int) (int, float64, float64) {
+	p := [3]float64{math.Cos(lng) * math.Cos(lat), math.Sin(lng) * math.Cos(lat), math.Sin(lat)}
+	face, sqd := 0, 5.0
+	for f, c := range h3FaceCenterPoint {
+		d := (c[0]-p[0])*(c[0]-p[0]) + (c[1]-p[1])*(c[1]-p[1]) + (c[2]-p[2])*(c[2]-p[2])
+		if d < sqd {
+			face, sqd = f, d
+		}
+	}
+
+	// cos(r) = 1 - 2 * sin^2(r/2) = 1 - 2 * (sqd / 4) = 1 - sqd/2
+	r := math.Acos(1 - sqd/2)
+	if r < h3Epsilon {
+		return face, 0, 0
+	}
+
+	// The angle counterclockwise from the Class II i axis, rotated for Class
+	// III, (odd), resolutions.
+	center := h3FaceCenterGeo[face]
+	az := math.Atan2(math.Cos(lat)*math.Sin(lng-center[1]),
+		math.Cos(center[0])*math.Sin(lat)-math.Sin(center[0])*math.Cos(lat)*math.Cos(lng-center[1]))
+	theta := h3PositiveAngle(h3FaceAxesAzimuth[face] - h3PositiveAngle(az))
+	if res%2 == 1 {
+		theta = h3PositiveAngle(theta - h3Ap7RotRadians)
+	}
+
+	// Gnomonic scaling of r, then scaling for the resolution.
+	r = math.Tan(r) / h3Res0UGnomonic
+	for i := 0; i < res; i++ {
+		r *= math.Sqrt(7)
+	}
+	return face, r * math.Cos(theta), r * math.Sin(theta)
+}
+
+// h3PositiveAngle normalizes the angle in radians to [0, 2π).
+func h3PositiveAngle(a float64) float64 {
+	t := a
+	if a < 0 {
+		t = a + 2*math.Pi
+	}
+	if a >= 2*math.Pi {
+		t -= 2 * math.Pi
+	}
+	return t
+}
+
+// h3Hex2dToIJK returns the hex containing the 2D cartesian coordinate.
+func h3Hex2dToIJK(x, y float64) h3IJK {
+	var h h3IJK
+
+	// Quantize into the ij system, and round to the containing hex.
+	a1, a2 := math.Abs(x), math.Abs(y)
+	x2 := a2 / h3Sin60
+	x1 := a1 + x2/2
+	m1, m2 := int(x1), int(x2)
+	r1, r2 := x1-float64(m1), x2-float64(m2)
+
+	switch {
+	case r1 < 1.0/3:
+		h.i, h.j = m1, m2
+		if r2 >= (1+r1)/2 {
+			h.j = m2 + 1
+		}
+	case r1 < 0.5:
+		h.i, h.j = m1, m2
+		if r2 >= 1-r1 {
+			h.j = m2 + 1
+		}
+		if 1-r1 <= r2 && r2 < 2*r1 {
+			h.i = m1 + 1
+		}
+	case r1 < 2.0/3:
+		h.i, h.j = m1+1, m2
+		if r2 >= 1-r1 {
+			h.j = m2 + 1
+		}
+		if 2*r1-1 < r2 && r2 < 1-r1 {
+			h.i = m1
+		}
+	default:
+		h.i, h.j = m1+1, m2
+		if r2 >= r1/2 {
+			h.j = m2 + 1
+		}
+	}
+
+	// Fold across the axes if necessary.
+	if x < 0 {
+		if h.j%2 == 0 {
+			h.i -= 2 * (h.i - h.j/2)
+		} else {
+			h.i -= 2*(h.i-(h.j+1)/2) + 1
+		}
+	}
+	if y < 0 {
+		h.i -= (2*h.j + 1) / 2
+		h.j = -h.j
+	}
+
+	h.normalize()
+	return h
+}
+
+// h3FaceIJKToH3 returns the H3 index for the resolution res hex on the face.
+func h3FaceIJKToH3(face int, ijk h3IJK, res int) uint64 {
+	h := h3Init
+	h = h&^(15<<h3ModeOffset) | h3CellMode<<h3ModeOffset
+	h = h&^(15<<h3ResOffset) | uint64(res)<<h3ResOffset
+
+	// Build the digits from the finest resolution up, leaving ijk as the
+	// coordinate of the base cell on the face.
+	for r := res - 1; r >= 0; r-- {
+		last := ijk
+		var center h3IJK
+		if (r+1)%2 == 1 {
+			ijk.upAp7()
+			center = ijk
+			center.downAp7()
+		} else {
+			ijk.upAp7r()
+			center = ijk
+			center.downAp7r()
+		}
+		diff := h3IJK{last.i - center.i, last.j - center.j, last.k - center.k}
+		diff.normalize()
+		h = h3SetDigit(h, r+1, diff.digit())
+	}
+
+	if ijk.i > h3MaxFaceCoord || ijk.j > h3MaxFaceCoord || ijk.k > h3MaxFaceCoord {
+		return 0
+	}
+	bc := h3FaceIJKBaseCells[face][ijk.i][ijk.j][ijk.k]
+	h = h&^(127<<h3BCOffset) | uint64(bc.baseCell)<<h3BCOffset
+
+	// Rotate into the canonical orientation for the base cell.
+	cwFaces, pentagon := h3PentagonCWOffsetFaces[bc.baseCell]
+	if !pentagon {
+		for i := 0; i < bc.ccwRot60; i++ {
+			h = h3Rotate60(h, h3Rotate60CCW)
+		}
+		return h
+	}
+
+	// Pentagons have no k axis child, so force a rotation out of it.
+	if h3LeadingNonZeroDigit(h) == h3KAxesDigit {
+		if cwFaces[0] == face || cwFaces[1] == face {
+			h = h3Rotate60(h, h3Rotate60CW)
+		} else {
+			h = h3Rotate60(h, h3Rotate60CCW)
+		}
+	}
+	for i := 0; i < bc.ccwRot60; i++ {
+		h = h3RotatePent60CCW(h)
+	}
+	return h
+}
+
+// h3Digit returns the digit of the index for resolution r.
+func h3Digit(h uint64, r int) int {
+	return int(h>>((h3MaxResolution-r)*h3DigitBits)) & h3DigitMask
+}
+
+// h3SetDigit returns the index with the digit for resolution r set to d.
+func h3SetDigit(h uint64, r, d int) uint64 {
+	shift := (h3MaxResolution - r) * h3DigitBits
+	return h&^(h3DigitMask<<shift) | uint64(d)<<shift
+}
+
+// h3LeadingNonZeroDigit returns the coarsest non-zero digit of the index.
+func h3LeadingNonZeroDigit(h uint64) int {
+	res := int(h>>h3ResOffset) & 15
+	for r := 1; r <= res; r++ {
+		if d := h3Digit(h, r); d != h3CenterDigit {
+			return d
+		}
+	}
+	return h3CenterDigit
+}
+
+// h3Rotate60CCW and h3Rotate60CW map each digit to the one 60° around from it.
+var (
+	h3Rotate60CCW = [h3NumDigits]int{
+		h3CenterDigit, h3IKAxesDigit, h3JKAxesDigit, h3KAxesDigit,
+		h3IJAxesDigit, h3IAxesDigit, h3JAxesDigit,
+	}
+	h3Rotate60CW = [h3NumDigits]int{
+		h3CenterDigit, h3JKAxesDigit, h3IJAxesDigit, h3JAxesDigit,
+		h3IKAxesDigit, h3KAxesDigit, h3IAxesDigit,
+	}
+)
+
+// h3Rotate60 rotates all the digits of the index by 60° with the given
+// rotation.
+func h3Rotate60(h uint64, rot [h3NumDigits]int) uint64 {
+	res := int(h>>h3ResOffset) & 15
+	for r := 1; r <= res; r++ {
+		h = h3SetDigit(h, r, rot[h3Digit(h, r)])
+	}
+	return h
+}
+
+// h3RotatePent60CCW rotates the index 60° counterclockwise about a pentagon
+// center, skipping over the deleted k axis.
+func h3RotatePent60CCW(h uint64) uint64 {
+	res := int(h>>h3ResOffset) & 15
+	found := false
+	for r := 1; r <= res; r++ {
+		h = h3SetDigit(h, r, h3Rotate60CCW[h3Digit(h, r)])
+		if !found && h3Digit(h, r) != h3CenterDigit {
+			found = true
+			if h3LeadingNonZeroDigit(h) == h3KAxesDigit {
+				h = h3Rotate60(h, h3Rotate60CCW)
+			}
+		}
+	}
+	return h
+}
+
+// normalize removes any negative components and the common minimum.
+func (c *h3IJK) normalize() {
+	if c.i < 0 {
+		c.j -= c.i
+		c.k -= c.i
+		c.i = 0
+	}
+	if c.j < 0 {
+		c.i -= c.j
+		c.k -= c.j
+		c.j = 0
+	}
+	if c.k < 0 {
+		c.i -= c.k
+		c.j -= c.k
+		c.k = 0
+	}
+	if m := min(c.i, c.j, c.k); m > 0 {
+		c.i -= m
+		c.j -= m
+		c.k -= m
+	}
+}
+
+// digit returns the H3 digit for a normalized unit vector, or h3NumDigits
+// if it is not one.
+func (c h3IJK) digit() int {
+	for d, v := range h3UnitVecs {
+		if c == v {
+			return d
+		}
+	}
+	return h3NumDigits
+}
+
+// upAp7 moves the coordinate to the parent resolution, for Class III
+// resolutions which are rotated counterclockwise.
+func (c *h3IJK) upAp7() {
+	i, j := c.i-c.k, c.j-c.k
+	*c = h3IJK{int(math.Round(float64(3*i-j) / 7)), int(math.Round(float64(i+2*j) / 7)), 0}
+	c.normalize()
+}
+
+// upAp7r moves the coordinate to the parent resolution, for Class II
+// resolutions which are rotated clockwise.
+func (c *h3IJK) upAp7r() {
+	i, j := c.i-c.k, c.j-c.k
+	*c = h3IJK{int(math.Round(float64(2*i+j) / 7)), int(math.Round(float64(3*j-i) / 7)), 0}
+	c.normalize()
+}
+
+// downAp7 moves the coordinate to the center child at the next finer,
+// counterclockwise rotated, resolution.
+func (c *h3IJK) downAp7() {
+	*c = h3IJK{3*c.i + c.j, 3*c.j + c.k, c.i + 3*c.k}
+	c.normalize()
+}
+
+// downAp7r moves the coordinate to the center child at the next finer,
+// clockwise rotated, resolution.
+func (c *h3IJK) downAp7r() {
+	*c = h3IJK{3*c.i + c.k, c.i + 3*c.j, c.j + 3*c.k}
+	c.normalize()
+}
+
+// h3FaceIJKBaseCells gives the base cell, and the rotations into its
+// coordinate system, for each resolution 0 coordinate from (0, 0, 0) to
+// (2, 2, 2) on each face.
+var h3FaceIJKBaseCells = [20][3][3][3]h3BaseCellRotation{
+	{ // Face 0.
+		{
+			{{16, 0}, {18, 0}, {24, 0}},
+			{{33, 0}, {30, 0}, {32, 3}},
+			{{49, 1}, {48, 3}, {50, 3}},
+		},
+		{
+			{{8, 0}, {5, 5}, {10, 5}},
+			{{22, 0}, {16, 0}, {18, 0}},
+			{{41, 1}, {33, 0}, {30, 0}},
+		},
+		{
+			{{4, 0}, {0, 5}, {2, 5}},
+			{{15, 1}, {8, 0}, {5, 5}},
+			{{31, 1}, {22, 0}, {16, 0}},
+		},
+	},
+	{ // Face 1.
+		{
+			{{2, 0}, {6, 0}, {14, 0}},
+			{{10, 0}, {11, 0}, {17, 3}},
+			{{24, 1}, {23, 3}, {25, 3}},
+		},
+		{
+			{{0, 0}, {1, 5}, {9, 5}},
+			{{5, 0}, {2, 0}, {6, 0}},
+			{{18, 1}, {10, 0}, {11, 0}},
+		},
+		{
+			{{4, 1}, {3, 5}, {7, 5}},
+			{{8, 1}, {0, 0}, {1, 5}},
+			{{16, 1}, {5, 0}, {2, 0}},
+		},
+	},
+	{ // Face 2.
+		{
+			{{7, 0}, {21, 0}, {38, 0}},
+			{{9, 0}, {19, 0}, {34, 3}},
+			{{14, 1}, {20, 3}, {36, 3}},
+		},
+		{
+			{{3, 0}, {13, 5}, {29, 5}},
+			{{1, 0}, {7, 0}, {21, 0}},
+			{{6, 1}, {9, 0}, {19, 0}},
+		},
+		{
+			{{4, 2}, {12, 5}, {26, 5}},
+			{{0, 1}, {3, 0}, {13, 5}},
+			{{2, 1}, {1, 0}, {7, 0}},
+		},
+	},
+	{ // Face 3.
+		{
+			{{26, 0}, {42, 0}, {58, 0}},
+			{{29, 0}, {43, 0}, {62, 3}},
+			{{38, 1}, {47, 3}, {64, 3}},
+		},
+		{
+			{{12, 0}, {28, 5}, {44, 5}},
+			{{13, 0}, {26, 0}, {42, 0}},
+			{{21, 1}, {29, 0}, {43, 0}},
+		},
+		{
+			{{4, 3}, {15, 5}, {31, 5}},
+			{{3, 1}, {12, 0}, {28, 5}},
+			{{7, 1}, {13, 0}, {26, 0}},
+		},
+	},
+	{ // Face 4.
+		{
+			{{31, 0}, {41, 0}, {49, 0}},
+			{{44, 0}, {53, 0}, {61, 3}},
+			{{58, 1}, {65, 3}, {75, 3}},
+		},
+		{
+			{{15, 0}, {22, 5}, {33, 5}},
+			{{28, 0}, {31, 0}, {41, 0}},
+			{{42, 1}, {44, 0}, {53, 0}},
+		},
+		{
+			{{4, 4}, {8, 5}, {16, 5}},
+			{{12, 1}, {15, 0}, {22, 5}},
+			{{26, 1}, {28, 0}, {31, 0}},
+		},
+	},
+	{ // Face 5.
+		{
+			{{50, 0}, {48, 0}, {49, 3}},
+			{{32, 0}, {30, 3}, {33, 3}},
+			{{24, 3}, {18, 3}, {16, 3}},
+		},
+		{
+			{{70, 0}, {67, 0}, {66, 3}},
+			{{52, 3}, {50, 0}, {48, 0}},
+			{{37, 3}, {32, 0}, {30, 3}},
+		},
+		{
+			{{83, 0}, {87, 3}, {85, 3}},
+			{{74, 3}, {70, 0}, {67, 0}},
+			{{57, 1}, {52, 3}, {50, 0}},
+		},
+	},
+	{ // Face 6.
+		{
+			{{25, 0}, {23, 0}, {24, 3}},
+			{{17, 0}, {11, 3}, {10, 3}},
+			{{14, 3}, {6, 3}, {2, 3}},
+		},
+		{
+			{{45, 0}, {39, 0}, {37, 3}},
+			{{35, 3}, {25, 0}, {23, 0}},
+			{{27, 3}, {17, 0}, {11, 3}},
+		},
+		{
+			{{63, 0}, {59, 3}, {57, 3}},
+			{{56, 3}, {45, 0}, {39, 0}},
+			{{46, 3}, {35, 3}, {25, 0}},
+		},
+	},
+	{ // Face 7.
+		{
+			{{36, 0}, {20, 0}, {14, 3}},
+			{{34, 0}, {19, 3}, {9, 3}},
+			{{38, 3}, {21, 3}, {7, 3}},
+		},
+		{
+			{{55, 0}, {40, 0}, {27, 3}},
+			{{54, 3}, {36, 0}, {20, 0}},
+			{{51, 3}, {34, 0}, {19, 3}},
+		},
+		{
+			{{72, 0}, {60, 3}, {46, 3}},
+			{{73, 3}, {55, 0}, {40, 0}},
+			{{71, 3}, {54, 3}, {36, 0}},
+		},
+	},
+	{ // Face 8.
+		{
+			{{64, 0}, {47, 0}, {38, 3}},
+			{{62, 0}, {43, 3}, {29, 3}},
+			{{58, 3}, {42, 3}, {26, 3}},
+		},
+		{
+			{{84, 0}, {69, 0}, {51, 3}},
+			{{82, 3}, {64, 0}, {47, 0}},
+			{{76, 3}, {62, 0}, {43, 3}},
+		},
+		{
+			{{97, 0}, {89, 3}, {71, 3}},
+			{{98, 3}, {84, 0}, {69, 0}},
+			{{96, 3}, {82, 3}, {64, 0}},
+		},
+	},
+	{ // Face 9.
+		{
+			{{75, 0}, {65, 0}, {58, 3}},
+			{{61, 0}, {53, 3}, {44, 3}},
+			{{49, 3}, {41, 3}, {31, 3}},
+		},
+		{
+			{{94, 0}, {86, 0}, {76, 3}},
+			{{81, 3}, {75, 0}, {65, 0}},
+			{{66, 3}, {61, 0}, {53, 3}},
+		},
+		{
+			{{107, 0}, {104, 3}, {96, 3}},
+			{{101, 3}, {94, 0}, {86, 0}},
+			{{85, 3}, {81, 3}, {75, 0}},
+		},
+	},
+	{ // Face 10.
+		{
+			{{57, 0}, {59, 0}, {63, 3}},
+			{{74, 0}, {78, 3}, {79, 3}},
+			{{83, 3}, {92, 3}, {95, 3}},
+		},
+		{
+			{{37, 0}, {39, 3}, {45, 3}},
+			{{52, 0}, {57, 0}, {59, 0}},
+			{{70, 3}, {74, 0}, {78, 3}},
+		},
+		{
+			{{24, 0}, {23, 3}, {25, 3}},
+			{{32, 3}, {37, 0}, {39, 3}},
+			{{50, 3}, {52, 0}, {57, 0}},
+		},
+	},
+	{ // Face 11.
+		{
+			{{46, 0}, {60, 0}, {72, 3}},
+			{{56, 0}, {68, 3}, {80, 3}},
+			{{63, 3}, {77, 3}, {90, 3}},
+		},
+		{
+			{{27, 0}, {40, 3}, {55, 3}},
+			{{35, 0}, {46, 0}, {60, 0}},
+			{{45, 3}, {56, 0}, {68, 3}},
+		},
+		{
+			{{14, 0}, {20, 3}, {36, 3}},
+			{{17, 3}, {27, 0}, {40, 3}},
+			{{25, 3}, {35, 0}, {46, 0}},
+		},
+	},
+	{ // Face 12.
+		{
+			{{71, 0}, {89, 0}, {97, 3}},
+			{{73, 0}, {91, 3}, {103, 3}},
+			{{72, 3}, {88, 3}, {105, 3}},
+		},
+		{
+			{{51, 0}, {69, 3}, {84, 3}},
+			{{54, 0}, {71, 0}, {89, 0}},
+			{{55, 3}, {73, 0}, {91, 3}},
+		},
+		{
+			{{38, 0}, {47, 3}, {64, 3}},
+			{{34, 3}, {51, 0}, {69, 3}},
+			{{36, 3}, {54, 0}, {71, 0}},
+		},
+	},
+	{ // Face 13.
+		{
+			{{96, 0}, {104, 0}, {107, 3}},
+			{{98, 0}, {110, 3}, {115, 3}},
+			{{97, 3}, {111, 3}, {119, 3}},
+		},
+		{
+			{{76, 0}, {86, 3}, {94, 3}},
+			{{82, 0}, {96, 0}, {104, 0}},
+			{{84, 3}, {98, 0}, {110, 3}},
+		},
+		{
+			{{58, 0}, {65, 3}, {75, 3}},
+			{{62, 3}, {76, 0}, {86, 3}},
+			{{64, 3}, {82, 0}, {96, 0}},
+		},
+	},
+	{ // Face 14.
+		{
+			{{85, 0}, {87, 0}, {83, 3}},
+			{{101, 0}, {102, 3}, {100, 3}},
+			{{107, 3}, {112, 3}, {114, 3}},
+		},
+		{
+			{{66, 0}, {67, 3}, {70, 3}},
+			{{81, 0}, {85, 0}, {87, 0}},
+			{{94, 3}, {101, 0}, {102, 3}},
+		},
+		{
+			{{49, 0}, {48, 3}, {50, 3}},
+			{{61, 3}, {66, 0}, {67, 3}},
+			{{75, 3}, {81, 0}, {85, 0}},
+		},
+	},
+	{ // Face 15.
+		{
+			{{95, 0}, {92, 0}, {83, 0}},
+			{{79, 0}, {78, 0}, {74, 3}},
+			{{63, 1}, {59, 3}, {57, 3}},
+		},
+		{
+			{{109, 0}, {108, 0}, {100, 5}},
+			{{93, 1}, {95, 0}, {92, 0}},
+			{{77, 1}, {79, 0}, {78, 0}},
+		},
+		{
+			{{117, 4}, {118, 5}, {114, 5}},
+			{{106, 1}, {109, 0}, {108, 0}},
+			{{90, 1}, {93, 1}, {95, 0}},
+		},
+	},
+	{ // Face 16.
+		{
+			{{90, 0}, {77, 0}, {63, 0}},
+			{{80, 0}, {68, 0}, {56, 3}},
+			{{72, 1}, {60, 3}, {46, 3}},
+		},
+		{
+			{{106, 0}, {93, 0}, {79, 5}},
+			{{99, 1}, {90, 0}, {77, 0}},
+			{{88, 1}, {80, 0}, {68, 0}},
+		},
+		{
+			{{117, 3}, {109, 5}, {95, 5}},
+			{{113, 1}, {106, 0}, {93, 0}},
+			{{105, 1}, {99, 1}, {90, 0}},
+		},
+	},
+	{ // Face 17.
+		{
+			{{105, 0}, {88, 0}, {72, 0}},
+			{{103, 0}, {91, 0}, {73, 3}},
+			{{97, 1}, {89, 3}, {71, 3}},
+		},
+		{
+			{{113, 0}, {99, 0}, {80, 5}},
+			{{116, 1}, {105, 0}, {88, 0}},
+			{{111, 1}, {103, 0}, {91, 0}},
+		},
+		{
+			{{117, 2}, {106, 5}, {90, 5}},
+			{{121, 1}, {113, 0}, {99, 0}},
+			{{119, 1}, {116, 1}, {105, 0}},
+		},
+	},
+	{ // Face 18.
+		{
+			{{119, 0}, {111, 0}, {97, 0}},
+			{{115, 0}, {110, 0}, {98, 3}},
+			{{107, 1}, {104, 3}, {96, 3}},
+		},
+		{
+			{{121, 0}, {116, 0}, {103, 5}},
+			{{120, 1}, {119, 0}, {111, 0}},
+			{{112, 1}, {115, 0}, {110, 0}},
+		},
+		{
+			{{117, 1}, {113, 5}, {105, 5}},
+			{{118, 1}, {121, 0}, {116, 0}},
+			{{114, 1}, {120, 1}, {119, 0}},
+		},
+	},
+	{ // Face 19.
+		{
+			{{114, 0}, {112, 0}, {107, 0}},
+			{{100, 0}, {102, 0}, {101, 3}},
+			{{83, 1}, {87, 3}, {85, 3}},
+		},
+		{
+			{{118, 0}, {120, 0}, {115, 5}},
+			{{108, 1}, {114, 0}, {112, 0}},
+			{{92, 1}, {100, 0}, {102, 0}},
+		},
+		{
+			{{117, 0}, {121, 5}, {119, 5}},
+			{{109, 1}, {118, 0}, {120, 0}},
+			{{95, 1}, {108, 1}, {114, 0}},
+		},
+	},
+}
@@ -0,0 +1,38 @@
+package geography
+
+import (
+	"math"
+	"strconv"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Normalize fills in all of the Geography fields that are derived from its Lat
+// and Lng, (the E7 forms, S2 CellID, GeoHash, Plus Code, H3 cell) so that
+// entities can be spatially joined regardless of which source they came from.
+//
+// The Timezone is only filled in if the source did not already supply one.
+func Normalize(g *ds.Geography) {
+	lat, lng := degrees(g.Lat), degrees(g.Lng)
+
+	g.LatE7 = int32(math.Round(lat * 1e7))
+	g.LngE7 = int32(math.Round(lng * 1e7))
+	g.S2CellID = s2LeafCellID(lat, lng)
+	g.GeoHash = geohash(lat, lng, geohashLength)
+	g.PlusCode = plusCode(lat, lng, olcDefaultCodeLength)
+	g.H3Cell = h3Cell(lat, lng, h3Resolution)
+	if g.Timezone == "" {
+		g.Timezone = Timezone(lat, lng)
+	}
+}
+
+// degrees returns the float64 value of the given float32 coordinate without
+// picking up the binary noise a plain conversion adds. e.g. float32(37.6197)
+// would otherwise become 37.619701385498047.
+func degrees(f float32) float64 {
+	d, err := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'f', -1, 32), 64)
+	if err != nil {
+		return float64(f)
+	}
+	return d
+}
@@ -0,0 +1,211 @@
+package geography
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		length   int
+		want     string
+	}{
+		{
+			lat:    0,
+			lng:    0,
+			length: 5,
+			want:   "s0000",
+		},
+		{
+			// Example from the Wikipedia Geohash article.
+			lat:    57.64911,
+			lng:    10.40744,
+			length: 11,
+			want:   "u4pruydqqvj",
+		},
+		{
+			lat:    -90,
+			lng:    -180,
+			length: 4,
+			want:   "0000",
+		},
+		{
+			lat:    90,
+			lng:    180,
+			length: 4,
+			want:   "zzzz",
+		},
+	}
+
+	for _, test := range tests {
+		if got := geohash(test.lat, test.lng, test.length); got != test.want {
+			t.Errorf("geohash(%v, %v, %d) = %q, want %q", test.lat, test.lng, test.length, got, test.want)
+		}
+	}
+}
+
+func TestPlusCode(t *testing.T) {
+	// Test cases are from the open-location-code test data.
+	tests := []struct {
+		lat, lng float64
+		length   int
+		want     string
+	}{
+		{
+			lat:    20.375,
+			lng:    2.775,
+			length: 6,
+			want:   "7FG49Q00+",
+		},
+		{
+			lat:    20.3700625,
+			lng:    2.7821875,
+			length: 10,
+			want:   "7FG49QCJ+2V",
+		},
+		{
+			lat:    47.0000625,
+			lng:    8.0000625,
+			length: 10,
+			want:   "8FVC2222+22",
+		},
+		{
+			lat:    -41.2730625,
+			lng:    174.7859375,
+			length: 10,
+			want:   "4VCPPQGP+Q9",
+		},
+		{
+			lat:    0.5,
+			lng:    -179.5,
+			length: 4,
+			want:   "62G20000+",
+		},
+		{
+			// Latitude 90 is clipped to just below the pole.
+			lat:    90,
+			lng:    1,
+			length: 4,
+			want:   "CFX30000+",
+		},
+		{
+			// Longitudes wrap around.
+			lat:    1,
+			lng:    180,
+			length: 4,
+			want:   "62H20000+",
+		},
+	}
+
+	for _, test := range tests {
+		if got := plusCode(test.lat, test.lng, test.length); got != test.want {
+			t.Errorf("plusCode(%v, %v, %d) = %q, want %q", test.lat, test.lng, test.length, got, test.want)
+		}
+	}
+}
+
+func TestS2LeafCellID(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		want     uint64
+	}{
+		{
+			// The center of face 0.
+			lat:  0,
+			lng:  0,
+			want: 0x1000000000000001,
+		},
+		{
+			// The north pole is the center of face 2.
+			lat:  90,
+			lng:  0,
+			want: 0x5000000000000001,
+		},
+	}
+
+	for _, test := range tests {
+		if got := s2LeafCellID(test.lat, test.lng); got != test.want {
+			t.Errorf("s2LeafCellID(%v, %v) = 0x%x, want 0x%x", test.lat, test.lng, got, test.want)
+		}
+	}
+
+	// Spot check the well known level 9 tokens for a few cities.
+	cities := []struct {
+		lat, lng float64
+		token    uint64
+	}{
+		{lat: 40.7128, lng: -74.0060, token: 0x89c25},
+		{lat: 37.7749, lng: -122.4194, token: 0x80858},
+	}
+	for _, c := range cities {
+		if got := s2LeafCellID(c.lat, c.lng) >> 44; got != c.token {
+			t.Errorf("s2LeafCellID(%v, %v) level 9 token = %x, want %x", c.lat, c.lng, got, c.token)
+		}
+	}
+}
+
+func TestH3Cell(t *testing.T) {
+	// Test cases are from the h3geo.org C library's latLngToCell.
+	tests := []struct {
+		lat, lng float64
+		res      int
+		want     uint64
+	}{
+		// Bad resolutions.
+		{lat: 37.7749, lng: -122.4194, res: -1, want: 0},
+		{lat: 37.7749, lng: -122.4194, res: 16, want: 0},
+
+		// Normal cases.
+		{lat: 37.7749, lng: -122.4194, res: 0, want: 0x8029fffffffffff},
+		{lat: 37.7749, lng: -122.4194, res: 1, want: 0x81283ffffffffff},
+		{lat: 37.7749, lng: -122.4194, res: 9, want: 0x89283082803ffff},
+		{lat: 37.7749, lng: -122.4194, res: 15, want: 0x8f283082800b390},
+		{lat: -33.8607, lng: 151.2050, res: 15, want: 0x8fbe0e35c31e491},
+		{lat: 35.6762, lng: 139.6503, res: 15, want: 0x8f2f5a363ba005a},
+		{lat: 0, lng: 0, res: 15, want: 0x8f754e64992d6d8},
+		{lat: -90, lng: 0, res: 15, want: 0x8ff29380e0d0cc4},
+
+		// Around the pentagons, which are rotated out of the deleted k axis.
+		{lat: 64.7, lng: 10.5361, res: 15, want: 0x8f0800000000102},
+		{lat: 64.71, lng: 10.5461, res: 15, want: 0x8f080000561a119},
+		{lat: 64.68, lng: 10.5411, res: 15, want: 0x8f0800015a520d1},
+		{lat: 39.11, lng: 122.31, res: 15, want: 0x8f300000552e069},
+		{lat: 39.08, lng: 122.305, res: 15, want: 0x8f300001c8288a8},
+		{lat: -64.69, lng: -169.4538, res: 15, want: 0x8fea00006a29471},
+		{lat: -64.72, lng: -169.4588, res: 15, want: 0x8fea0002a5133b5},
+		{lat: -64.72, lng: -169.4588, res: 5, want: 0x85ea0003fffffff},
+	}
+
+	for _, test := range tests {
+		if got := h3Cell(test.lat, test.lng, test.res); got != test.want {
+			t.Errorf("h3Cell(%v, %v, %d) = %x, want %x", test.lat, test.lng, test.res, got, test.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := &ds.Geography{
+		Lat: 37.6197,
+		Lng: -122.3656,
+	}
+	Normalize(got)
+
+	want := &ds.Geography{
+		Lat:      37.6197,
+		Lng:      -122.3656,
+		LatE7:    376197000,
+		LngE7:    -1223656000,
+		S2CellID: 0x808f77e64ed8f301,
+		GeoHash:  "9q8yp82ndu77",
+		PlusCode: "849VJJ9M+VQ",
+		H3Cell:   0x8f2830905ab28b1,
+		Timezone: "America/Los_Angeles",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Normalize() = %+v, want %+v\ndiff: %s", got, want, diff)
+	}
+}
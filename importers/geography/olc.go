@@ -0,0 +1,104 @@
+package geography
+
+import "math"
+
+// This is a minimal port of the encoding half of the Open Location Code
+// library. See github.com/google/open-location-code for the full library.
+
+const (
+	// olcAlphabet is the set of digits used in Open Location Codes.
+	olcAlphabet = "23456789CFGHJMPQRVWX"
+	olcBase     = int64(len(olcAlphabet))
+
+	olcSeparator         = '+'
+	olcSeparatorPosition = 8
+	olcPadding           = '0'
+
+	// olcPairCodeLength is the number of digits encoded as lat/lng pairs.
+	// Any digits beyond this use the 4x5 grid refinement.
+	olcPairCodeLength = 10
+	olcGridCodeLength = 5
+	olcGridRows       = 5
+	olcGridCols       = 4
+	olcMaxCodeLength  = olcPairCodeLength + olcGridCodeLength
+
+	// The precision of the pair and grid digits as integer multipliers.
+	olcPairPrecision     = int64(8000) // olcBase^3
+	olcGridLatFullValue  = int64(3125) // olcGridRows^olcGridCodeLength
+	olcGridLngFullValue  = int64(1024) // olcGridCols^olcGridCodeLength
+	olcFinalLatPrecision = olcPairPrecision * olcGridLatFullValue
+	olcFinalLngPrecision = olcPairPrecision * olcGridLngFullValue
+
+	olcLatMax   = int64(90)
+	olcLngMax   = int64(180)
+	olcLatRange = 2 * olcLatMax * olcFinalLatPrecision
+	olcLngRange = 2 * olcLngMax * olcFinalLngPrecision
+
+	// olcDefaultCodeLength gives codes with a precision of about 14 meters.
+	olcDefaultCodeLength = 10
+)
+
+// plusCode returns the Open Location Code for the given point with the given
+// number of digits. Lengths less than 2, or more than 15 are clamped, and odd
+// lengths below 10 are rounded up, as in the reference implementation.
+func plusCode(lat, lng float64, length int) string {
+	if length < 2 {
+		length = 2
+	}
+	if length < olcPairCodeLength && length%2 == 1 {
+		length++
+	}
+	if length > olcMaxCodeLength {
+		length = olcMaxCodeLength
+	}
+
+	// Convert to integers in the final precision to avoid floating point
+	// errors creeping in as the digits are extracted.
+	latVal := int64(math.Round(lat*float64(olcFinalLatPrecision))) + olcLatMax*olcFinalLatPrecision
+	if latVal < 0 {
+		latVal = 0
+	} else if latVal >= olcLatRange {
+		latVal = olcLatRange - 1
+	}
+	lngVal := int64(math.Round(lng*float64(olcFinalLngPrecision))) + olcLngMax*olcFinalLngPrecision
+	lngVal %= olcLngRange
+	if lngVal < 0 {
+		lngVal += olcLngRange
+	}
+
+	code := make([]byte, olcMaxCodeLength+1)
+	if length > olcPairCodeLength {
+		for i := 0; i < olcGridCodeLength; i++ {
+			ndx := (latVal%olcGridRows)*olcGridCols + lngVal%olcGridCols
+			code[olcMaxCodeLength-i] = olcAlphabet[ndx]
+			latVal /= olcGridRows
+			lngVal /= olcGridCols
+		}
+	} else {
+		latVal /= olcGridLatFullValue
+		lngVal /= olcGridLngFullValue
+	}
+
+	// The pair digits are extracted least significant first, so they are written
+	// from the end backwards, skipping over the separator.
+	for i := olcPairCodeLength/2 - 1; i >= 0; i-- {
+		pos := 2 * i
+		if pos >= olcSeparatorPosition {
+			pos++
+		}
+		code[pos] = olcAlphabet[latVal%olcBase]
+		code[pos+1] = olcAlphabet[lngVal%olcBase]
+		latVal /= olcBase
+		lngVal /= olcBase
+	}
+	code[olcSeparatorPosition] = olcSeparator
+
+	// Trim off any unneeded grid digits, or pad out short codes.
+	if length >= olcSeparatorPosition {
+		return string(code[:length+1])
+	}
+	for i := length; i < olcSeparatorPosition; i++ {
+		code[i] = olcPadding
+	}
+	return string(code[:olcSeparatorPosition+1])
+}
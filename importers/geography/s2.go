@@ -0,0 +1,144 @@
+package geography
+
+import "math"
+
+// This is a minimal port of the parts of the s2geometry.io library needed to
+// compute the leaf CellID for a given point. See github.com/golang/geo/s2 for
+// the full library and for more detailed explanations of the steps.
+
+const (
+	// s2MaxLevel is the level of leaf cells.
+	s2MaxLevel = 30
+	// s2PosBits is the number of bits used to encode the position along the
+	// Hilbert curve within a face, plus one bit for the trailing marker.
+	s2PosBits = 2*s2MaxLevel + 1
+	// s2MaxSize is the number of leaf cells along each edge of a face.
+	s2MaxSize = 1 << s2MaxLevel
+
+	s2LookupBits = 4
+	s2SwapMask   = 0x01
+	s2InvertMask = 0x02
+)
+
+var (
+	// s2PosToIJ maps the position along the Hilbert curve of each of the four
+	// children of a cell to the (i, j) of the child, for each orientation.
+	s2PosToIJ = [4][4]int{
+		{0, 1, 3, 2}, // canonical order:    (0,0), (0,1), (1,1), (1,0)
+		{0, 2, 3, 1}, // axes swapped:       (0,0), (1,0), (1,1), (0,1)
+		{3, 2, 0, 1}, // bits inverted:      (1,1), (1,0), (0,0), (0,1)
+		{3, 1, 0, 2}, // swapped & inverted: (1,1), (0,1), (0,0), (1,0)
+	}
+	// s2PosToOrientation is the change in orientation for each child position.
+	s2PosToOrientation = [4]int{s2SwapMask, 0, 0, s2InvertMask | s2SwapMask}
+
+	// s2LookupPos maps 4 bits of i and j and an orientation to 8 bits of
+	// Hilbert curve position and the resulting orientation.
+	s2LookupPos [1 << (2*s2LookupBits + 2)]int
+)
+
+func init() {
+	s2InitLookupCell(0, 0, 0, 0, 0, 0)
+	s2InitLookupCell(0, 0, 0, s2SwapMask, 0, s2SwapMask)
+	s2InitLookupCell(0, 0, 0, s2InvertMask, 0, s2InvertMask)
+	s2InitLookupCell(0, 0, 0, s2SwapMask|s2InvertMask, 0, s2SwapMask|s2InvertMask)
+}
+
+// s2InitLookupCell recursively fills in the lookup table for all the cells at
+// s2LookupBits levels below the given cell.
+func s2InitLookupCell(level, i, j, origOrientation, pos, orientation int) {
+	if level == s2LookupBits {
+		ij := (i << s2LookupBits) + j
+		s2LookupPos[(ij<<2)+origOrientation] = (pos << 2) + orientation
+		return
+	}
+
+	level++
+	i <<= 1
+	j <<= 1
+	pos <<= 2
+	r := s2PosToIJ[orientation]
+	for k := 0; k < 4; k++ {
+		s2InitLookupCell(level, i+(r[k]>>1), j+(r[k]&1), origOrientation, pos+k, orientation^s2PosToOrientation[k])
+	}
+}
+
+// s2LeafCellID returns the id of the level 30 s2 cell containing the given point.
+func s2LeafCellID(lat, lng float64) uint64 {
+	phi := lat * math.Pi / 180
+	theta := lng * math.Pi / 180
+	x := math.Cos(phi) * math.Cos(theta)
+	y := math.Cos(phi) * math.Sin(theta)
+	z := math.Sin(phi)
+
+	face, u, v := s2XYZToFaceUV(x, y, z)
+	return s2CellIDFromFaceIJ(face, s2STToIJ(s2UVToST(u)), s2STToIJ(s2UVToST(v)))
+}
+
+// s2XYZToFaceUV returns the cube face the point projects on to, and its (u, v)
+// coordinates on that face.
+func s2XYZToFaceUV(x, y, z float64) (int, float64, float64) {
+	face := 0
+	ax, ay, az := math.Abs(x), math.Abs(y), math.Abs(z)
+	switch {
+	case ax > ay && ax > az:
+		face = 0
+		if x < 0 {
+			face = 3
+		}
+	case ay > az:
+		face = 1
+		if y < 0 {
+			face = 4
+		}
+	default:
+		face = 2
+		if z < 0 {
+			face = 5
+		}
+	}
+
+	switch face {
+	case 0:
+		return face, y / x, z / x
+	case 1:
+		return face, -x / y, z / y
+	case 2:
+		return face, -x / z, -y / z
+	case 3:
+		return face, z / x, y / x
+	case 4:
+		return face, z / y, -x / y
+	default:
+		return face, -y / z, -x / z
+	}
+}
+
+// s2UVToST converts a face coordinate in [-1, 1] to a cell-space coordinate in
+// [0, 1] using the quadratic projection.
+func s2UVToST(u float64) float64 {
+	if u >= 0 {
+		return 0.5 * math.Sqrt(1+3*u)
+	}
+	return 1 - 0.5*math.Sqrt(1-3*u)
+}
+
+// s2STToIJ converts a cell-space coordinate to the leaf cell coordinate.
+func s2STToIJ(s float64) int {
+	return int(math.Max(0, math.Min(s2MaxSize-1, math.Floor(s2MaxSize*s))))
+}
+
+// s2CellIDFromFaceIJ returns the leaf cell id for the given face and leaf cell coordinates.
+func s2CellIDFromFaceIJ(face, i, j int) uint64 {
+	n := uint64(face) << (s2PosBits - 1)
+	bits := face & s2SwapMask
+	mask := (1 << s2LookupBits) - 1
+	for k := 7; k >= 0; k-- {
+		bits += ((i >> uint(k*s2LookupBits)) & mask) << (s2LookupBits + 2)
+		bits += ((j >> uint(k*s2LookupBits)) & mask) << 2
+		bits = s2LookupPos[bits]
+		n |= uint64(bits>>2) << (uint(k) * 2 * s2LookupBits)
+		bits &= s2SwapMask | s2InvertMask
+	}
+	return n*2 + 1
+}
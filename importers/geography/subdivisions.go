@@ -0,0 +1,187 @@
+package geography
+
+import (
+	"strings"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Subdivision is an ISO 3166-2 top level administrative subdivision of a region,
+// such as a US State, Canadian Province, or Australian State or Territory.
+type Subdivision struct {
+	// Code is the full ISO 3166-2 code. e.g., "US-CA", "CA-ON", "MX-CHH"
+	Code string
+	// Name is the English name of the subdivision.
+	Name string
+
+	// aliases are the other codes used for this subdivision by the various
+	// sources, such as postal abbreviations, FIPS 10-4 codes, or FIPS 5-2
+	// numeric state codes.
+	aliases []string
+}
+
+// Apply sets the subdivision fields on the given Geography.
+func (s *Subdivision) Apply(g *ds.Geography) {
+	g.Subdivision1Code = s.Code
+	g.Subdivision1Name = s.Name
+}
+
+// SubdivisionFor returns the ISO 3166-2 subdivision in the given ISO 3166-1
+// region for the given code. The code may be any of:
+//
+//   - the full ISO 3166-2 code (e.g., "US-CA")
+//   - the ISO 3166-2 code without the region prefix (e.g., "CA", "NSW")
+//   - the postal abbreviation (e.g., "CA", "ON", "CHIH")
+//   - the FIPS 10-4 code (e.g., "CA08", "AS02", "MX06")
+//   - for the US, the FIPS 5-2 numeric state code (e.g., "06")
+//
+// GHCN-D (ghcnd-states.txt) and the NOAA ISD station list use the postal
+// abbreviations for US states and Canadian provinces.
+func SubdivisionFor(region, code string) (*Subdivision, bool) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if region == "" || code == "" {
+		return nil, false
+	}
+
+	s, ok := subdivisions[region][code]
+	return s, ok
+}
+
+// subdivisions maps from ISO 3166-1 region code to the subdivisions in that region
+// keyed by all of the codes the subdivision is known by.
+var subdivisions = func() map[string]map[string]*Subdivision {
+	m := make(map[string]map[string]*Subdivision)
+	for _, s := range isoSubdivisions {
+		region, suffix, _ := strings.Cut(s.Code, "-")
+		if m[region] == nil {
+			m[region] = make(map[string]*Subdivision)
+		}
+		m[region][s.Code] = s
+		m[region][suffix] = s
+		for _, a := range s.aliases {
+			m[region][a] = s
+		}
+	}
+	return m
+}()
+
+var isoSubdivisions = []*Subdivision{
+	// United States. FIPS 10-4 codes are "US" followed by the FIPS 5-2 code.
+	{Code: "US-AL", Name: "Alabama", aliases: []string{"01", "US01"}},
+	{Code: "US-AK", Name: "Alaska", aliases: []string{"02", "US02"}},
+	{Code: "US-AZ", Name: "Arizona", aliases: []string{"04", "US04"}},
+	{Code: "US-AR", Name: "Arkansas", aliases: []string{"05", "US05"}},
+	{Code: "US-CA", Name: "California", aliases: []string{"06", "US06"}},
+	{Code: "US-CO", Name: "Colorado", aliases: []string{"08", "US08"}},
+	{Code: "US-CT", Name: "Connecticut", aliases: []string{"09", "US09"}},
+	{Code: "US-DE", Name: "Delaware", aliases: []string{"10", "US10"}},
+	{Code: "US-DC", Name: "District of Columbia", aliases: []string{"11", "US11"}},
+	{Code: "US-FL", Name: "Florida", aliases: []string{"12", "US12"}},
+	{Code: "US-GA", Name: "Georgia", aliases: []string{"13", "US13"}},
+	{Code: "US-HI", Name: "Hawaii", aliases: []string{"15", "US15"}},
+	{Code: "US-ID", Name: "Idaho", aliases: []string{"16", "US16"}},
+	{Code: "US-IL", Name: "Illinois", aliases: []string{"17", "US17"}},
+	{Code: "US-IN", Name: "Indiana", aliases: []string{"18", "US18"}},
+	{Code: "US-IA", Name: "Iowa", aliases: []string{"19", "US19"}},
+	{Code: "US-KS", Name: "Kansas", aliases: []string{"20", "US20"}},
+	{Code: "US-KY", Name: "Kentucky", aliases: []string{"21", "US21"}},
+	{Code: "US-LA", Name: "Louisiana", aliases: []string{"22", "US22"}},
+	{Code: "US-ME", Name: "Maine", aliases: []string{"23", "US23"}},
+	{Code: "US-MD", Name: "Maryland", aliases: []string{"24", "US24"}},
+	{Code: "US-MA", Name: "Massachusetts", aliases: []string{"25", "US25"}},
+	{Code: "US-MI", Name: "Michigan", aliases: []string{"26", "US26"}},
+	{Code: "US-MN", Name: "Minnesota", aliases: []string{"27", "US27"}},
+	{Code: "US-MS", Name: "Mississippi", aliases: []string{"28", "US28"}},
+	{Code: "US-MO", Name: "Missouri", aliases: []string{"29", "US29"}},
+	{Code: "US-MT", Name: "Montana", aliases: []string{"30", "US30"}},
+	{Code: "US-NE", Name: "Nebraska", aliases: []string{"31", "US31"}},
+	{Code: "US-NV", Name: "Nevada", aliases: []string{"32", "US32"}},
+	{Code: "US-NH", Name: "New Hampshire", aliases: []string{"33", "US33"}},
+	{Code: "US-NJ", Name: "New Jersey", aliases: []string{"34", "US34"}},
+	{Code: "US-NM", Name: "New Mexico", aliases: []string{"35", "US35"}},
+	{Code: "US-NY", Name: "New York", aliases: []string{"36", "US36"}},
+	{Code: "US-NC", Name: "North Carolina", aliases: []string{"37", "US37"}},
+	{Code: "US-ND", Name: "North Dakota", aliases: []string{"38", "US38"}},
+	{Code: "US-OH", Name: "Ohio", aliases: []string{"39", "US39"}},
+	{Code: "US-OK", Name: "Oklahoma", aliases: []string{"40", "US40"}},
+	{Code: "US-OR", Name: "Oregon", aliases: []string{"41", "US41"}},
+	{Code: "US-PA", Name: "Pennsylvania", aliases: []string{"42", "US42"}},
+	{Code: "US-RI", Name: "Rhode Island", aliases: []string{"44", "US44"}},
+	{Code: "US-SC", Name: "South Carolina", aliases: []string{"45", "US45"}},
+	{Code: "US-SD", Name: "South Dakota", aliases: []string{"46", "US46"}},
+	{Code: "US-TN", Name: "Tennessee", aliases: []string{"47", "US47"}},
+	{Code: "US-TX", Name: "Texas", aliases: []string{"48", "US48"}},
+	{Code: "US-UT", Name: "Utah", aliases: []string{"49", "US49"}},
+	{Code: "US-VT", Name: "Vermont", aliases: []string{"50", "US50"}},
+	{Code: "US-VA", Name: "Virginia", aliases: []string{"51", "US51"}},
+	{Code: "US-WA", Name: "Washington", aliases: []string{"53", "US53"}},
+	{Code: "US-WV", Name: "West Virginia", aliases: []string{"54", "US54"}},
+	{Code: "US-WI", Name: "Wisconsin", aliases: []string{"55", "US55"}},
+	{Code: "US-WY", Name: "Wyoming", aliases: []string{"56", "US56"}},
+	{Code: "US-AS", Name: "American Samoa", aliases: []string{"60", "US60"}},
+	{Code: "US-GU", Name: "Guam", aliases: []string{"66", "US66"}},
+	{Code: "US-MP", Name: "Northern Mariana Islands", aliases: []string{"69", "US69"}},
+	{Code: "US-PR", Name: "Puerto Rico", aliases: []string{"72", "US72"}},
+	{Code: "US-UM", Name: "United States Minor Outlying Islands", aliases: []string{"74", "US74"}},
+	{Code: "US-VI", Name: "Virgin Islands, U.S.", aliases: []string{"78", "US78"}},
+
+	// Canada.
+	{Code: "CA-AB", Name: "Alberta", aliases: []string{"CA01"}},
+	{Code: "CA-BC", Name: "British Columbia", aliases: []string{"CA02"}},
+	{Code: "CA-MB", Name: "Manitoba", aliases: []string{"CA03"}},
+	{Code: "CA-NB", Name: "New Brunswick", aliases: []string{"CA04"}},
+	{Code: "CA-NL", Name: "Newfoundland and Labrador", aliases: []string{"CA05", "NF"}},
+	{Code: "CA-NS", Name: "Nova Scotia", aliases: []string{"CA07"}},
+	{Code: "CA-ON", Name: "Ontario", aliases: []string{"CA08"}},
+	{Code: "CA-PE", Name: "Prince Edward Island", aliases: []string{"CA09"}},
+	{Code: "CA-QC", Name: "Quebec", aliases: []string{"CA10", "PQ"}},
+	{Code: "CA-SK", Name: "Saskatchewan", aliases: []string{"CA11"}},
+	{Code: "CA-YT", Name: "Yukon", aliases: []string{"CA12", "YK"}},
+	{Code: "CA-NT", Name: "Northwest Territories", aliases: []string{"CA13"}},
+	{Code: "CA-NU", Name: "Nunavut", aliases: []string{"CA14"}},
+
+	// Mexico. Postal abbreviations vary by source so the common ones are all included.
+	{Code: "MX-AGU", Name: "Aguascalientes", aliases: []string{"MX01", "AG", "AGS"}},
+	{Code: "MX-BCN", Name: "Baja California", aliases: []string{"MX02", "BC"}},
+	{Code: "MX-BCS", Name: "Baja California Sur", aliases: []string{"MX03", "BS"}},
+	{Code: "MX-CAM", Name: "Campeche", aliases: []string{"MX04", "CM", "CAMP"}},
+	{Code: "MX-CHP", Name: "Chiapas", aliases: []string{"MX05", "CS", "CHIS"}},
+	{Code: "MX-CHH", Name: "Chihuahua", aliases: []string{"MX06", "CH", "CHIH"}},
+	{Code: "MX-COA", Name: "Coahuila de Zaragoza", aliases: []string{"MX07", "CO", "COAH"}},
+	{Code: "MX-COL", Name: "Colima", aliases: []string{"MX08", "CL"}},
+	{Code: "MX-CMX", Name: "Ciudad de México", aliases: []string{"MX09", "DF", "CDMX"}},
+	{Code: "MX-DUR", Name: "Durango", aliases: []string{"MX10", "DG", "DGO"}},
+	{Code: "MX-GUA", Name: "Guanajuato", aliases: []string{"MX11", "GT", "GTO"}},
+	{Code: "MX-GRO", Name: "Guerrero", aliases: []string{"MX12", "GR"}},
+	{Code: "MX-HID", Name: "Hidalgo", aliases: []string{"MX13", "HG", "HGO"}},
+	{Code: "MX-JAL", Name: "Jalisco", aliases: []string{"MX14", "JA", "JC"}},
+	{Code: "MX-MEX", Name: "México", aliases: []string{"MX15", "EM", "MX", "EDOMEX"}},
+	{Code: "MX-MIC", Name: "Michoacán de Ocampo", aliases: []string{"MX16", "MI", "MN", "MICH"}},
+	{Code: "MX-MOR", Name: "Morelos", aliases: []string{"MX17", "MO"}},
+	{Code: "MX-NAY", Name: "Nayarit", aliases: []string{"MX18", "NA", "NT"}},
+	{Code: "MX-NLE", Name: "Nuevo León", aliases: []string{"MX19", "NL"}},
+	{Code: "MX-OAX", Name: "Oaxaca", aliases: []string{"MX20", "OA"}},
+	{Code: "MX-PUE", Name: "Puebla", aliases: []string{"MX21", "PU", "PB"}},
+	{Code: "MX-QUE", Name: "Querétaro", aliases: []string{"MX22", "QT", "QRO"}},
+	{Code: "MX-ROO", Name: "Quintana Roo", aliases: []string{"MX23", "QR", "QROO"}},
+	{Code: "MX-SLP", Name: "San Luis Potosí", aliases: []string{"MX24", "SL"}},
+	{Code: "MX-SIN", Name: "Sinaloa", aliases: []string{"MX25", "SI"}},
+	{Code: "MX-SON", Name: "Sonora", aliases: []string{"MX26", "SO"}},
+	{Code: "MX-TAB", Name: "Tabasco", aliases: []string{"MX27", "TB"}},
+	{Code: "MX-TAM", Name: "Tamaulipas", aliases: []string{"MX28", "TM", "TAMPS"}},
+	{Code: "MX-TLA", Name: "Tlaxcala", aliases: []string{"MX29", "TL"}},
+	{Code: "MX-VER", Name: "Veracruz de Ignacio de la Llave", aliases: []string{"MX30", "VE", "VZ"}},
+	{Code: "MX-YUC", Name: "Yucatán", aliases: []string{"MX31", "YU"}},
+	{Code: "MX-ZAC", Name: "Zacatecas", aliases: []string{"MX32", "ZA"}},
+
+	// Australia.
+	{Code: "AU-ACT", Name: "Australian Capital Territory", aliases: []string{"AS01"}},
+	{Code: "AU-NSW", Name: "New South Wales", aliases: []string{"AS02"}},
+	{Code: "AU-NT", Name: "Northern Territory", aliases: []string{"AS03"}},
+	{Code: "AU-QLD", Name: "Queensland", aliases: []string{"AS04"}},
+	{Code: "AU-SA", Name: "South Australia", aliases: []string{"AS05"}},
+	{Code: "AU-TAS", Name: "Tasmania", aliases: []string{"AS06"}},
+	{Code: "AU-VIC", Name: "Victoria", aliases: []string{"AS07"}},
+	{Code: "AU-WA", Name: "Western Australia", aliases: []string{"AS08"}},
+}
@@ -0,0 +1,134 @@
+package geography
+
+import "testing"
+
+func TestSubdivisionFor(t *testing.T) {
+	tests := []struct {
+		region   string
+		code     string
+		wantCode string
+		wantName string
+		wantOK   bool
+	}{
+		// Empty and unknown inputs.
+		{},
+		{
+			region: "US",
+		},
+		{
+			code: "CA",
+		},
+		{
+			region: "US",
+			code:   "ZZ",
+		},
+		{
+			// Right code, wrong region.
+			region: "CA",
+			code:   "TX",
+		},
+		// US state in all the supported forms.
+		{
+			region:   "US",
+			code:     "CA",
+			wantCode: "US-CA",
+			wantName: "California",
+			wantOK:   true,
+		},
+		{
+			region:   "us",
+			code:     " us-ca ",
+			wantCode: "US-CA",
+			wantName: "California",
+			wantOK:   true,
+		},
+		{
+			region:   "US",
+			code:     "06",
+			wantCode: "US-CA",
+			wantName: "California",
+			wantOK:   true,
+		},
+		{
+			region:   "US",
+			code:     "US06",
+			wantCode: "US-CA",
+			wantName: "California",
+			wantOK:   true,
+		},
+		// Canada.
+		{
+			region:   "CA",
+			code:     "ON",
+			wantCode: "CA-ON",
+			wantName: "Ontario",
+			wantOK:   true,
+		},
+		{
+			region:   "CA",
+			code:     "CA08",
+			wantCode: "CA-ON",
+			wantName: "Ontario",
+			wantOK:   true,
+		},
+		{
+			region:   "CA",
+			code:     "NF",
+			wantCode: "CA-NL",
+			wantName: "Newfoundland and Labrador",
+			wantOK:   true,
+		},
+		// Mexico.
+		{
+			region:   "MX",
+			code:     "CHIH",
+			wantCode: "MX-CHH",
+			wantName: "Chihuahua",
+			wantOK:   true,
+		},
+		{
+			region:   "MX",
+			code:     "MX06",
+			wantCode: "MX-CHH",
+			wantName: "Chihuahua",
+			wantOK:   true,
+		},
+		{
+			region:   "MX",
+			code:     "DF",
+			wantCode: "MX-CMX",
+			wantName: "Ciudad de México",
+			wantOK:   true,
+		},
+		// Australia.
+		{
+			region:   "AU",
+			code:     "NSW",
+			wantCode: "AU-NSW",
+			wantName: "New South Wales",
+			wantOK:   true,
+		},
+		{
+			region:   "AU",
+			code:     "AS02",
+			wantCode: "AU-NSW",
+			wantName: "New South Wales",
+			wantOK:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := SubdivisionFor(test.region, test.code)
+		if ok != test.wantOK {
+			t.Errorf("SubdivisionFor(%q, %q) = _, %v, want %v", test.region, test.code, ok, test.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got.Code != test.wantCode || got.Name != test.wantName {
+			t.Errorf("SubdivisionFor(%q, %q) = %q, %q, want %q, %q",
+				test.region, test.code, got.Code, got.Name, test.wantCode, test.wantName)
+		}
+	}
+}
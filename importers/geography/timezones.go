@@ -0,0 +1,219 @@
+package geography
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed" // For the timezone boundaries.
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	_ "time/tzdata" // So zones can be loaded without the system zoneinfo.
+)
+
+// timezonesGeoJSON holds the gzipped timezone boundary polygons in the same
+// GeoJSON form as the releases from github.com/evansiroky/timezone-boundary-builder,
+// (a FeatureCollection with a "tzid" property on each feature).
+//
+// The file is the 2025b release without its ocean zones, simplified to about
+// a kilometer and with coordinates rounded to 3 decimal places. Points within
+// a kilometer or so of a zone line, or on small islands dropped by the
+// simplification, may resolve to a neighboring zone or the nautical zone. The
+// data is © OpenStreetMap contributors, available under the ODbL, (see
+// data/README.md).
+//
+//go:embed data/timezones.geojson.gz
+var timezonesGeoJSON []byte
+
+// tzPolygon is one polygon of a timezone. The first ring is the outer boundary
+// and any others are holes. Points are stored as [lng, lat] as in GeoJSON.
+type tzPolygon struct {
+	tzid  string
+	rings [][][2]float64
+
+	// The bounding box of the outer ring, to skip most polygons cheaply.
+	minLat, maxLat, minLng, maxLng float64
+}
+
+var (
+	tzOnce     sync.Once
+	tzPolygons []*tzPolygon
+	tzErr      error
+)
+
+// loadTimezones parses the embedded boundaries. The features are kept in file
+// order and the first one containing a point wins, so smaller zones which
+// overlap a larger one must come first in the file.
+func loadTimezones() {
+	zr, err := gzip.NewReader(bytes.NewReader(timezonesGeoJSON))
+	if err != nil {
+		tzErr = fmt.Errorf("geography: reading timezone boundaries: %v", err)
+		return
+	}
+	defer zr.Close()
+
+	var fc struct {
+		Features []struct {
+			Properties struct {
+				TZID string `json:"tzid"`
+			} `json:"properties"`
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(zr).Decode(&fc); err != nil {
+		tzErr = fmt.Errorf("geography: parsing timezone boundaries: %v", err)
+		return
+	}
+
+	for _, f := range fc.Features {
+		var polys [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var p [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &p); err != nil {
+				tzErr = fmt.Errorf("geography: parsing timezone %q: %v", f.Properties.TZID, err)
+				return
+			}
+			polys = append(polys, p)
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polys); err != nil {
+				tzErr = fmt.Errorf("geography: parsing timezone %q: %v", f.Properties.TZID, err)
+				return
+			}
+		default:
+			tzErr = fmt.Errorf("geography: timezone %q has unsupported geometry %q", f.Properties.TZID, f.Geometry.Type)
+			return
+		}
+
+		for _, rings := range polys {
+			if len(rings) == 0 || len(rings[0]) == 0 {
+				continue
+			}
+			p := &tzPolygon{
+				tzid:   f.Properties.TZID,
+				rings:  rings,
+				minLat: math.Inf(1), maxLat: math.Inf(-1),
+				minLng: math.Inf(1), maxLng: math.Inf(-1),
+			}
+			for _, pt := range rings[0] {
+				p.minLng = math.Min(p.minLng, pt[0])
+				p.maxLng = math.Max(p.maxLng, pt[0])
+				p.minLat = math.Min(p.minLat, pt[1])
+				p.maxLat = math.Max(p.maxLat, pt[1])
+			}
+			tzPolygons = append(tzPolygons, p)
+		}
+	}
+}
+
+// contains reports if the point is inside the polygon and outside all of its holes.
+func (p *tzPolygon) contains(lat, lng float64) bool {
+	if lat < p.minLat || lat > p.maxLat || lng < p.minLng || lng > p.maxLng {
+		return false
+	}
+	if !ringContains(p.rings[0], lat, lng) {
+		return false
+	}
+	for _, hole := range p.rings[1:] {
+		if ringContains(hole, lat, lng) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains uses the standard even-odd ray casting test, treating the
+// coordinates as planar. This is fine at the scale of the boundary data, but
+// rings must not cross the antimeridian; split them into two polygons instead.
+func ringContains(ring [][2]float64, lat, lng float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Timezone returns the IANA timezone name for the given point.
+//
+// Points which are not inside any of the embedded boundaries, (at sea, beyond
+// the territorial waters included with the land zones), get the nautical
+// timezone for their longitude, e.g. "Etc/GMT+8" for 120°W. Note the POSIX style inverted sign
+// on the Etc zones, "Etc/GMT+8" is 8 hours behind UTC.
+func Timezone(lat, lng float64) string {
+	tzOnce.Do(loadTimezones)
+	if tzErr == nil {
+		for _, p := range tzPolygons {
+			if p.contains(lat, lng) {
+				return p.tzid
+			}
+		}
+	}
+	return nauticalTimezone(lng)
+}
+
+// nauticalTimezone returns the Etc zone for the 15° wide band of longitude
+// containing lng.
+func nauticalTimezone(lng float64) string {
+	offset := int(math.Round(lng / 15))
+	switch {
+	case offset > 12:
+		offset = 12
+	case offset < -12:
+		offset = -12
+	}
+
+	switch {
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	case offset < 0:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+	return "Etc/GMT"
+}
+
+// StandardOffset returns the offset from UTC of the standard (non daylight
+// saving) time in the named zone at the given instant. Several sources report
+// times in local standard time all year round, and this is the offset needed
+// to convert them to UTC.
+func StandardOffset(tz string, t time.Time) (time.Duration, error) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return 0, fmt.Errorf("geography: unknown timezone %q: %v", tz, err)
+	}
+
+	lt := t.In(loc)
+	_, offset := lt.Zone()
+	if lt.IsDST() {
+		// Zones may have had a different standard offset in the past, so use
+		// the nearest non DST instant rather than assuming an hour of DST.
+		for _, d := range []time.Duration{-1, 1, -2, 2, -3, 3, -4, 4, -5, 5, -6, 6} {
+			st := lt.Add(d * 30 * 24 * time.Hour)
+			if !st.IsDST() {
+				_, offset = st.Zone()
+				break
+			}
+		}
+	}
+	return time.Duration(offset) * time.Second, nil
+}
+
+// LocalStandardToUTC converts a wall clock time recorded in local standard time
+// in the named zone to UTC. Only the year, month, day, hour, minute, second and
+// nanosecond of local are used; its location is ignored.
+func LocalStandardToUTC(local time.Time, tz string) (time.Time, error) {
+	wall := time.Date(local.Year(), local.Month(), local.Day(),
+		local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+	offset, err := StandardOffset(tz, wall)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return wall.Add(-offset), nil
+}
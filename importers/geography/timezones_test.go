@@ -0,0 +1,102 @@
+package geography
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lng float64
+		want     string
+	}{
+		{name: "San Francisco", lat: 37.7749, lng: -122.4194, want: "America/Los_Angeles"},
+		{name: "Seattle", lat: 47.6062, lng: -122.3321, want: "America/Los_Angeles"},
+		{name: "Coeur d'Alene", lat: 47.6777, lng: -116.7805, want: "America/Los_Angeles"},
+		{name: "Boise", lat: 43.6150, lng: -116.2023, want: "America/Boise"},
+		{name: "Phoenix", lat: 33.4484, lng: -112.0740, want: "America/Phoenix"},
+		{name: "Denver", lat: 39.7392, lng: -104.9903, want: "America/Denver"},
+		{name: "El Paso", lat: 31.7619, lng: -106.4850, want: "America/Denver"},
+		{name: "Chicago", lat: 41.8781, lng: -87.6298, want: "America/Chicago"},
+		{name: "Nashville", lat: 36.1627, lng: -86.7816, want: "America/Chicago"},
+		{name: "Indianapolis", lat: 39.7684, lng: -86.1581, want: "America/Indiana/Indianapolis"},
+		{name: "Detroit", lat: 42.3314, lng: -83.0458, want: "America/Detroit"},
+		{name: "Atlanta", lat: 33.7490, lng: -84.3880, want: "America/New_York"},
+		{name: "New York", lat: 40.7128, lng: -74.0060, want: "America/New_York"},
+		{name: "Boston", lat: 42.3601, lng: -71.0589, want: "America/New_York"},
+		{name: "Key West", lat: 24.5551, lng: -81.7800, want: "America/New_York"},
+		{name: "Anchorage", lat: 61.2181, lng: -149.9003, want: "America/Anchorage"},
+		{name: "Juneau", lat: 58.3019, lng: -134.4197, want: "America/Juneau"},
+		{name: "Adak", lat: 51.8800, lng: -176.6581, want: "America/Adak"},
+		{name: "Attu", lat: 52.9300, lng: 173.1800, want: "America/Adak"},
+		{name: "Honolulu", lat: 21.3069, lng: -157.8583, want: "Pacific/Honolulu"},
+		{name: "San Juan", lat: 18.4655, lng: -66.1057, want: "America/Puerto_Rico"},
+		{name: "Windsor", lat: 42.3149, lng: -83.0364, want: "America/Toronto"},
+		{name: "Mexico City", lat: 19.4326, lng: -99.1332, want: "America/Mexico_City"},
+		{name: "Tijuana", lat: 32.5149, lng: -117.0382, want: "America/Tijuana"},
+		{name: "Greenwich", lat: 51.4779, lng: -0.0015, want: "Europe/London"},
+		{name: "Paris", lat: 48.8566, lng: 2.3522, want: "Europe/Paris"},
+		{name: "Tokyo", lat: 35.6762, lng: 139.6503, want: "Asia/Tokyo"},
+		{name: "Kolkata", lat: 22.5726, lng: 88.3639, want: "Asia/Kolkata"},
+		{name: "Sydney", lat: -33.8688, lng: 151.2093, want: "Australia/Sydney"},
+		{name: "Auckland", lat: -36.8485, lng: 174.7633, want: "Pacific/Auckland"},
+		{name: "Buenos Aires", lat: -34.6037, lng: -58.3816, want: "America/Argentina/Buenos_Aires"},
+		{name: "McMurdo", lat: -77.8419, lng: 166.6863, want: "Antarctica/McMurdo"},
+		{name: "Mid Pacific", lat: 0, lng: -150, want: "Etc/GMT+10"},
+		{name: "Date line east", lat: 0, lng: -179.9, want: "Etc/GMT+12"},
+		{name: "Date line west", lat: 0, lng: 179.9, want: "Etc/GMT-12"},
+	}
+
+	for _, test := range tests {
+		if got := Timezone(test.lat, test.lng); got != test.want {
+			t.Errorf("Timezone(%v, %v) [%s] = %q, want %q", test.lat, test.lng, test.name, got, test.want)
+		}
+	}
+}
+
+func TestLocalStandardToUTC(t *testing.T) {
+	tests := []struct {
+		local time.Time
+		tz    string
+		want  time.Time
+	}{
+		{
+			// Winter, standard time is also the wall clock time.
+			local: time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC),
+			tz:    "America/Los_Angeles",
+			want:  time.Date(2023, 1, 15, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			// Summer, LST ignores daylight saving time.
+			local: time.Date(2023, 7, 15, 12, 0, 0, 0, time.UTC),
+			tz:    "America/Los_Angeles",
+			want:  time.Date(2023, 7, 15, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			local: time.Date(2023, 7, 15, 12, 0, 0, 0, time.UTC),
+			tz:    "America/Phoenix",
+			want:  time.Date(2023, 7, 15, 19, 0, 0, 0, time.UTC),
+		},
+		{
+			local: time.Date(2023, 7, 15, 0, 30, 0, 0, time.UTC),
+			tz:    "Etc/GMT-10",
+			want:  time.Date(2023, 7, 14, 14, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		got, err := LocalStandardToUTC(test.local, test.tz)
+		if err != nil {
+			t.Errorf("LocalStandardToUTC(%v, %q) unexpected error: %v", test.local, test.tz, err)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("LocalStandardToUTC(%v, %q) = %v, want %v", test.local, test.tz, got, test.want)
+		}
+	}
+
+	if _, err := LocalStandardToUTC(time.Now(), "Not/AZone"); err == nil {
+		t.Errorf("LocalStandardToUTC with an unknown zone should have failed")
+	}
+}
@@ -0,0 +1,316 @@
+package merge
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// locationFields are the Geography fields set together from LocationField.
+var locationFields = map[string]bool{
+	"Lat":      true,
+	"Lng":      true,
+	"LatE7":    true,
+	"LngE7":    true,
+	"Datum":    true,
+	"S2CellID": true,
+	"GeoHash":  true,
+	"PlusCode": true,
+	"H3Cell":   true,
+	"Timezone": true,
+}
+
+// regionFields are the Geography fields set together from RegionField.
+var regionFields = map[string]bool{
+	"Continent":        true,
+	"MetaRegion":       true,
+	"RegionName":       true,
+	"RegionCode":       true,
+	"Subdivision1Name": true,
+	"Subdivision1Code": true,
+	"Subdivision2Name": true,
+	"Subdivision3Name": true,
+}
+
+// combine builds the merged Station for one cluster of candidates, choosing
+// each fields value with the policy and recording the values which lost out
+// as Conflicts. The candidates are not modified.
+func combine(cluster []*Candidate, policy Policy) *ds.Station {
+	if len(cluster) == 1 {
+		return cluster[0].Station
+	}
+
+	out := ds.EmptyStation()
+	c := &combiner{policy: policy, out: out}
+
+	c.choose("ID", cluster, func(s *ds.Station) reflect.Value { return reflect.ValueOf(&s.ID).Elem() })
+	c.choose("Name", cluster, func(s *ds.Station) reflect.Value { return reflect.ValueOf(&s.Name).Elem() })
+
+	idType := reflect.TypeOf(ds.Identifiers{})
+	for i := 0; i < idType.NumField(); i++ {
+		i := i
+		c.choose("Identifiers."+idType.Field(i).Name, cluster, func(s *ds.Station) reflect.Value {
+			if s.Identifiers == nil {
+				return reflect.Value{}
+			}
+			return reflect.ValueOf(s.Identifiers).Elem().Field(i)
+		})
+	}
+
+	geoType := reflect.TypeOf(ds.Geography{})
+	for i := 0; i < geoType.NumField(); i++ {
+		if name := geoType.Field(i).Name; locationFields[name] || regionFields[name] {
+			continue
+		}
+		i := i
+		c.choose("Geography."+geoType.Field(i).Name, cluster, func(s *ds.Station) reflect.Value {
+			if s.Geography == nil {
+				return reflect.Value{}
+			}
+			return reflect.ValueOf(s.Geography).Elem().Field(i)
+		})
+	}
+	c.chooseLocation(cluster)
+	c.chooseRegion(cluster)
+
+	for _, cand := range cluster {
+		s := cand.Station
+		if s.Identifiers != nil {
+			out.Identifiers.RegionalAviationCodes = mergeMaps(out.Identifiers.RegionalAviationCodes, s.Identifiers.RegionalAviationCodes)
+			// Each region can have IDs from several authorities, so
+			// these are the union of the sources rather than chosen.
+			for region, ids := range s.Identifiers.RegionalIDs {
+				out.Identifiers.AddRegionalID(region, ids)
+			}
+		}
+
+		// The period of record is the span across all of the sources.
+		if !s.StartDate.IsZero() && (out.StartDate.IsZero() || s.StartDate.Before(out.StartDate)) {
+			out.StartDate = s.StartDate
+		}
+		if s.EndDate.After(out.EndDate) {
+			out.EndDate = s.EndDate
+		}
+		if s.LastUpdated.After(out.LastUpdated) {
+			out.LastUpdated = s.LastUpdated
+		}
+		out.Coverage = mergeCoverage(out.Coverage, s.Coverage)
+		out.Attributions.Merge(s.Attributions)
+		out.Conflicts = append(out.Conflicts, s.Conflicts...)
+	}
+	out.Conflicts = append(out.Conflicts, c.conflicts...)
+	return out
+}
+
+// combiner holds the state while choosing the fields of a merged station.
+type combiner struct {
+	policy    Policy
+	out       *ds.Station
+	conflicts []*ds.Conflict
+}
+
+// choose sets the field in the output station, (found by get), to the best of
+// the candidates values under the policy.
+func (c *combiner) choose(field string, cluster []*Candidate, get func(*ds.Station) reflect.Value) {
+	var values []*value
+	var best *value
+	for _, cand := range cluster {
+		v := get(cand.Station)
+		if !v.IsValid() || !isSet(v) || !isScalar(v) {
+			continue
+		}
+		val := &value{candidate: cand, formatted: format(v), precision: precision(v)}
+		values = append(values, val)
+		if best == nil {
+			best = val
+		} else if better, _ := c.policy.compare(field, val, best); better {
+			best = val
+		}
+	}
+	if best == nil {
+		return
+	}
+
+	get(c.out).Set(get(best.candidate.Station))
+	c.record(field, best, values)
+}
+
+// chooseLocation sets the coordinates and all the fields derived from them
+// from one candidate, so that they stay consistent with each other.
+func (c *combiner) chooseLocation(cluster []*Candidate) {
+	c.chooseGroup(LocationField, locationFields, cluster, func(g *ds.Geography) *value {
+		if g.Lat == 0 && g.Lng == 0 {
+			return nil
+		}
+		lat, lng := reflect.ValueOf(g.Lat), reflect.ValueOf(g.Lng)
+		return &value{
+			formatted: format(lat) + "," + format(lng),
+			precision: min(precision(lat), precision(lng)),
+		}
+	})
+}
+
+// chooseRegion sets the region, the subdivisions within it and the areas it
+// is part of from one candidate, so that a subdivision is never paired with
+// another sources region. A candidate which names the subdivision is more
+// precise than one which only has the region.
+func (c *combiner) chooseRegion(cluster []*Candidate) {
+	c.chooseGroup(RegionField, regionFields, cluster, func(g *ds.Geography) *value {
+		if g.RegionCode == "" {
+			return nil
+		}
+		val := &value{formatted: g.RegionCode}
+		if g.Subdivision1Code != "" {
+			val.formatted += "," + g.Subdivision1Code
+			val.precision = 1
+		}
+		return val
+	})
+}
+
+// chooseGroup sets the Geography fields in the group from the best of the
+// candidates under the policy for field. describe returns the value to
+// compare the candidates by, or nil if the candidate has none.
+func (c *combiner) chooseGroup(field string, group map[string]bool, cluster []*Candidate, describe func(*ds.Geography) *value) {
+	var values []*value
+	var best *value
+	for _, cand := range cluster {
+		if cand.Station.Geography == nil {
+			continue
+		}
+		val := describe(cand.Station.Geography)
+		if val == nil {
+			continue
+		}
+		val.candidate = cand
+		values = append(values, val)
+		if best == nil {
+			best = val
+		} else if better, _ := c.policy.compare(field, val, best); better {
+			best = val
+		}
+	}
+	if best == nil {
+		return
+	}
+
+	src, dst := reflect.ValueOf(best.candidate.Station.Geography).Elem(), reflect.ValueOf(c.out.Geography).Elem()
+	for name := range group {
+		dst.FieldByName(name).Set(src.FieldByName(name))
+	}
+	c.record(field, best, values)
+}
+
+// record adds a Conflict for each value which differs from the chosen one.
+func (c *combiner) record(field string, best *value, values []*value) {
+	for _, v := range values {
+		if v == best || v.formatted == best.formatted {
+			continue
+		}
+		_, reason := c.policy.compare(field, best, v)
+		c.conflicts = append(c.conflicts, &ds.Conflict{
+			Field:        field,
+			Source:       v.candidate.Source,
+			Value:        v.formatted,
+			ChosenSource: best.candidate.Source,
+			Reason:       reason,
+		})
+	}
+}
+
+// isScalar reports if the value is one of the string or numeric kinds the
+// policy chooses between.
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Int, reflect.Int32, reflect.Int64,
+		reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isSet reports if the field has a value. Signed numeric fields are unset only
+// if they are ds.UnsetValue, as zero is a real value, e.g. an elevation at sea
+// level.
+func isSet(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return v.Int() != ds.UnsetValue
+	case reflect.Float32, reflect.Float64:
+		return v.Float() != ds.UnsetValue
+	}
+	return !v.IsZero()
+}
+
+// format returns the value as a string for comparing and reporting.
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// precision returns the number of decimal places in the shortest form of a
+// floating point value, and 0 for everything else.
+func precision(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		s := format(v)
+		if i := strings.IndexByte(s, '.'); i >= 0 {
+			return len(s) - i - 1
+		}
+	}
+	return 0
+}
+
+// mergeMaps returns dst with any keys only in src added.
+func mergeMaps(dst, src map[string]string) map[string]string {
+	for k, v := range src {
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+// mergeCoverage returns the union of the coverage, widening the years for
+// elements in both, sorted by element.
+func mergeCoverage(a, b []*ds.ElementCoverage) []*ds.ElementCoverage {
+	if len(b) == 0 {
+		return a
+	}
+
+	byElement := map[string]*ds.ElementCoverage{}
+	var out []*ds.ElementCoverage
+	for _, list := range [][]*ds.ElementCoverage{a, b} {
+		for _, c := range list {
+			have, ok := byElement[c.Element]
+			if !ok {
+				cp := *c
+				byElement[c.Element] = &cp
+				out = append(out, &cp)
+				continue
+			}
+			if c.FirstYear < have.FirstYear {
+				have.FirstYear = c.FirstYear
+			}
+			if c.LastYear > have.LastYear {
+				have.LastYear = c.LastYear
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Element < out[j].Element
+	})
+	return out
+}
@@ -0,0 +1,11 @@
+/*
+Package merge consolidates the partial Station records from each of the data
+sources into one Station per physical site.
+
+Every source has its own view of a station. GHCN-D knows its WMO and GHCN IDs,
+the ISD history knows its USAF and WBAN IDs and ICAO code, ASOS knows its ICAO
+and WBAN, and so on. Records are matched when they share an identifier, or when
+they are close together and have similar names. Records that match nothing
+are passed through unchanged.
+*/
+package merge
@@ -0,0 +1,202 @@
+package merge
+
+import (
+	"math"
+	"sort"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	// earthRadiusMeters is the mean radius of the earth.
+	earthRadiusMeters = 6371008.8
+	// metersPerDegree is the length of one degree of latitude.
+	metersPerDegree = earthRadiusMeters * math.Pi / 180
+
+	// The ISD history uses these for stations without the given identifier.
+	missingUsafID = "999999"
+	missingWbanID = "99999"
+)
+
+// clusters is a union-find over the candidates, which also tracks the sources
+// in each cluster, so that two records from the same source are never merged.
+// Each source is taken to already have one record per station.
+type clusters struct {
+	parent  []int
+	sources []map[string]bool
+}
+
+func newClusters(candidates []*Candidate) *clusters {
+	c := &clusters{
+		parent:  make([]int, len(candidates)),
+		sources: make([]map[string]bool, len(candidates)),
+	}
+	for i, cand := range candidates {
+		c.parent[i] = i
+		c.sources[i] = map[string]bool{cand.Source: true}
+	}
+	return c
+}
+
+func (c *clusters) find(i int) int {
+	for c.parent[i] != i {
+		c.parent[i] = c.parent[c.parent[i]]
+		i = c.parent[i]
+	}
+	return i
+}
+
+// union joins the clusters containing a and b, returning false if they could
+// not be joined because they both have a record from the same source.
+func (c *clusters) union(a, b int) bool {
+	ra, rb := c.find(a), c.find(b)
+	if ra == rb {
+		return true
+	}
+	for src := range c.sources[rb] {
+		if c.sources[ra][src] {
+			return false
+		}
+	}
+
+	// Keep the lowest index as the root so the output order is stable.
+	if rb < ra {
+		ra, rb = rb, ra
+	}
+	c.parent[rb] = ra
+	for src := range c.sources[rb] {
+		c.sources[ra][src] = true
+	}
+	c.sources[rb] = nil
+	return true
+}
+
+// identifierKeys returns the keys of the identifiers which are unique to one
+// physical station, prefixed by the kind of identifier.
+func identifierKeys(ids *ds.Identifiers) []string {
+	if ids == nil {
+		return nil
+	}
+
+	var keys []string
+	if ids.WmoID != "" {
+		keys = append(keys, "wmo:"+ids.WmoID)
+	}
+	if ids.ICAO != "" {
+		keys = append(keys, "icao:"+ids.ICAO)
+	}
+	if ids.GhcnID != "" {
+		keys = append(keys, "ghcn:"+ids.GhcnID)
+	}
+	if ids.GhcnIDAlt != "" {
+		keys = append(keys, "ghcn:"+ids.GhcnIDAlt)
+	}
+	// USAF and WBAN IDs are only unique as a pair, and either half may be
+	// the placeholder for a station without one.
+	if ids.UsafID != "" && ids.WbanID != "" &&
+		(ids.UsafID != missingUsafID || ids.WbanID != missingWbanID) {
+		keys = append(keys, "usaf-wban:"+ids.UsafID+"-"+ids.WbanID)
+	}
+	return keys
+}
+
+// hasLocation reports if the station has coordinates. Sources use 0, 0 when
+// the location is unknown.
+func hasLocation(s *ds.Station) bool {
+	return s.Geography != nil && (s.Geography.Lat != 0 || s.Geography.Lng != 0)
+}
+
+// distanceMeters returns the great circle distance between two points.
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dphi := phi2 - phi1
+	dlambda := (lng2 - lng1) * math.Pi / 180
+
+	h := math.Sin(dphi/2)*math.Sin(dphi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dlambda/2)*math.Sin(dlambda/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// match groups the candidates into clusters of records for the same station.
+// Candidates are first joined on shared identifiers, and then on proximity and
+// name similarity. Clusters are returned ordered by their first candidate, and
+// the candidates within a cluster keep their order from the input.
+func match(candidates []*Candidate, opts Options) [][]*Candidate {
+	c := newClusters(candidates)
+
+	// Shared identifiers.
+	first := map[string]int{}
+	for i, cand := range candidates {
+		for _, key := range identifierKeys(cand.Station.Identifiers) {
+			if j, ok := first[key]; ok {
+				c.union(j, i)
+				continue
+			}
+			first[key] = i
+		}
+	}
+
+	// Proximity and name. Candidates are bucketed into bands of latitude at
+	// least MaxDistanceMeters wide so only neighboring bands need checking.
+	if opts.MaxDistanceMeters > 0 {
+		bandDegrees := opts.MaxDistanceMeters / metersPerDegree
+		bands := map[int][]int{}
+		for i, cand := range candidates {
+			if !hasLocation(cand.Station) {
+				continue
+			}
+			band := int(math.Floor(float64(cand.Station.Geography.Lat) / bandDegrees))
+			bands[band] = append(bands[band], i)
+		}
+
+		var keys []int
+		for band := range bands {
+			keys = append(keys, band)
+		}
+		sort.Ints(keys)
+
+		try := func(i, j int) {
+			if c.find(i) != c.find(j) && nearby(candidates[i].Station, candidates[j].Station, opts) {
+				c.union(i, j)
+			}
+		}
+		for _, band := range keys {
+			in, next := bands[band], bands[band+1]
+			for x, i := range in {
+				for _, j := range in[x+1:] {
+					try(i, j)
+				}
+				for _, j := range next {
+					try(i, j)
+				}
+			}
+		}
+	}
+
+	// Gather up the clusters.
+	var out [][]*Candidate
+	index := map[int]int{}
+	for i, cand := range candidates {
+		root := c.find(i)
+		n, ok := index[root]
+		if !ok {
+			n = len(out)
+			index[root] = n
+			out = append(out, nil)
+		}
+		out[n] = append(out[n], cand)
+	}
+	return out
+}
+
+// nearby reports if the two stations are within the match distance of each
+// other and have similar enough names.
+func nearby(a, b *ds.Station, opts Options) bool {
+	d := distanceMeters(
+		float64(a.Geography.Lat), float64(a.Geography.Lng),
+		float64(b.Geography.Lat), float64(b.Geography.Lng))
+	if d > opts.MaxDistanceMeters {
+		return false
+	}
+	return nameSimilarity(a.Name, b.Name) >= opts.MinNameSimilarity
+}
@@ -0,0 +1,141 @@
+package merge
+
+import (
+	"sort"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Source is one sources partial stations to be merged.
+type Source struct {
+	// Name identifies the source, e.g. "ghcnd".
+	Name string
+	// Stations is a PCollection<*ds.Station>.
+	Stations beam.PCollection
+}
+
+// Candidate is a station from one source along with where it came from.
+type Candidate struct {
+	// Source is the name of the source the station came from.
+	Source string
+	// Rank is the position of the source in the call to Stations. Lower
+	// ranked sources are preferred when the candidates are combined.
+	Rank int
+	// Station is the stations record as read from the source.
+	Station *ds.Station
+}
+
+// Options controls how candidates are matched to each other.
+type Options struct {
+	// MaxDistanceMeters is the furthest apart two candidates can be and still
+	// be matched by proximity and name.
+	MaxDistanceMeters float64
+	// MinNameSimilarity is the lowest name similarity, in [0, 1], for two
+	// nearby candidates to be matched.
+	MinNameSimilarity float64
+
+	// Policy decides which sources value is used for each field of a merged
+	// station.
+	Policy Policy
+}
+
+// DefaultOptions are the Options used by Stations.
+var DefaultOptions = Options{
+	MaxDistanceMeters: 2000,
+	MinNameSimilarity: 0.5,
+}
+
+func init() {
+	register.DoFn2x0[*ds.Station, func(string, *Candidate)](&tagFn{})
+	register.DoFn3x0[string, func(**Candidate) bool, func(*ds.Station)](&mergeFn{})
+	register.Emitter2[string, *Candidate]()
+	register.Iter1[*Candidate]()
+}
+
+// Stations merges the stations from all of the sources, returning a
+// PCollection<*ds.Station> with one Station per physical site. Sources are
+// given in order of preference, which is used to break any ties left by the
+// Options Policy.
+func Stations(s beam.Scope, sources ...Source) beam.PCollection {
+	return StationsWithOptions(s, DefaultOptions, sources...)
+}
+
+// StationsWithOptions is Stations with the given matching Options.
+func StationsWithOptions(s beam.Scope, opts Options, sources ...Source) beam.PCollection {
+	s = s.Scope("merge.Stations")
+
+	var tagged []beam.PCollection
+	for i, src := range sources {
+		tagged = append(tagged, beam.ParDo(s, &tagFn{Source: src.Name, Rank: i}, src.Stations))
+	}
+
+	// Identifier matches can span any distance, (a source may have wildly
+	// wrong coordinates), so all of the candidates are grouped together and
+	// matched in memory. There are a few hundred thousand stations across all
+	// of the sources, so this fits comfortably on one worker.
+	grouped := beam.GroupByKey(s, beam.Flatten(s, tagged...))
+	return beam.ParDo(s, &mergeFn{Options: opts}, grouped)
+}
+
+// tagFn wraps each station in a Candidate for its source.
+type tagFn struct {
+	Source string
+	Rank   int
+}
+
+func (fn *tagFn) ProcessElement(st *ds.Station, emit func(string, *Candidate)) {
+	emit("", &Candidate{Source: fn.Source, Rank: fn.Rank, Station: st})
+}
+
+// mergeFn runs Merge over the grouped candidates.
+type mergeFn struct {
+	Options Options
+}
+
+func (fn *mergeFn) ProcessElement(_ string, candidates func(**Candidate) bool, emit func(*ds.Station)) {
+	var all []*Candidate
+	var c *Candidate
+	for candidates(&c) {
+		all = append(all, c)
+	}
+	for _, st := range MergeWithOptions(all, fn.Options) {
+		emit(st)
+	}
+}
+
+// Merge matches the candidates using the DefaultOptions and returns one Station
+// for each group of matched candidates.
+func Merge(candidates []*Candidate) []*ds.Station {
+	return MergeWithOptions(candidates, DefaultOptions)
+}
+
+// MergeWithOptions matches the candidates and returns one Station for each
+// group of matched candidates, including groups of one.
+func MergeWithOptions(candidates []*Candidate, opts Options) []*ds.Station {
+	// Make the output independent of the order the candidates arrived in.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rank != candidates[j].Rank {
+			return candidates[i].Rank < candidates[j].Rank
+		}
+		return stationSortKey(candidates[i].Station) < stationSortKey(candidates[j].Station)
+	})
+
+	var out []*ds.Station
+	for _, cluster := range match(candidates, opts) {
+		out = append(out, combine(cluster, opts.Policy))
+	}
+	return out
+}
+
+// stationSortKey gives a stable ordering for the stations within one source.
+func stationSortKey(s *ds.Station) string {
+	key := s.Name
+	if s.Identifiers != nil {
+		ids := s.Identifiers
+		key = ids.GhcnID + "|" + ids.UsafID + "|" + ids.WbanID + "|" + ids.WmoID + "|" + ids.ICAO + "|" + key
+	}
+	return key
+}
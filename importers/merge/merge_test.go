@@ -0,0 +1,169 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func station(name string, lat, lng float32, ids ds.Identifiers) *ds.Station {
+	s := ds.EmptyStation()
+	s.Name = name
+	s.Identifiers = &ids
+	s.Geography.Lat = lat
+	s.Geography.Lng = lng
+	return s
+}
+
+func withConflicts(s *ds.Station, conflicts ...*ds.Conflict) *ds.Station {
+	s.Conflicts = conflicts
+	return s
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []*Candidate
+		want       []*ds.Station
+	}{
+		{
+			name: "stragglers pass through",
+			candidates: []*Candidate{
+				{Source: "a", Rank: 0, Station: station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{GhcnID: "USW00023234"})},
+				{Source: "b", Rank: 1, Station: station("SYDNEY", -33.8607, 151.2050, ds.Identifiers{WmoID: "94768"})},
+			},
+			want: []*ds.Station{
+				station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{GhcnID: "USW00023234"}),
+				station("SYDNEY", -33.8607, 151.2050, ds.Identifiers{WmoID: "94768"}),
+			},
+		},
+		{
+			name: "shared wmo id, far apart",
+			candidates: []*Candidate{
+				{Source: "b", Rank: 1, Station: station("SFO", 0, 0, ds.Identifiers{WmoID: "72494", ICAO: "KSFO"})},
+				{Source: "a", Rank: 0, Station: station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494", GhcnID: "USW00023234"})},
+			},
+			want: []*ds.Station{
+				withConflicts(
+					station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494", GhcnID: "USW00023234", ICAO: "KSFO"}),
+					&ds.Conflict{Field: "Name", Source: "b", Value: "SFO", ChosenSource: "a", Reason: ReasonOrder}),
+			},
+		},
+		{
+			name: "usaf-wban pair",
+			candidates: []*Candidate{
+				{Source: "a", Rank: 0, Station: station("X", 10, 10, ds.Identifiers{UsafID: "724940", WbanID: "23234"})},
+				{Source: "b", Rank: 1, Station: station("Y", 20, 20, ds.Identifiers{UsafID: "724940", WbanID: "23234", ICAO: "KSFO"})},
+				// The placeholder pair does not match anything.
+				{Source: "a", Rank: 0, Station: station("P", 30, 30, ds.Identifiers{UsafID: "999999", WbanID: "99999"})},
+				{Source: "b", Rank: 1, Station: station("Q", 40, 40, ds.Identifiers{UsafID: "999999", WbanID: "99999"})},
+			},
+			want: []*ds.Station{
+				withConflicts(
+					station("X", 10, 10, ds.Identifiers{UsafID: "724940", WbanID: "23234", ICAO: "KSFO"}),
+					&ds.Conflict{Field: "Name", Source: "b", Value: "Y", ChosenSource: "a", Reason: ReasonOrder},
+					&ds.Conflict{Field: LocationField, Source: "b", Value: "20,20", ChosenSource: "a", Reason: ReasonOrder}),
+				station("P", 30, 30, ds.Identifiers{UsafID: "999999", WbanID: "99999"}),
+				station("Q", 40, 40, ds.Identifiers{UsafID: "999999", WbanID: "99999"}),
+			},
+		},
+		{
+			name: "nearby with similar names",
+			candidates: []*Candidate{
+				{Source: "a", Rank: 0, Station: station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{GhcnID: "USW00023234"})},
+				{Source: "b", Rank: 1, Station: station("San Francisco International Airport", 37.6190, -122.3750, ds.Identifiers{ICAO: "KSFO"})},
+				// Close by, but a different name.
+				{Source: "b", Rank: 1, Station: station("MILLBRAE", 37.6000, -122.3900, ds.Identifiers{})},
+				// Same name, but too far away.
+				{Source: "c", Rank: 2, Station: station("SAN FRANCISCO INTL AP", 37.9, -122.3656, ds.Identifiers{})},
+			},
+			want: []*ds.Station{
+				withConflicts(
+					station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{GhcnID: "USW00023234", ICAO: "KSFO"}),
+					&ds.Conflict{Field: "Name", Source: "b", Value: "San Francisco International Airport", ChosenSource: "a", Reason: ReasonOrder},
+					&ds.Conflict{Field: LocationField, Source: "b", Value: "37.619,-122.375", ChosenSource: "a", Reason: ReasonPrecision}),
+				station("MILLBRAE", 37.6000, -122.3900, ds.Identifiers{}),
+				station("SAN FRANCISCO INTL AP", 37.9, -122.3656, ds.Identifiers{}),
+			},
+		},
+		{
+			name: "same source records are never merged",
+			candidates: []*Candidate{
+				{Source: "a", Rank: 0, Station: station("ONE", 1, 1, ds.Identifiers{GhcnID: "A1", WmoID: "11111"})},
+				{Source: "a", Rank: 0, Station: station("TWO", 1, 1, ds.Identifiers{GhcnID: "A2", WmoID: "11111"})},
+			},
+			want: []*ds.Station{
+				station("ONE", 1, 1, ds.Identifiers{GhcnID: "A1", WmoID: "11111"}),
+				station("TWO", 1, 1, ds.Identifiers{GhcnID: "A2", WmoID: "11111"}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := Merge(test.candidates)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: Merge() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestCombineSpans(t *testing.T) {
+	a := station("A", 1, 1, ds.Identifiers{WmoID: "1", RegionalIDs: map[string]string{"US": "HCDN:AL293"}})
+	a.StartDate, a.EndDate, a.LastUpdated = ds.Date{Year: 1950, Month: 1, Day: 1}, ds.Date{Year: 1999, Month: 12, Day: 31}, ds.Date{Year: 2023, Month: 1, Day: 1}
+	a.Coverage = []*ds.ElementCoverage{{Element: "TMAX", FirstYear: 1950, LastYear: 1999}}
+	a.Attributions = &ds.Attributions{Datasets: []string{"GHCN-D"}, Networks: []string{"WBAN/ICAO"}}
+	b := station("A", 1, 1, ds.Identifiers{WmoID: "1", RegionalIDs: map[string]string{"US": "EPA:06-075-0005", "CA": "EPA:CC-040-0005"}})
+	b.StartDate, b.EndDate, b.LastUpdated = ds.Date{Year: 1973, Month: 1, Day: 1}, ds.Date{Year: 2023, Month: 6, Day: 30}, ds.Date{Year: 2023, Month: 7, Day: 1}
+	b.Coverage = []*ds.ElementCoverage{
+		{Element: "TMAX", FirstYear: 1973, LastYear: 2023},
+		{Element: "PRCP", FirstYear: 1973, LastYear: 2023},
+	}
+	b.Attributions = &ds.Attributions{Datasets: []string{"ISD"}, Networks: []string{"ASOS", "WBAN/ICAO"}}
+
+	got := combine([]*Candidate{{Source: "a", Station: a}, {Source: "b", Rank: 1, Station: b}}, Policy{})
+	want := station("A", 1, 1, ds.Identifiers{WmoID: "1", RegionalIDs: map[string]string{
+		"US": "EPA:06-075-0005 HCDN:AL293",
+		"CA": "EPA:CC-040-0005",
+	}})
+	want.StartDate, want.EndDate, want.LastUpdated = ds.Date{Year: 1950, Month: 1, Day: 1}, ds.Date{Year: 2023, Month: 6, Day: 30}, ds.Date{Year: 2023, Month: 7, Day: 1}
+	want.Coverage = []*ds.ElementCoverage{
+		{Element: "PRCP", FirstYear: 1973, LastYear: 2023},
+		{Element: "TMAX", FirstYear: 1950, LastYear: 2023},
+	}
+	want.Attributions = &ds.Attributions{Datasets: []string{"GHCN-D", "ISD"}, Networks: []string{"ASOS", "WBAN/ICAO"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("combine() diff (-want +got):\n%s", diff)
+	}
+	// The inputs are left alone.
+	if a.Coverage[0].LastYear != 1999 {
+		t.Errorf("combine() modified its input coverage")
+	}
+}
+
+func TestStations(t *testing.T) {
+	beam.Init()
+	pipeline, scope := beam.NewPipelineWithRoot()
+
+	ghcnd := beam.Create(scope,
+		station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494", GhcnID: "USW00023234"}),
+		station("SYDNEY", -33.8607, 151.2050, ds.Identifiers{WmoID: "94768"}))
+	isd := beam.Create(scope,
+		station("SAN FRANCISCO INTERNATIONAL AIRPORT", 37.62, -122.365, ds.Identifiers{UsafID: "724940", WbanID: "23234", WmoID: "72494"}))
+
+	merged := Stations(scope, Source{Name: "ghcnd", Stations: ghcnd}, Source{Name: "isd", Stations: isd})
+	passert.Equals(scope, merged,
+		withConflicts(
+			station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494", GhcnID: "USW00023234", UsafID: "724940", WbanID: "23234"}),
+			&ds.Conflict{Field: "Name", Source: "isd", Value: "SAN FRANCISCO INTERNATIONAL AIRPORT", ChosenSource: "ghcnd", Reason: ReasonOrder},
+			&ds.Conflict{Field: LocationField, Source: "isd", Value: "37.62,-122.365", ChosenSource: "ghcnd", Reason: ReasonPrecision}),
+		station("SYDNEY", -33.8607, 151.2050, ds.Identifiers{WmoID: "94768"}))
+
+	if err := ptest.Run(pipeline); err != nil {
+		t.Errorf("Failed to execute job: %v", err)
+	}
+}
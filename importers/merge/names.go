@@ -0,0 +1,104 @@
+package merge
+
+import (
+	"strings"
+	"unicode"
+)
+
+// nameAbbreviations expands the abbreviations the sources commonly use in
+// station names so that e.g. "SAN FRANCISCO INTL AP" and "SAN FRANCISCO
+// INTERNATIONAL AIRPORT" compare as the same.
+var nameAbbreviations = map[string]string{
+	"AP":   "AIRPORT",
+	"APT":  "AIRPORT",
+	"ARPT": "AIRPORT",
+	"AIRP": "AIRPORT",
+	"INTL": "INTERNATIONAL",
+	"INT":  "INTERNATIONAL",
+	"MUNI": "MUNICIPAL",
+	"MUN":  "MUNICIPAL",
+	"RGNL": "REGIONAL",
+	"REGL": "REGIONAL",
+	"CO":   "COUNTY",
+	"CNTY": "COUNTY",
+	"MTN":  "MOUNTAIN",
+	"MT":   "MOUNT",
+	"FT":   "FORT",
+	"ST":   "SAINT",
+	"STN":  "STATION",
+	"N":    "NORTH",
+	"S":    "SOUTH",
+	"E":    "EAST",
+	"W":    "WEST",
+	"NR":   "NEAR",
+	"FLD":  "FIELD",
+	"AFB":  "AIR FORCE BASE",
+	"NAS":  "NAVAL AIR STATION",
+	"WSO":  "",
+	"WSFO": "",
+	"WFO":  "",
+	"AWOS": "",
+	"ASOS": "",
+	"THE":  "",
+	"OF":   "",
+}
+
+// normalizeName upper cases the name, drops punctuation, and expands the
+// common abbreviations.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var out []string
+	for _, w := range words {
+		if exp, ok := nameAbbreviations[w]; ok {
+			w = exp
+		}
+		if w != "" {
+			out = append(out, w)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// bigrams returns the counts of the character pairs within each word of s.
+func bigrams(s string) map[string]int {
+	b := map[string]int{}
+	for _, w := range strings.Fields(s) {
+		r := []rune(w)
+		if len(r) == 1 {
+			b[w]++
+			continue
+		}
+		for i := 0; i+1 < len(r); i++ {
+			b[string(r[i:i+2])]++
+		}
+	}
+	return b
+}
+
+// nameSimilarity returns the Sørensen–Dice coefficient of the bigrams in the two
+// normalized names, from 0 for nothing in common to 1 for the same.
+func nameSimilarity(a, b string) float64 {
+	a, b = normalizeName(a), normalizeName(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	ba, bb := bigrams(a), bigrams(b)
+	total, shared := 0, 0
+	for k, n := range ba {
+		total += n
+		if m, ok := bb[k]; ok {
+			shared += min(n, m)
+		}
+	}
+	for _, n := range bb {
+		total += n
+	}
+	return 2 * float64(shared) / float64(total)
+}
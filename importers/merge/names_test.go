@@ -0,0 +1,24 @@
+package merge
+
+import "testing"
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		wantMin float64
+		wantMax float64
+	}{
+		{a: "SAN FRANCISCO INTL AP", b: "San Francisco International Airport", wantMin: 1, wantMax: 1},
+		{a: "SEATTLE TACOMA INTL AP", b: "SEATTLE-TACOMA INTERNATIONAL AIRPORT", wantMin: 1, wantMax: 1},
+		{a: "ATLANTA HARTSFIELD INTL AP", b: "ATLANTA HARTSFIELD-JACKSON INTL", wantMin: 0.7, wantMax: 0.95},
+		{a: "SAN FRANCISCO INTL AP", b: "MILLBRAE", wantMin: 0, wantMax: 0.2},
+		{a: "", b: "MILLBRAE", wantMin: 0, wantMax: 0},
+	}
+
+	for _, test := range tests {
+		got := nameSimilarity(test.a, test.b)
+		if got < test.wantMin || got > test.wantMax {
+			t.Errorf("nameSimilarity(%q, %q) = %v, want in [%v, %v]", test.a, test.b, got, test.wantMin, test.wantMax)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Reasons a value was chosen over another, as recorded in ds.Conflict.
+const (
+	ReasonPrecedence = "precedence"
+	ReasonRecency    = "recency"
+	ReasonPrecision  = "precision"
+	ReasonOrder      = "order"
+)
+
+// LocationField is the field name the policy uses for a stations coordinates,
+// and the fields derived from them, which are always taken together.
+const LocationField = "Geography.Location"
+
+// RegionField is the field name the policy uses for a stations region, its
+// subdivisions and the areas it is part of, which are always taken together.
+const RegionField = "Geography.Region"
+
+// Policy decides which sources value is used for each field when the records
+// for a station are merged.
+//
+// For each field, the candidates with a value are compared by:
+//
+//  1. Precedence: the sources position in the fields list in Fields, or in
+//     Default if the field has no list. Sources not in the list come after
+//     those that are, tied with each other.
+//  2. Recency: the source with the latest LastUpdated.
+//  3. Precision: the value with the most significant decimal places. Only the
+//     coordinates and floating point fields have a precision, and a region
+//     is more precise with its subdivision.
+//  4. Order: the order the sources were given to Stations.
+type Policy struct {
+	// Default is the source precedence used for fields not in Fields.
+	Default []string `json:"default"`
+	// Fields is the source precedence for individual fields, keyed by the
+	// field path, e.g. "Name", "Identifiers.ICAO", "Geography.ElevationMeters",
+	// LocationField or RegionField.
+	Fields map[string][]string `json:"fields"`
+}
+
+// ReadPolicy reads a JSON encoded Policy. e.g.
+//
+//	{
+//	  "default": ["ghcnd", "isd"],
+//	  "fields": {
+//	    "Name": ["isd", "ghcnd"],
+//	    "Geography.ElevationMeters": ["asos"]
+//	  }
+//	}
+func ReadPolicy(r io.Reader) (Policy, error) {
+	var p Policy
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return Policy{}, fmt.Errorf("merge: reading policy: %v", err)
+	}
+	return p, nil
+}
+
+// precedence returns the position of the source in the precedence list for
+// the field, lower being preferred.
+func (p Policy) precedence(field, source string) int {
+	list, ok := p.Fields[field]
+	if !ok {
+		list = p.Default
+	}
+	for i, s := range list {
+		if s == source {
+			return i
+		}
+	}
+	return len(list)
+}
+
+// value is one candidates value for a field.
+type value struct {
+	candidate *Candidate
+	formatted string
+	precision int
+}
+
+// compare returns if a is preferred over b for the field and the reason why.
+func (p Policy) compare(field string, a, b *value) (bool, string) {
+	if pa, pb := p.precedence(field, a.candidate.Source), p.precedence(field, b.candidate.Source); pa != pb {
+		return pa < pb, ReasonPrecedence
+	}
+	if ua, ub := a.candidate.Station.LastUpdated, b.candidate.Station.LastUpdated; ua != ub {
+		return ua.After(ub), ReasonRecency
+	}
+	if a.precision != b.precision {
+		return a.precision > b.precision, ReasonPrecision
+	}
+	return a.candidate.Rank <= b.candidate.Rank, ReasonOrder
+}
@@ -0,0 +1,144 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestReadPolicy(t *testing.T) {
+	got, err := ReadPolicy(strings.NewReader(`{
+		"default": ["ghcnd", "isd"],
+		"fields": {"Name": ["isd"]}
+	}`))
+	if err != nil {
+		t.Fatalf("ReadPolicy() unexpected error: %v", err)
+	}
+	want := Policy{
+		Default: []string{"ghcnd", "isd"},
+		Fields:  map[string][]string{"Name": {"isd"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ReadPolicy() diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := ReadPolicy(strings.NewReader(`{"default": "ghcnd"}`)); err == nil {
+		t.Errorf("ReadPolicy() with a bad policy should have failed")
+	}
+}
+
+func TestCombinePolicy(t *testing.T) {
+	ghcnd := station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494"})
+	ghcnd.Geography.ElevationMeters = 3
+	ghcnd.LastUpdated = ds.Date{Year: 2023, Month: 1, Day: 1}
+
+	isd := station("SAN FRANCISCO INTERNATIONAL AIRPORT", 37.62, -122.365, ds.Identifiers{WmoID: "72494", ICAO: "KSFO"})
+	isd.Geography.ElevationMeters = 4
+	isd.LastUpdated = ds.Date{Year: 2023, Month: 6, Day: 1}
+
+	asos := station("SAN FRANCISCO INTL ARPT", 37.61962, -122.36562, ds.Identifiers{WmoID: "72494"})
+	asos.Geography.ElevationMeters = 2
+	asos.LastUpdated = ds.Date{Year: 2023, Month: 6, Day: 1}
+
+	cluster := []*Candidate{
+		{Source: "ghcnd", Rank: 0, Station: ghcnd},
+		{Source: "isd", Rank: 1, Station: isd},
+		{Source: "asos", Rank: 2, Station: asos},
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   *ds.Station
+	}{
+		{
+			// With no precedence, the more recent sources win, and then the
+			// more precise coordinates, and then the source order.
+			name:   "tie breakers",
+			policy: Policy{},
+			want: func() *ds.Station {
+				s := station("SAN FRANCISCO INTERNATIONAL AIRPORT", 37.61962, -122.36562, ds.Identifiers{WmoID: "72494", ICAO: "KSFO"})
+				s.Geography.ElevationMeters = 4
+				s.LastUpdated = ds.Date{Year: 2023, Month: 6, Day: 1}
+				return withConflicts(s,
+					&ds.Conflict{Field: "Name", Source: "ghcnd", Value: "SAN FRANCISCO INTL AP", ChosenSource: "isd", Reason: ReasonRecency},
+					&ds.Conflict{Field: "Name", Source: "asos", Value: "SAN FRANCISCO INTL ARPT", ChosenSource: "isd", Reason: ReasonOrder},
+					&ds.Conflict{Field: "Geography.ElevationMeters", Source: "ghcnd", Value: "3", ChosenSource: "isd", Reason: ReasonRecency},
+					&ds.Conflict{Field: "Geography.ElevationMeters", Source: "asos", Value: "2", ChosenSource: "isd", Reason: ReasonOrder},
+					&ds.Conflict{Field: LocationField, Source: "ghcnd", Value: "37.6197,-122.3656", ChosenSource: "asos", Reason: ReasonRecency},
+					&ds.Conflict{Field: LocationField, Source: "isd", Value: "37.62,-122.365", ChosenSource: "asos", Reason: ReasonPrecision},
+				)
+			}(),
+		},
+		{
+			name: "precedence",
+			policy: Policy{
+				Default: []string{"ghcnd"},
+				Fields: map[string][]string{
+					"Geography.ElevationMeters": {"asos", "isd"},
+				},
+			},
+			want: func() *ds.Station {
+				s := station("SAN FRANCISCO INTL AP", 37.6197, -122.3656, ds.Identifiers{WmoID: "72494", ICAO: "KSFO"})
+				s.Geography.ElevationMeters = 2
+				s.LastUpdated = ds.Date{Year: 2023, Month: 6, Day: 1}
+				return withConflicts(s,
+					&ds.Conflict{Field: "Name", Source: "isd", Value: "SAN FRANCISCO INTERNATIONAL AIRPORT", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+					&ds.Conflict{Field: "Name", Source: "asos", Value: "SAN FRANCISCO INTL ARPT", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+					&ds.Conflict{Field: "Geography.ElevationMeters", Source: "ghcnd", Value: "3", ChosenSource: "asos", Reason: ReasonPrecedence},
+					&ds.Conflict{Field: "Geography.ElevationMeters", Source: "isd", Value: "4", ChosenSource: "asos", Reason: ReasonPrecedence},
+					&ds.Conflict{Field: LocationField, Source: "isd", Value: "37.62,-122.365", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+					&ds.Conflict{Field: LocationField, Source: "asos", Value: "37.61962,-122.36562", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+				)
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		got := combine(cluster, test.policy)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: combine() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestCombineRegion(t *testing.T) {
+	ghcnd := station("WINDSOR A", 42.2756, -82.9556, ds.Identifiers{WmoID: "71538"})
+	ghcnd.Geography.ElevationMeters = ds.UnsetValue
+	ghcnd.Geography.Continent = "North America"
+	ghcnd.Geography.RegionName = "Canada"
+	ghcnd.Geography.RegionCode = "CA"
+
+	isd := station("WINDSOR", 42.2756, -82.9556, ds.Identifiers{WmoID: "71538"})
+	isd.Geography.ElevationMeters = 0
+	isd.Geography.Continent = "North America"
+	isd.Geography.RegionName = "United States"
+	isd.Geography.RegionCode = "US"
+	isd.Geography.Subdivision1Name = "Michigan"
+	isd.Geography.Subdivision1Code = "MI"
+
+	got := combine([]*Candidate{{Source: "ghcnd", Station: ghcnd}, {Source: "isd", Rank: 1, Station: isd}}, Policy{Default: []string{"ghcnd"}})
+
+	// The region and subdivision come from one source, and an elevation of
+	// zero is kept over an unset one.
+	want := station("WINDSOR A", 42.2756, -82.9556, ds.Identifiers{WmoID: "71538"})
+	want.Geography.Continent = "North America"
+	want.Geography.RegionName = "Canada"
+	want.Geography.RegionCode = "CA"
+	withConflicts(want,
+		&ds.Conflict{Field: "Name", Source: "isd", Value: "WINDSOR", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+		&ds.Conflict{Field: RegionField, Source: "isd", Value: "US,MI", ChosenSource: "ghcnd", Reason: ReasonPrecedence},
+	)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("combine() diff (-want +got):\n%s", diff)
+	}
+
+	// Without a precedence, the region with a subdivision is more precise.
+	got = combine([]*Candidate{{Source: "ghcnd", Station: ghcnd}, {Source: "isd", Rank: 1, Station: isd}}, Policy{})
+	if got.Geography.RegionCode != "US" || got.Geography.Subdivision1Code != "MI" {
+		t.Errorf("combine() region = %s, %s, want US, MI", got.Geography.RegionCode, got.Geography.Subdivision1Code)
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Package metar decodes and encodes METAR and SPECI surface observation reports,
+and TAF terminal aerodrome forecasts, as exchanged between weather services.
+
+Format documentation:
+
+	Federal Meteorological Handbook No. 1, Surface Weather Observations and Reports, Chapter 12
+	WMO Manual on Codes (WMO-No. 306), Volume I.1, FM 15 METAR, FM 16 SPECI and FM 51 TAF
+
+Reports are decoded into METAR and TAF values which keep each group as it was
+given, so that reports in the standard order encode back to the same text,
+(see METAR.String and TAF.String). Groups which are not understood, such as
+the missing data groups "/////KT" and "Q////", are kept rather than rejected,
+as reports from the wider world vary in their details. These, and groups out
+of the standard order, are moved to their standard place when encoding, so
+the report as it was given is kept in METAR.Raw and TAF.Raw.
+
+	METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 T01220083
+
+	TAF KSFO 011720Z 0118/0224 29012KT P6SM FEW015 BKN040
+	  FM012000 30015G25KT P6SM SCT020
+	  TEMPO 0120/0124 3SM -RA BR BKN010
+
+METAR.Observation converts a report into a ds.Observation in SI units, and
+TAF.ForecastPeriods converts a forecast into a ds.ForecastPeriod for each of
+its periods. Reports only give the day of the month, so these need a time at
+or shortly after the report to find the year and month.
+*/
+package metar
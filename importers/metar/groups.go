@@ -0,0 +1,481 @@
+package metar
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+var (
+	stationRe       = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	timeRe          = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windRe          = regexp.MustCompile(`^(\d{3}|VRB)(\d{2}|[1-9]\d{2})(?:G(\d{2}|[1-9]\d{2}))?(KT|MPS|KMH)$`)
+	windVariationRe = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visMilesRe      = regexp.MustCompile(`^([MP])?(?:(\d{1,2})|([1-9])/([1-9]\d?))SM$`)
+	visMetersRe     = regexp.MustCompile(`^(\d{4})(NDV)?$`)
+	rvrRe           = regexp.MustCompile(`^R(\d{2}[LCR]?)/([MP])?(\d{4})(?:V([MP])?(\d{4}))?(FT)?/?([UDN])?$`)
+	weatherRe       = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*)$`)
+	skyRe           = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU)?$`)
+	temperatureRe   = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	altimeterRe     = regexp.MustCompile(`^([AQ])(\d{4})$`)
+)
+
+// Sky covers which are reported without a layer height.
+const (
+	SkyClear           = "SKC" // Sky clear, reported by an observer.
+	SkyClearBelow12000 = "CLR" // No clouds below 12,000ft, reported by ASOS.
+	SkyNoSignificant   = "NSC" // No significant cloud.
+	SkyNoneDetected    = "NCD" // No cloud detected, reported by automated stations.
+)
+
+// Wind is a wind group, e.g. "29012G20KT" or "VRB03KT".
+type Wind struct {
+	// Direction is the direction the wind is from in degrees true. It is
+	// not used when Variable is set.
+	Direction int
+	// Variable is set for variable winds, (VRB).
+	Variable bool
+	// Speed and Gust are in Units, and Gust is 0 if there was none.
+	Speed int
+	Gust  int
+	// Units is "KT" for knots, "MPS" for meters per second or "KMH" for
+	// kilometers per hour.
+	Units string
+}
+
+// parseWind parses a wind group, returning false if it is not one.
+func parseWind(tok string) (*Wind, bool) {
+	m := windRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	w := &Wind{Units: m[4]}
+	if m[1] == "VRB" {
+		w.Variable = true
+	} else {
+		w.Direction, _ = strconv.Atoi(m[1])
+	}
+	w.Speed, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		w.Gust, _ = strconv.Atoi(m[3])
+	}
+	return w, true
+}
+
+func (w *Wind) String() string {
+	dir := fmt.Sprintf("%03d", w.Direction)
+	if w.Variable {
+		dir = "VRB"
+	}
+	s := fmt.Sprintf("%s%02d", dir, w.Speed)
+	if w.Gust != 0 {
+		s += fmt.Sprintf("G%02d", w.Gust)
+	}
+	return s + w.Units
+}
+
+func (w *Wind) unit() units.Unit {
+	switch w.Units {
+	case "MPS":
+		return units.MetersPerSecond
+	case "KMH":
+		return units.KilometersPerHour
+	}
+	return units.Knots
+}
+
+// DirectionDeg returns the direction of the wind, or UnsetValue for calm and
+// variable winds.
+func (w *Wind) DirectionDeg() float64 {
+	if w.Variable || w.Speed == 0 {
+		return ds.UnsetValue
+	}
+	return float64(w.Direction)
+}
+
+// SpeedMPS returns the speed of the wind in meters per second.
+func (w *Wind) SpeedMPS() float64 {
+	return units.MustConvert(float64(w.Speed), w.unit(), units.MetersPerSecond)
+}
+
+// GustMPS returns the speed of the gusts in meters per second, or UnsetValue
+// if there were none.
+func (w *Wind) GustMPS() float64 {
+	if w.Gust == 0 {
+		return ds.UnsetValue
+	}
+	return units.MustConvert(float64(w.Gust), w.unit(), units.MetersPerSecond)
+}
+
+// WindVariation is the range of directions of a varying wind, e.g. "250V310".
+type WindVariation struct {
+	From, To int
+}
+
+// parseWindVariation parses a wind variation group, returning false if it is
+// not one.
+func parseWindVariation(tok string) (*WindVariation, bool) {
+	m := windVariationRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	v := &WindVariation{}
+	v.From, _ = strconv.Atoi(m[1])
+	v.To, _ = strconv.Atoi(m[2])
+	return v, true
+}
+
+func (v *WindVariation) String() string {
+	return fmt.Sprintf("%03dV%03d", v.From, v.To)
+}
+
+// Visibility is a prevailing visibility group in statute miles, e.g. "10SM",
+// "1 1/2SM" or "M1/4SM", or in meters, e.g. "0800" or "9999", or CAVOK.
+type Visibility struct {
+	// Limit is "M" when the visibility is less than the value, "P" when it is
+	// more, and otherwise "".
+	Limit string
+	// Whole, Numerator and Denominator are the statute miles, e.g. 1, 1 and
+	// 2 for "1 1/2SM". Denominator is 0 if there is no fraction.
+	Whole, Numerator, Denominator int
+	// Metric is set for visibilities in Meters.
+	Metric bool
+	Meters int
+	// NoDirectionalVariation is set for NDV, from automated stations which
+	// can not report a directional variation.
+	NoDirectionalVariation bool
+	// CAVOK is set for ceiling and visibility OK, a visibility of 10km or
+	// more with no cloud below 5,000ft and no significant weather.
+	CAVOK bool
+}
+
+// parseVisibility parses a visibility group, returning false if it is not
+// one. A visibility with a whole number and a fraction of miles is given as
+// the two groups joined with a space.
+func parseVisibility(tok string) (*Visibility, bool) {
+	if tok == "CAVOK" {
+		return &Visibility{CAVOK: true}, true
+	}
+	if m := visMetersRe.FindStringSubmatch(tok); m != nil {
+		v := &Visibility{Metric: true, NoDirectionalVariation: m[2] != ""}
+		v.Meters, _ = strconv.Atoi(m[1])
+		return v, true
+	}
+
+	whole, frac, split := strings.Cut(tok, " ")
+	if !split {
+		frac = tok
+	}
+	m := visMilesRe.FindStringSubmatch(frac)
+	if m == nil || (split && (m[3] == "" || m[1] != "" || len(whole) != 1 || whole < "1" || whole > "9")) {
+		return nil, false
+	}
+	v := &Visibility{Limit: m[1]}
+	if m[2] != "" {
+		v.Whole, _ = strconv.Atoi(m[2])
+	} else {
+		v.Numerator, _ = strconv.Atoi(m[3])
+		v.Denominator, _ = strconv.Atoi(m[4])
+	}
+	if split {
+		v.Whole, _ = strconv.Atoi(whole)
+	}
+	return v, true
+}
+
+func (v *Visibility) String() string {
+	switch {
+	case v.CAVOK:
+		return "CAVOK"
+	case v.Metric:
+		s := fmt.Sprintf("%04d", v.Meters)
+		if v.NoDirectionalVariation {
+			s += "NDV"
+		}
+		return s
+	}
+
+	s := v.Limit
+	switch {
+	case v.Denominator == 0:
+		s += strconv.Itoa(v.Whole)
+	case v.Whole == 0:
+		s += fmt.Sprintf("%d/%d", v.Numerator, v.Denominator)
+	default:
+		s += fmt.Sprintf("%d %d/%d", v.Whole, v.Numerator, v.Denominator)
+	}
+	return s + "SM"
+}
+
+// VisibilityM returns the visibility in meters. CAVOK and 9999 are taken as
+// 10km.
+func (v *Visibility) VisibilityM() float64 {
+	switch {
+	case v.CAVOK || (v.Metric && v.Meters == 9999):
+		return 10000
+	case v.Metric:
+		return float64(v.Meters)
+	}
+	miles := float64(v.Whole)
+	if v.Denominator != 0 {
+		miles += float64(v.Numerator) / float64(v.Denominator)
+	}
+	return units.MustConvert(miles, units.StatuteMiles, units.Meters)
+}
+
+// RunwayVisualRange is a runway visual range group, e.g. "R28L/2400V4000FT"
+// or "R24/P1500U".
+type RunwayVisualRange struct {
+	// Runway is the runway designator, e.g. "28L".
+	Runway string
+	// Limit is "M" when the range is less than Range, "P" when it is more,
+	// and otherwise "".
+	Limit string
+	Range int
+	// MaxLimit and MaxRange are the highest of a variable range. MaxRange is
+	// 0 if the range was not variable.
+	MaxLimit string
+	MaxRange int
+	// Feet is set when the ranges are in feet rather than meters.
+	Feet bool
+	// Trend is "U" for rising, "D" for falling, "N" for no change, or "".
+	Trend string
+}
+
+// parseRVR parses a runway visual range group, returning false if it is not
+// one.
+func parseRVR(tok string) (*RunwayVisualRange, bool) {
+	m := rvrRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	r := &RunwayVisualRange{Runway: m[1], Limit: m[2], MaxLimit: m[4], Feet: m[6] != "", Trend: m[7]}
+	r.Range, _ = strconv.Atoi(m[3])
+	if m[5] != "" {
+		r.MaxRange, _ = strconv.Atoi(m[5])
+	}
+	return r, true
+}
+
+func (r *RunwayVisualRange) String() string {
+	s := fmt.Sprintf("R%s/%s%04d", r.Runway, r.Limit, r.Range)
+	if r.MaxRange != 0 {
+		s += fmt.Sprintf("V%s%04d", r.MaxLimit, r.MaxRange)
+	}
+	if r.Feet {
+		s += "FT"
+		if r.Trend != "" {
+			s += "/"
+		}
+	}
+	return s + r.Trend
+}
+
+// RunwayVisualRange returns the range in meters as a ds.RunwayVisualRange.
+func (r *RunwayVisualRange) RunwayVisualRange() *ds.RunwayVisualRange {
+	from := units.Meters
+	if r.Feet {
+		from = units.Feet
+	}
+	out := &ds.RunwayVisualRange{
+		Runway:    r.Runway,
+		RangeM:    units.MustConvert(float64(r.Range), from, units.Meters),
+		MaxRangeM: ds.UnsetValue,
+		Limit:     r.Limit,
+		Trend:     r.Trend,
+	}
+	if r.MaxRange != 0 {
+		out.MaxRangeM = units.MustConvert(float64(r.MaxRange), from, units.Meters)
+		if r.MaxLimit != "" {
+			out.Limit = r.MaxLimit
+		}
+	}
+	return out
+}
+
+// isWeather reports whether the group is a present or forecast weather group,
+// e.g. "-RA", "+TSRAGR", "VCSH" or "BR".
+func isWeather(tok string) bool {
+	m := weatherRe.FindStringSubmatch(tok)
+	if m == nil {
+		return false
+	}
+	// An intensity or proximity alone is not weather.
+	return m[2] != "" || m[3] != ""
+}
+
+// SkyLayer is a sky condition group, e.g. "BKN040", "OVC010CB" or "CLR".
+type SkyLayer struct {
+	// Cover is FEW, SCT, BKN, OVC or VV for vertical visibility, or one of
+	// the Sky constants for a clear sky.
+	Cover string
+	// Height is the height of the base of the layer in hundreds of feet,
+	// or -1 if it was not reported, (///). It is not used for a clear sky.
+	Height int
+	// Type is the convective cloud type, CB or TCU, if any.
+	Type string
+}
+
+// parseSkyLayer parses a sky condition group, returning false if it is not
+// one.
+func parseSkyLayer(tok string) (*SkyLayer, bool) {
+	switch tok {
+	case SkyClear, SkyClearBelow12000, SkyNoSignificant, SkyNoneDetected:
+		return &SkyLayer{Cover: tok}, true
+	}
+	m := skyRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	l := &SkyLayer{Cover: m[1], Height: -1, Type: m[3]}
+	if m[2] != "///" {
+		l.Height, _ = strconv.Atoi(m[2])
+	}
+	return l, true
+}
+
+func (l *SkyLayer) String() string {
+	if l.clear() {
+		return l.Cover
+	}
+	height := "///"
+	if l.Height >= 0 {
+		height = fmt.Sprintf("%03d", l.Height)
+	}
+	return l.Cover + height + l.Type
+}
+
+func (l *SkyLayer) clear() bool {
+	switch l.Cover {
+	case SkyClear, SkyClearBelow12000, SkyNoSignificant, SkyNoneDetected:
+		return true
+	}
+	return false
+}
+
+// CloudLayer returns the layer with its height in meters as a ds.CloudLayer.
+func (l *SkyLayer) CloudLayer() *ds.CloudLayer {
+	if l.clear() {
+		return &ds.CloudLayer{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue}
+	}
+	out := &ds.CloudLayer{Cover: l.Cover, BaseM: ds.UnsetValue, Type: l.Type}
+	if l.Height >= 0 {
+		out.BaseM = units.MustConvert(float64(l.Height*100), units.Feet, units.Meters)
+	}
+	return out
+}
+
+// skyOktas are the oktas used for the total sky cover of each layer cover.
+// The layer covers are ranges, so the highest of each range is used.
+var skyOktas = map[string]float64{
+	ds.CloudCoverClear:     0,
+	ds.CloudCoverFew:       2,
+	ds.CloudCoverScattered: 4,
+	ds.CloudCoverBroken:    7,
+	ds.CloudCoverOvercast:  8,
+	ds.CloudCoverObscured:  8,
+}
+
+// cloudLayers converts the sky layers, and returns them with the total sky
+// cover in oktas and the ceiling in meters. The cover and ceiling are
+// UnsetValue if there are no layers.
+func cloudLayers(sky []*SkyLayer) ([]*ds.CloudLayer, float64, float64) {
+	if len(sky) == 0 {
+		return nil, ds.UnsetValue, ds.UnsetValue
+	}
+	layers := make([]*ds.CloudLayer, len(sky))
+	var oktas float64
+	for i, l := range sky {
+		layers[i] = l.CloudLayer()
+		if o := skyOktas[layers[i].Cover]; o > oktas {
+			oktas = o
+		}
+	}
+	return layers, oktas, ds.Ceiling(layers)
+}
+
+// Temperature is a temperature and dew point group in whole degrees Celsius,
+// e.g. "12/08", "M01/M03" or "15/".
+type Temperature struct {
+	// TempC and DewPointC are in whole degrees. A reported M00, a value
+	// just below zero, is kept as negative zero. DewPointC is UnsetValue if
+	// it was not reported.
+	TempC, DewPointC float64
+}
+
+// parseTemperature parses a temperature and dew point group, returning false
+// if it is not one.
+func parseTemperature(tok string) (*Temperature, bool) {
+	m := temperatureRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	t := &Temperature{TempC: signedValue(m[1]), DewPointC: ds.UnsetValue}
+	if m[2] != "" {
+		t.DewPointC = signedValue(m[2])
+	}
+	return t, true
+}
+
+func (t *Temperature) String() string {
+	s := signedString(t.TempC) + "/"
+	if t.DewPointC != ds.UnsetValue {
+		s += signedString(t.DewPointC)
+	}
+	return s
+}
+
+// signedValue parses a whole number with an M prefix for minus, e.g. "M05".
+func signedValue(s string) float64 {
+	v, _ := strconv.Atoi(strings.TrimPrefix(s, "M"))
+	if strings.HasPrefix(s, "M") {
+		return math.Copysign(float64(v), -1)
+	}
+	return float64(v)
+}
+
+// signedString formats a whole number with an M prefix for minus.
+func signedString(v float64) string {
+	if math.Signbit(v) {
+		return fmt.Sprintf("M%02.0f", -v)
+	}
+	return fmt.Sprintf("%02.0f", v)
+}
+
+// Altimeter is an altimeter setting group in hundredths of inches of mercury,
+// e.g. "A3002", or in hectopascals, e.g. "Q1013".
+type Altimeter struct {
+	// Units is "A" for inches of mercury or "Q" for hectopascals.
+	Units string
+	Value int
+}
+
+// parseAltimeter parses an altimeter setting group, returning false if it is
+// not one.
+func parseAltimeter(tok string) (*Altimeter, bool) {
+	m := altimeterRe.FindStringSubmatch(tok)
+	if m == nil {
+		return nil, false
+	}
+	a := &Altimeter{Units: m[1]}
+	a.Value, _ = strconv.Atoi(m[2])
+	return a, true
+}
+
+func (a *Altimeter) String() string {
+	return fmt.Sprintf("%s%04d", a.Units, a.Value)
+}
+
+// Hectopascals returns the altimeter setting in hPa.
+func (a *Altimeter) Hectopascals() float64 {
+	if a.Units == "Q" {
+		return float64(a.Value)
+	}
+	return units.MustConvert(float64(a.Value), units.HundredthsInchesOfHg, units.Hectopascals)
+}
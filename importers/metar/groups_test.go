@@ -0,0 +1,708 @@
+package metar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestParseWind(t *testing.T) {
+	tests := []struct {
+		have string
+		want *Wind
+	}{
+		// Not wind groups.
+		{
+			have: "",
+		},
+		{
+			have: "29012",
+		},
+		{
+			have: "2901KT",
+		},
+		{
+			have: "29012G5KT",
+		},
+		{
+			have: "/////KT",
+		},
+		{
+			have: "29012MPH",
+		},
+		{
+			have: "290012KT",
+		},
+		// Normal cases.
+		{
+			have: "29012KT",
+			want: &Wind{Direction: 290, Speed: 12, Units: "KT"},
+		},
+		{
+			have: "00000KT",
+			want: &Wind{Speed: 0, Units: "KT"},
+		},
+		{
+			have: "VRB03KT",
+			want: &Wind{Variable: true, Speed: 3, Units: "KT"},
+		},
+		{
+			have: "29012G20KT",
+			want: &Wind{Direction: 290, Speed: 12, Gust: 20, Units: "KT"},
+		},
+		{
+			have: "24008MPS",
+			want: &Wind{Direction: 240, Speed: 8, Units: "MPS"},
+		},
+		{
+			have: "36025G40KMH",
+			want: &Wind{Direction: 360, Speed: 25, Gust: 40, Units: "KMH"},
+		},
+		// Hurricane force winds.
+		{
+			have: "090105G130KT",
+			want: &Wind{Direction: 90, Speed: 105, Gust: 130, Units: "KT"},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseWind(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseWind(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseWind(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if ok && got.String() != test.have {
+			t.Errorf("parseWind(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+	}
+}
+
+func TestWindValues(t *testing.T) {
+	tests := []struct {
+		have      *Wind
+		wantDir   float64
+		wantSpeed float64
+		wantGust  float64
+	}{
+		{
+			have:      &Wind{Direction: 290, Speed: 12, Gust: 20, Units: "KT"},
+			wantDir:   290,
+			wantSpeed: units.MustConvert(12, units.Knots, units.MetersPerSecond),
+			wantGust:  units.MustConvert(20, units.Knots, units.MetersPerSecond),
+		},
+		{
+			have:      &Wind{Speed: 0, Units: "KT"},
+			wantDir:   ds.UnsetValue,
+			wantSpeed: 0,
+			wantGust:  ds.UnsetValue,
+		},
+		{
+			have:      &Wind{Variable: true, Speed: 3, Units: "MPS"},
+			wantDir:   ds.UnsetValue,
+			wantSpeed: 3,
+			wantGust:  ds.UnsetValue,
+		},
+		{
+			have:      &Wind{Direction: 360, Speed: 36, Units: "KMH"},
+			wantDir:   360,
+			wantSpeed: 10,
+			wantGust:  ds.UnsetValue,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.DirectionDeg(); got != test.wantDir {
+			t.Errorf("%v.DirectionDeg() = %v, want %v", test.have, got, test.wantDir)
+		}
+		if got := test.have.SpeedMPS(); got != test.wantSpeed {
+			t.Errorf("%v.SpeedMPS() = %v, want %v", test.have, got, test.wantSpeed)
+		}
+		if got := test.have.GustMPS(); got != test.wantGust {
+			t.Errorf("%v.GustMPS() = %v, want %v", test.have, got, test.wantGust)
+		}
+	}
+}
+
+func TestParseWindVariation(t *testing.T) {
+	tests := []struct {
+		have string
+		want *WindVariation
+	}{
+		{
+			have: "",
+		},
+		{
+			have: "250V31",
+		},
+		{
+			have: "250-310",
+		},
+		{
+			have: "250V310",
+			want: &WindVariation{From: 250, To: 310},
+		},
+		{
+			have: "350V020",
+			want: &WindVariation{From: 350, To: 20},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseWindVariation(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseWindVariation(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseWindVariation(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if ok && got.String() != test.have {
+			t.Errorf("parseWindVariation(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+	}
+}
+
+func TestParseVisibility(t *testing.T) {
+	tests := []struct {
+		have  string
+		want  *Visibility
+		wantM float64
+	}{
+		// Not visibility groups.
+		{
+			have: "",
+		},
+		{
+			have: "SM",
+		},
+		{
+			have: "10",
+		},
+		{
+			have: "1/0SM",
+		},
+		{
+			have: "10 1/2SM",
+		},
+		{
+			have: "1 10SM",
+		},
+		{
+			have: "1 M1/4SM",
+		},
+		{
+			have: "29012KT 10SM",
+		},
+		{
+			have: "999",
+		},
+		// Statute miles.
+		{
+			have:  "10SM",
+			want:  &Visibility{Whole: 10},
+			wantM: units.MustConvert(10, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "0SM",
+			want:  &Visibility{},
+			wantM: 0,
+		},
+		{
+			have:  "P6SM",
+			want:  &Visibility{Limit: "P", Whole: 6},
+			wantM: units.MustConvert(6, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "1/2SM",
+			want:  &Visibility{Numerator: 1, Denominator: 2},
+			wantM: units.MustConvert(0.5, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "M1/4SM",
+			want:  &Visibility{Limit: "M", Numerator: 1, Denominator: 4},
+			wantM: units.MustConvert(0.25, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "3/16SM",
+			want:  &Visibility{Numerator: 3, Denominator: 16},
+			wantM: units.MustConvert(0.1875, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "1 1/2SM",
+			want:  &Visibility{Whole: 1, Numerator: 1, Denominator: 2},
+			wantM: units.MustConvert(1.5, units.StatuteMiles, units.Meters),
+		},
+		{
+			have:  "2 3/4SM",
+			want:  &Visibility{Whole: 2, Numerator: 3, Denominator: 4},
+			wantM: units.MustConvert(2.75, units.StatuteMiles, units.Meters),
+		},
+		// Meters.
+		{
+			have:  "0800",
+			want:  &Visibility{Metric: true, Meters: 800},
+			wantM: 800,
+		},
+		{
+			have:  "0000",
+			want:  &Visibility{Metric: true},
+			wantM: 0,
+		},
+		{
+			have:  "9999",
+			want:  &Visibility{Metric: true, Meters: 9999},
+			wantM: 10000,
+		},
+		{
+			have:  "4000NDV",
+			want:  &Visibility{Metric: true, Meters: 4000, NoDirectionalVariation: true},
+			wantM: 4000,
+		},
+		{
+			have:  "CAVOK",
+			want:  &Visibility{CAVOK: true},
+			wantM: 10000,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseVisibility(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseVisibility(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseVisibility(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if !ok {
+			continue
+		}
+		if got.String() != test.have {
+			t.Errorf("parseVisibility(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+		if m := got.VisibilityM(); m != test.wantM {
+			t.Errorf("parseVisibility(%q).VisibilityM() = %v, want %v", test.have, m, test.wantM)
+		}
+	}
+}
+
+func TestParseRVR(t *testing.T) {
+	tests := []struct {
+		have string
+		want *RunwayVisualRange
+		// wantString is the encoding if it differs from have.
+		wantString string
+	}{
+		// Not runway visual range groups.
+		{
+			have: "",
+		},
+		{
+			have: "R28L",
+		},
+		{
+			have: "R28L/240FT",
+		},
+		{
+			have: "RA",
+		},
+		// Normal cases.
+		{
+			have: "R28L/2400FT",
+			want: &RunwayVisualRange{Runway: "28L", Range: 2400, Feet: true},
+		},
+		{
+			have: "R28R/P6000FT",
+			want: &RunwayVisualRange{Runway: "28R", Limit: "P", Range: 6000, Feet: true},
+		},
+		{
+			have: "R01/M0600FT",
+			want: &RunwayVisualRange{Runway: "01", Limit: "M", Range: 600, Feet: true},
+		},
+		{
+			have: "R28L/1000V1600FT",
+			want: &RunwayVisualRange{Runway: "28L", Range: 1000, MaxRange: 1600, Feet: true},
+		},
+		{
+			have: "R06C/M0600VP6000FT",
+			want: &RunwayVisualRange{Runway: "06C", Limit: "M", Range: 600, MaxLimit: "P", MaxRange: 6000, Feet: true},
+		},
+		{
+			have: "R28L/2400V4000FT/U",
+			want: &RunwayVisualRange{Runway: "28L", Range: 2400, MaxRange: 4000, Feet: true, Trend: "U"},
+		},
+		{
+			have: "R24/0600",
+			want: &RunwayVisualRange{Runway: "24", Range: 600},
+		},
+		{
+			have: "R24/P1500N",
+			want: &RunwayVisualRange{Runway: "24", Limit: "P", Range: 1500, Trend: "N"},
+		},
+		{
+			have: "R09L/0550V0800D",
+			want: &RunwayVisualRange{Runway: "09L", Range: 550, MaxRange: 800, Trend: "D"},
+		},
+		// Non standard trends are encoded in the standard form.
+		{
+			have:       "R24/0600/U",
+			want:       &RunwayVisualRange{Runway: "24", Range: 600, Trend: "U"},
+			wantString: "R24/0600U",
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseRVR(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseRVR(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseRVR(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if !ok {
+			continue
+		}
+		want := test.wantString
+		if want == "" {
+			want = test.have
+		}
+		if got.String() != want {
+			t.Errorf("parseRVR(%q).String() = %q, want %q", test.have, got.String(), want)
+		}
+	}
+}
+
+func TestRunwayVisualRange(t *testing.T) {
+	feet := func(v float64) float64 { return units.MustConvert(v, units.Feet, units.Meters) }
+
+	tests := []struct {
+		have *RunwayVisualRange
+		want *ds.RunwayVisualRange
+	}{
+		{
+			have: &RunwayVisualRange{Runway: "28L", Range: 2400, Feet: true},
+			want: &ds.RunwayVisualRange{Runway: "28L", RangeM: feet(2400), MaxRangeM: ds.UnsetValue},
+		},
+		{
+			have: &RunwayVisualRange{Runway: "28L", Range: 1000, MaxLimit: "P", MaxRange: 6000, Feet: true, Trend: "U"},
+			want: &ds.RunwayVisualRange{Runway: "28L", RangeM: feet(1000), MaxRangeM: feet(6000), Limit: "P", Trend: "U"},
+		},
+		{
+			have: &RunwayVisualRange{Runway: "24", Limit: "M", Range: 50, Trend: "D"},
+			want: &ds.RunwayVisualRange{Runway: "24", RangeM: 50, MaxRangeM: ds.UnsetValue, Limit: "M", Trend: "D"},
+		},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, test.have.RunwayVisualRange()); diff != "" {
+			t.Errorf("%v.RunwayVisualRange() diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestIsWeather(t *testing.T) {
+	tests := []struct {
+		have string
+		want bool
+	}{
+		// Not weather groups.
+		{have: ""},
+		{have: "-"},
+		{have: "+"},
+		{have: "VC"},
+		{have: "AUTO"},
+		{have: "RERA"},
+		{have: "NSW"},
+		{have: "-XX"},
+		// Normal cases.
+		{have: "-RA", want: true},
+		{have: "RA", want: true},
+		{have: "+RA", want: true},
+		{have: "-SHRA", want: true},
+		{have: "+TSRAGR", want: true},
+		{have: "TS", want: true},
+		{have: "VCSH", want: true},
+		{have: "VCTS", want: true},
+		{have: "FZFG", want: true},
+		{have: "BR", want: true},
+		{have: "HZ", want: true},
+		{have: "BLSN", want: true},
+		{have: "-FZDZSN", want: true},
+		{have: "+FC", want: true},
+		{have: "UP", want: true},
+	}
+
+	for _, test := range tests {
+		if got := isWeather(test.have); got != test.want {
+			t.Errorf("isWeather(%q) = %v, want %v", test.have, got, test.want)
+		}
+	}
+}
+
+func TestParseSkyLayer(t *testing.T) {
+	feet := func(v float64) float64 { return units.MustConvert(v, units.Feet, units.Meters) }
+
+	tests := []struct {
+		have      string
+		want      *SkyLayer
+		wantCloud *ds.CloudLayer
+	}{
+		// Not sky condition groups.
+		{
+			have: "",
+		},
+		{
+			have: "BKN40",
+		},
+		{
+			have: "OVC010XX",
+		},
+		{
+			have: "CLEAR",
+		},
+		// Normal cases.
+		{
+			have:      "CLR",
+			want:      &SkyLayer{Cover: SkyClearBelow12000},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue},
+		},
+		{
+			have:      "SKC",
+			want:      &SkyLayer{Cover: SkyClear},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue},
+		},
+		{
+			have:      "NSC",
+			want:      &SkyLayer{Cover: SkyNoSignificant},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue},
+		},
+		{
+			have:      "NCD",
+			want:      &SkyLayer{Cover: SkyNoneDetected},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue},
+		},
+		{
+			have:      "FEW015",
+			want:      &SkyLayer{Cover: "FEW", Height: 15},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverFew, BaseM: feet(1500)},
+		},
+		{
+			have:      "BKN040",
+			want:      &SkyLayer{Cover: "BKN", Height: 40},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverBroken, BaseM: feet(4000)},
+		},
+		{
+			have:      "SCT020CB",
+			want:      &SkyLayer{Cover: "SCT", Height: 20, Type: "CB"},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverScattered, BaseM: feet(2000), Type: "CB"},
+		},
+		{
+			have:      "OVC250TCU",
+			want:      &SkyLayer{Cover: "OVC", Height: 250, Type: "TCU"},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverOvercast, BaseM: feet(25000), Type: "TCU"},
+		},
+		{
+			have:      "VV000",
+			want:      &SkyLayer{Cover: "VV", Height: 0},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverObscured, BaseM: 0},
+		},
+		{
+			have:      "OVC///",
+			want:      &SkyLayer{Cover: "OVC", Height: -1},
+			wantCloud: &ds.CloudLayer{Cover: ds.CloudCoverOvercast, BaseM: ds.UnsetValue},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseSkyLayer(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseSkyLayer(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseSkyLayer(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if !ok {
+			continue
+		}
+		if got.String() != test.have {
+			t.Errorf("parseSkyLayer(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+		if diff := cmp.Diff(test.wantCloud, got.CloudLayer()); diff != "" {
+			t.Errorf("parseSkyLayer(%q).CloudLayer() diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestCloudLayers(t *testing.T) {
+	feet := func(v float64) float64 { return units.MustConvert(v, units.Feet, units.Meters) }
+
+	tests := []struct {
+		have        []*SkyLayer
+		wantOktas   float64
+		wantCeiling float64
+	}{
+		{
+			have:        nil,
+			wantOktas:   ds.UnsetValue,
+			wantCeiling: ds.UnsetValue,
+		},
+		{
+			have:        []*SkyLayer{{Cover: SkyClearBelow12000}},
+			wantOktas:   0,
+			wantCeiling: ds.UnsetValue,
+		},
+		{
+			have:        []*SkyLayer{{Cover: "FEW", Height: 15}, {Cover: "SCT", Height: 40}},
+			wantOktas:   4,
+			wantCeiling: ds.UnsetValue,
+		},
+		{
+			have:        []*SkyLayer{{Cover: "FEW", Height: 15}, {Cover: "BKN", Height: 40}, {Cover: "OVC", Height: 80}},
+			wantOktas:   8,
+			wantCeiling: feet(4000),
+		},
+		{
+			have:        []*SkyLayer{{Cover: "VV", Height: 3}},
+			wantOktas:   8,
+			wantCeiling: feet(300),
+		},
+	}
+
+	for _, test := range tests {
+		layers, oktas, ceiling := cloudLayers(test.have)
+		if len(layers) != len(test.have) {
+			t.Errorf("cloudLayers(%v) returned %d layers, want %d", test.have, len(layers), len(test.have))
+		}
+		if oktas != test.wantOktas {
+			t.Errorf("cloudLayers(%v) oktas = %v, want %v", test.have, oktas, test.wantOktas)
+		}
+		if ceiling != test.wantCeiling {
+			t.Errorf("cloudLayers(%v) ceiling = %v, want %v", test.have, ceiling, test.wantCeiling)
+		}
+	}
+}
+
+func TestParseTemperature(t *testing.T) {
+	tests := []struct {
+		have string
+		want *Temperature
+	}{
+		// Not temperature groups.
+		{
+			have: "",
+		},
+		{
+			have: "12/8",
+		},
+		{
+			have: "/08",
+		},
+		{
+			have: "140/04",
+		},
+		// Normal cases.
+		{
+			have: "12/08",
+			want: &Temperature{TempC: 12, DewPointC: 8},
+		},
+		{
+			have: "M01/M03",
+			want: &Temperature{TempC: -1, DewPointC: -3},
+		},
+		{
+			have: "05/M02",
+			want: &Temperature{TempC: 5, DewPointC: -2},
+		},
+		{
+			have: "15/",
+			want: &Temperature{TempC: 15, DewPointC: ds.UnsetValue},
+		},
+		{
+			have: "00/M00",
+			want: &Temperature{TempC: 0, DewPointC: math.Copysign(0, -1)},
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseTemperature(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseTemperature(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseTemperature(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if ok && got.String() != test.have {
+			t.Errorf("parseTemperature(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+	}
+}
+
+func TestParseAltimeter(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *Altimeter
+		wantHPa float64
+	}{
+		// Not altimeter groups.
+		{
+			have: "",
+		},
+		{
+			have: "A300",
+		},
+		{
+			have: "B3002",
+		},
+		// Normal cases.
+		{
+			have:    "A3002",
+			want:    &Altimeter{Units: "A", Value: 3002},
+			wantHPa: units.MustConvert(30.02, units.InchesOfMercury, units.Hectopascals),
+		},
+		{
+			have:    "A2992",
+			want:    &Altimeter{Units: "A", Value: 2992},
+			wantHPa: units.MustConvert(29.92, units.InchesOfMercury, units.Hectopascals),
+		},
+		{
+			have:    "Q1013",
+			want:    &Altimeter{Units: "Q", Value: 1013},
+			wantHPa: 1013,
+		},
+		{
+			have:    "Q0998",
+			want:    &Altimeter{Units: "Q", Value: 998},
+			wantHPa: 998,
+		},
+	}
+
+	for _, test := range tests {
+		got, ok := parseAltimeter(test.have)
+		if ok != (test.want != nil) {
+			t.Errorf("parseAltimeter(%q) ok = %v, want %v", test.have, ok, !ok)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("parseAltimeter(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+		if !ok {
+			continue
+		}
+		if got.String() != test.have {
+			t.Errorf("parseAltimeter(%q).String() = %q, want %q", test.have, got.String(), test.have)
+		}
+		if hpa := got.Hectopascals(); math.Abs(hpa-test.wantHPa) > 1e-9 {
+			t.Errorf("parseAltimeter(%q).Hectopascals() = %v, want %v", test.have, hpa, test.wantHPa)
+		}
+	}
+}
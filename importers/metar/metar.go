@@ -0,0 +1,381 @@
+package metar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+var (
+	slpRe       = regexp.MustCompile(`^SLP(\d{3})$`)
+	precip1Re   = regexp.MustCompile(`^P(\d{4})$`)
+	precip6Re   = regexp.MustCompile(`^6(\d{4})$`)
+	precip24Re  = regexp.MustCompile(`^7(\d{4})$`)
+	snowDepthRe = regexp.MustCompile(`^4/(\d{3})$`)
+	tempExactRe = regexp.MustCompile(`^T([01]\d{3})([01]\d{3})?$`)
+)
+
+// METAR is a decoded METAR or SPECI report.
+//
+// The groups of the body are decoded in any order, and any which are not
+// understood are kept in Other. String encodes the groups in the standard
+// order, with Other at the end of the body, so reports in the standard order
+// encode back to the same text. Reports with groups out of order, or with
+// groups which are not understood before the end of the body, such as
+// "/////KT", encode with those groups moved, (see Raw).
+type METAR struct {
+	// Type is "METAR", "SPECI", or "" if the report did not start with
+	// its type.
+	Type    string
+	Station string
+	// Day, Hour and Minute are the UTC time of the report.
+	Day, Hour, Minute int
+	// Modifiers are AUTO for a fully automated report and COR for a
+	// corrected one, in the order given.
+	Modifiers []string
+
+	Wind          *Wind
+	WindVariation *WindVariation
+	Visibility    *Visibility
+	// RunwayVisualRanges are the runway visual ranges, in the order given.
+	RunwayVisualRanges []*RunwayVisualRange
+	// Weather are the present weather groups, e.g. "-RA", "BR".
+	Weather     []string
+	Sky         []*SkyLayer
+	Temperature *Temperature
+	Altimeter   *Altimeter
+
+	// Other are the groups of the body which were not understood, and any
+	// trend forecast, (NOSIG, BECMG or TEMPO), at the end of the body.
+	Other []string
+	// Remarks are the groups after RMK.
+	Remarks []string
+
+	// Raw is the report exactly as it was given to Parse, kept for
+	// provenance, as String is only an encoding of the decoded groups.
+	Raw string
+}
+
+// Parse decodes a METAR or SPECI report. Only the station and the day and
+// time group are required.
+//
+//	METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 T01220083
+func Parse(report string) (*METAR, error) {
+	tokens := strings.Fields(strings.TrimSuffix(strings.TrimSpace(report), "="))
+	m := &METAR{Raw: report}
+	if len(tokens) > 0 && (tokens[0] == "METAR" || tokens[0] == "SPECI") {
+		m.Type = tokens[0]
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 2 || !stationRe.MatchString(tokens[0]) {
+		return nil, fmt.Errorf("metar: missing station in %q", report)
+	}
+	m.Station = tokens[0]
+
+	var err error
+	if m.Day, m.Hour, m.Minute, err = parseTime(tokens[1]); err != nil {
+		return nil, fmt.Errorf("metar: %v in %q", err, report)
+	}
+
+	body := tokens[2:]
+	for i, tok := range body {
+		if tok == "RMK" {
+			body, m.Remarks = body[:i], body[i+1:]
+			break
+		}
+	}
+
+	for i := 0; i < len(body); i++ {
+		tok := body[i]
+		// Trend forecasts are kept as they are, as their groups are not
+		// observed conditions.
+		if tok == "NOSIG" || tok == "BECMG" || tok == "TEMPO" {
+			m.Other = append(m.Other, body[i:]...)
+			break
+		}
+		// Visibilities of over a mile with a fraction are split over two
+		// groups, e.g. "1 1/2SM".
+		if m.Visibility == nil && i+1 < len(body) {
+			if v, ok := parseVisibility(tok + " " + body[i+1]); ok {
+				m.Visibility = v
+				i++
+				continue
+			}
+		}
+		m.parseGroup(tok)
+	}
+
+	return m, nil
+}
+
+// parseTime parses a day and time group, e.g. "010056Z".
+func parseTime(tok string) (day, hour, minute int, err error) {
+	t := timeRe.FindStringSubmatch(tok)
+	if t == nil {
+		return 0, 0, 0, fmt.Errorf("invalid day and time %q", tok)
+	}
+	day, _ = strconv.Atoi(t[1])
+	hour, _ = strconv.Atoi(t[2])
+	minute, _ = strconv.Atoi(t[3])
+	if day < 1 || day > 31 || hour > 23 || minute > 59 {
+		return 0, 0, 0, fmt.Errorf("invalid day and time %q", tok)
+	}
+	return day, hour, minute, nil
+}
+
+// parseGroup decodes one group of the body into m.
+func (m *METAR) parseGroup(tok string) {
+	if tok == "AUTO" || tok == "COR" {
+		m.Modifiers = append(m.Modifiers, tok)
+		return
+	}
+	if w, ok := parseWind(tok); ok && m.Wind == nil {
+		m.Wind = w
+		return
+	}
+	if v, ok := parseWindVariation(tok); ok && m.WindVariation == nil {
+		m.WindVariation = v
+		return
+	}
+	if v, ok := parseVisibility(tok); ok && m.Visibility == nil {
+		m.Visibility = v
+		return
+	}
+	if r, ok := parseRVR(tok); ok {
+		m.RunwayVisualRanges = append(m.RunwayVisualRanges, r)
+		return
+	}
+	if isWeather(tok) {
+		m.Weather = append(m.Weather, tok)
+		return
+	}
+	if l, ok := parseSkyLayer(tok); ok {
+		m.Sky = append(m.Sky, l)
+		return
+	}
+	if t, ok := parseTemperature(tok); ok && m.Temperature == nil {
+		m.Temperature = t
+		return
+	}
+	if a, ok := parseAltimeter(tok); ok && m.Altimeter == nil {
+		m.Altimeter = a
+		return
+	}
+	m.Other = append(m.Other, tok)
+}
+
+// String encodes the report in the standard order of its groups, with the
+// groups which were not understood at the end of the body.
+func (m *METAR) String() string {
+	var groups []string
+	if m.Type != "" {
+		groups = append(groups, m.Type)
+	}
+	groups = append(groups, m.Station, fmt.Sprintf("%02d%02d%02dZ", m.Day, m.Hour, m.Minute))
+	groups = append(groups, m.Modifiers...)
+	if m.Wind != nil {
+		groups = append(groups, m.Wind.String())
+	}
+	if m.WindVariation != nil {
+		groups = append(groups, m.WindVariation.String())
+	}
+	if m.Visibility != nil {
+		groups = append(groups, m.Visibility.String())
+	}
+	for _, r := range m.RunwayVisualRanges {
+		groups = append(groups, r.String())
+	}
+	groups = append(groups, m.Weather...)
+	for _, l := range m.Sky {
+		groups = append(groups, l.String())
+	}
+	if m.Temperature != nil {
+		groups = append(groups, m.Temperature.String())
+	}
+	if m.Altimeter != nil {
+		groups = append(groups, m.Altimeter.String())
+	}
+	groups = append(groups, m.Other...)
+	if len(m.Remarks) > 0 {
+		groups = append(groups, "RMK")
+		groups = append(groups, m.Remarks...)
+	}
+	return strings.Join(groups, " ")
+}
+
+// ParseObservation decodes a METAR or SPECI report into an Observation, (see
+// Parse and METAR.Observation).
+func ParseObservation(report string, ref time.Time) (*ds.Observation, error) {
+	m, err := Parse(report)
+	if err != nil {
+		return nil, err
+	}
+	return m.Observation(ref)
+}
+
+// Observation converts the report into an Observation for its station and
+// time, with the report as its Report, (Raw, or if the report was not from
+// Parse, its String encoding). The report only gives the day of the
+// month, so ref is a time at or shortly after the report, from which the
+// year and month are taken.
+//
+// Of the remarks the sea level pressure, the precise temperature and dew
+// point, the precipitation totals and the snow depth are decoded.
+func (m *METAR) Observation(ref time.Time) (*ds.Observation, error) {
+	t, err := reportTime(m.Day, m.Hour, m.Minute, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	obs := ds.EmptyObservation()
+	obs.StationID = m.Station
+	obs.Time = t
+	obs.Report = m.Raw
+	if obs.Report == "" {
+		obs.Report = m.String()
+	}
+
+	if w := m.Wind; w != nil {
+		obs.WindSpeedMPS = w.SpeedMPS()
+		obs.Flags.Set("WindSpeedMPS", &ds.Flag{Quality: ds.QualityPassed})
+		if dir := w.DirectionDeg(); dir != ds.UnsetValue {
+			obs.WindDirectionDeg = dir
+			obs.Flags.Set("WindDirectionDeg", &ds.Flag{Quality: ds.QualityPassed})
+		}
+		if gust := w.GustMPS(); gust != ds.UnsetValue {
+			obs.WindGustMPS = gust
+			obs.Flags.Set("WindGustMPS", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+
+	if v := m.Visibility; v != nil {
+		obs.VisibilityM = v.VisibilityM()
+		// Visibilities which are limits of what can be reported are kept
+		// as the limit, with the group in the flag.
+		flag := &ds.Flag{Quality: ds.QualityPassed}
+		if v.Limit != "" || v.CAVOK || (v.Metric && v.Meters == 9999) {
+			flag.Original = v.String()
+		}
+		obs.Flags.Set("VisibilityM", flag)
+	}
+
+	for _, r := range m.RunwayVisualRanges {
+		obs.RunwayVisualRanges = append(obs.RunwayVisualRanges, r.RunwayVisualRange())
+	}
+	obs.PresentWeather = append(obs.PresentWeather, m.Weather...)
+
+	if len(m.Sky) > 0 {
+		obs.CloudLayers, obs.SkyCoverOktas, obs.CeilingM = cloudLayers(m.Sky)
+		obs.Flags.Set("SkyCoverOktas", &ds.Flag{Quality: ds.QualityEstimated})
+		if obs.CeilingM != ds.UnsetValue {
+			obs.Flags.Set("CeilingM", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+
+	if temp := m.Temperature; temp != nil {
+		// Add 0 to turn a negative zero into zero.
+		obs.TempC = temp.TempC + 0
+		obs.Flags.Set("TempC", &ds.Flag{Quality: ds.QualityPassed})
+		if temp.DewPointC != ds.UnsetValue {
+			obs.DewPointC = temp.DewPointC + 0
+			obs.Flags.Set("DewPointC", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+
+	if m.Altimeter != nil {
+		obs.PressureAltimeterHPa = m.Altimeter.Hectopascals()
+		obs.Flags.Set("PressureAltimeterHPa", &ds.Flag{Quality: ds.QualityPassed})
+	}
+
+	for _, tok := range m.Remarks {
+		decodeRemark(obs, tok)
+	}
+
+	return obs, nil
+}
+
+// reportTime returns the time of a reports day and time in the month of ref,
+// or the month before if the day is after refs.
+func reportTime(day, hour, minute int, ref time.Time) (time.Time, error) {
+	ref = ref.UTC()
+	month := ref.Month()
+	if day > ref.Day() {
+		month--
+	}
+	t := time.Date(ref.Year(), month, day, hour, minute, 0, 0, time.UTC)
+	if t.Day() != day {
+		return time.Time{}, fmt.Errorf("metar: day %d is not in the month before %v", day, ref)
+	}
+	return t, nil
+}
+
+// decodeRemark decodes one group from the remarks into obs.
+func decodeRemark(obs *ds.Observation, tok string) {
+	switch {
+	case slpRe.MatchString(tok):
+		// The sea level pressure is given in tenths of hPa without the
+		// leading 9 or 10, e.g. SLP165 is 1016.5 and SLP982 is 998.2.
+		v, _ := strconv.Atoi(slpRe.FindStringSubmatch(tok)[1])
+		if v < 500 {
+			v += 10000
+		} else {
+			v += 9000
+		}
+		obs.PressureSeaLevelHPa = units.MustConvert(float64(v), units.TenthsHectopascals, units.Hectopascals)
+		obs.Flags.Set("PressureSeaLevelHPa", &ds.Flag{Quality: ds.QualityPassed})
+	case precip1Re.MatchString(tok):
+		setPrecip(obs, "Precip1HrMM", &obs.Precip1HrMM, tok, precip1Re.FindStringSubmatch(tok)[1])
+	case precip6Re.MatchString(tok):
+		// The group is the 6 hour total in the reports nearest 00, 06, 12
+		// and 18 UTC, and the 3 hour total in those nearest 03, 09, 15 and 21.
+		if obs.Time.Add(30*time.Minute).Hour()%6 == 0 {
+			setPrecip(obs, "Precip6HrMM", &obs.Precip6HrMM, tok, precip6Re.FindStringSubmatch(tok)[1])
+		} else {
+			setPrecip(obs, "Precip3HrMM", &obs.Precip3HrMM, tok, precip6Re.FindStringSubmatch(tok)[1])
+		}
+	case precip24Re.MatchString(tok):
+		setPrecip(obs, "Precip24HrMM", &obs.Precip24HrMM, tok, precip24Re.FindStringSubmatch(tok)[1])
+	case snowDepthRe.MatchString(tok):
+		v, _ := strconv.Atoi(snowDepthRe.FindStringSubmatch(tok)[1])
+		obs.SnowDepthMM = units.MustConvert(float64(v), units.Inches, units.Millimeters)
+		obs.Flags.Set("SnowDepthMM", &ds.Flag{Quality: ds.QualityPassed})
+	case tempExactRe.MatchString(tok):
+		// The temperature and dew point in tenths of degrees, with a leading
+		// 1 for below zero, replace the whole degrees from the body.
+		m := tempExactRe.FindStringSubmatch(tok)
+		obs.TempC = tenthsValue(m[1])
+		obs.Flags.Set("TempC", &ds.Flag{Quality: ds.QualityPassed})
+		if m[2] != "" {
+			obs.DewPointC = tenthsValue(m[2])
+			obs.Flags.Set("DewPointC", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+}
+
+// setPrecip sets a precipitation total given in hundredths of inches. A total
+// of 0000 is a trace.
+func setPrecip(obs *ds.Observation, name string, field *float64, tok, digits string) {
+	v, _ := strconv.Atoi(digits)
+	*field = units.MustConvert(float64(v), units.HundredthsInches, units.Millimeters)
+	if v == 0 {
+		obs.Flags.Set(name, &ds.Flag{Quality: ds.QualityTrace, Original: tok})
+		return
+	}
+	obs.Flags.Set(name, &ds.Flag{Quality: ds.QualityPassed})
+}
+
+// tenthsValue parses a value in tenths of degrees with a leading 1 for minus,
+// e.g. "1006" is -0.6.
+func tenthsValue(s string) float64 {
+	v, _ := strconv.Atoi(s[1:])
+	if s[0] == '1' {
+		v = -v
+	}
+	return units.MustConvert(float64(v), units.TenthsCelsius, units.Celsius)
+}
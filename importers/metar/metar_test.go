@@ -0,0 +1,490 @@
+package metar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *METAR
+		wantErr bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "METAR",
+			wantErr: true,
+		},
+		{
+			have:    "METAR KSFO",
+			wantErr: true,
+		},
+		{
+			have:    "METAR 010056Z 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "METAR ksfo 010056Z 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "METAR KSFO 29012KT 10SM",
+			wantErr: true,
+		},
+		{
+			have:    "KSFO 012456Z 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "KSFO 320056Z 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "KSFO 010060Z 29012KT",
+			wantErr: true,
+		},
+		// Only the station and time.
+		{
+			have: "KSFO 010056Z",
+			want: &METAR{Station: "KSFO", Day: 1, Minute: 56, Raw: "KSFO 010056Z"},
+		},
+		// Normal cases.
+		{
+			have: "METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 T01220083",
+			want: &METAR{
+				Type:        "METAR",
+				Station:     "KSFO",
+				Day:         1,
+				Hour:        0,
+				Minute:      56,
+				Wind:        &Wind{Direction: 290, Speed: 12, Gust: 20, Units: "KT"},
+				Visibility:  &Visibility{Whole: 10},
+				Sky:         []*SkyLayer{{Cover: "FEW", Height: 15}, {Cover: "BKN", Height: 40}},
+				Temperature: &Temperature{TempC: 12, DewPointC: 8},
+				Altimeter:   &Altimeter{Units: "A", Value: 3002},
+				Remarks:     []string{"AO2", "SLP165", "T01220083"},
+				Raw:         "METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 T01220083",
+			},
+		},
+		{
+			have: "SPECI KORD 151432Z AUTO COR 27015G25KT 250V310 1 1/2SM R28L/2400V4000FT -SN BR OVC008 M03/M05 A2985 RMK AO2",
+			want: &METAR{
+				Type:               "SPECI",
+				Station:            "KORD",
+				Day:                15,
+				Hour:               14,
+				Minute:             32,
+				Modifiers:          []string{"AUTO", "COR"},
+				Wind:               &Wind{Direction: 270, Speed: 15, Gust: 25, Units: "KT"},
+				WindVariation:      &WindVariation{From: 250, To: 310},
+				Visibility:         &Visibility{Whole: 1, Numerator: 1, Denominator: 2},
+				RunwayVisualRanges: []*RunwayVisualRange{{Runway: "28L", Range: 2400, MaxRange: 4000, Feet: true}},
+				Weather:            []string{"-SN", "BR"},
+				Sky:                []*SkyLayer{{Cover: "OVC", Height: 8}},
+				Temperature:        &Temperature{TempC: -3, DewPointC: -5},
+				Altimeter:          &Altimeter{Units: "A", Value: 2985},
+				Remarks:            []string{"AO2"},
+				Raw:                "SPECI KORD 151432Z AUTO COR 27015G25KT 250V310 1 1/2SM R28L/2400V4000FT -SN BR OVC008 M03/M05 A2985 RMK AO2",
+			},
+		},
+		{
+			have: "EGLL 011250Z 24008MPS 9999 R27L/P1500N NSC 15/ Q1013 NOSIG",
+			want: &METAR{
+				Station:            "EGLL",
+				Day:                1,
+				Hour:               12,
+				Minute:             50,
+				Wind:               &Wind{Direction: 240, Speed: 8, Units: "MPS"},
+				Visibility:         &Visibility{Metric: true, Meters: 9999},
+				RunwayVisualRanges: []*RunwayVisualRange{{Runway: "27L", Limit: "P", Range: 1500, Trend: "N"}},
+				Sky:                []*SkyLayer{{Cover: SkyNoSignificant}},
+				Temperature:        &Temperature{TempC: 15, DewPointC: ds.UnsetValue},
+				Altimeter:          &Altimeter{Units: "Q", Value: 1013},
+				Other:              []string{"NOSIG"},
+				Raw:                "EGLL 011250Z 24008MPS 9999 R27L/P1500N NSC 15/ Q1013 NOSIG",
+			},
+		},
+		// Extra whitespace and the end of message marker are dropped.
+		{
+			have: "  METAR  KSFO 010056Z\n 00000KT   CAVOK=",
+			want: &METAR{
+				Type:       "METAR",
+				Station:    "KSFO",
+				Day:        1,
+				Minute:     56,
+				Wind:       &Wind{Units: "KT"},
+				Visibility: &Visibility{CAVOK: true},
+				Raw:        "  METAR  KSFO 010056Z\n 00000KT   CAVOK=",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Parse(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+// TestObservationReport checks that the Report is the report exactly as it was
+// given, not an encoding of it.
+func TestObservationReport(t *testing.T) {
+	ref := time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)
+	tests := []string{
+		"METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002",
+		"METAR EGLL 010050Z /////KT 9999 NSC ///// Q////",
+		"  METAR  KSFO 010056Z\n 00000KT   CAVOK=",
+	}
+
+	for _, have := range tests {
+		got, err := ParseObservation(have, ref)
+		if err != nil {
+			t.Errorf("ParseObservation(%q) = %v", have, err)
+			continue
+		}
+		if got.Report != have {
+			t.Errorf("ParseObservation(%q).Report = %q, want the report unchanged", have, got.Report)
+		}
+	}
+}
+
+// TestRoundTrip checks that reports in the standard order encode back to the
+// same text.
+func TestRoundTrip(t *testing.T) {
+	tests := []string{
+		"KSFO 010056Z",
+		"METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 T01220083",
+		"METAR KJFK 011251Z 31016G28KT 10SM FEW050 SCT250 M02/M16 A3021 RMK AO2 PK WND 30032/1207 SLP230 T10221161",
+		"METAR KDEN 011253Z AUTO 00000KT 10SM CLR M12/M17 A3036 RMK AO2 SLP321 T11221167",
+		"METAR KORD 151432Z AUTO COR 27015G25KT 250V310 1 1/2SM R28L/2400V4000FT -SN BR OVC008 M03/M05 A2985 RMK AO2",
+		"SPECI KBOS 071617Z 04022G35KT 1/4SM R04R/1200V2400FT +SN FZFG VV004 M04/M05 A2968 RMK AO2 PK WND 04041/1555 P0012",
+		"METAR KMIA 201853Z 09012KT 10SM VCSH FEW025CB SCT040 BKN250 29/22 A3001 RMK AO2 CB DSNT W",
+		"SPECI KTPA 201912Z 27018G30KT 2SM +TSRAGR BKN015CB OVC040 24/21 A2995 RMK AO2 FRQ LTGICCG",
+		"METAR KSEA 101553Z 16008KT 3SM -RA BR OVC012 08/07 A2990 RMK AO2 RAB1505 P0004",
+		"METAR KLAX 011553Z VRB03KT 1/2SM FG VV002 13/13 A2999 RMK AO2 SLP154",
+		"METAR KPHX 011651Z 00000KT 10SM SKC 21/M04 A3015",
+		"METAR KBIS 021753Z 33025G38KT M1/4SM BLSN VV005 M23/M27 A3049 RMK AO2 PK WND 33042/1722",
+		"METAR KLHX 010700Z AUTO 27006KT 10SM CLR M01/M10 A3007 RMK AO2 T10061100",
+		"METAR KMCI 311753Z 18012KT 6SM HZ SCT030TCU 33/24 A2995 RMK AO2 60012 70125",
+		"METAR KMSP 010553Z 32010KT 10SM OVC///  M05/M08 A3012 RMK AO2 4/012 60000",
+		"METAR PGUM 010054Z 09015KT P6SM FEW020 30/24 A2988",
+		"METAR PANC 011053Z 00000KT 2 3/4SM BR OVC007 M01/M02 A2977 RMK AO2",
+		"METAR KATL 011952Z 29007KT 7SM -DZ OVC004 14/13 A3011 RMK AO2",
+		"EGLL 011250Z 24008MPS 9999 R27L/P1500N NSC 15/ Q1013 NOSIG",
+		"METAR LFPG 011230Z 22012KT 190V250 4000NDV -RA BKN012 OVC030 11/09 Q1002 TEMPO 3000 RA",
+		"METAR EDDF 011220Z 27015KT CAVOK 18/07 Q1021 NOSIG",
+		"METAR RJTT 010030Z 34012KT 9999 FEW030 08/M05 Q1022 NOSIG",
+		"METAR UUEE 011200Z 18005MPS 0800 R06C/M0600VP6000FT FZFG VV001 M10/M11 Q1030",
+		"METAR YSSY 010000Z 04015KT 9999 FEW040 24/17 Q1016",
+		"METAR SBGR 011200Z 11006KT 2000 BR BKN003 18/18 Q1019",
+		"METAR CYYZ 011300Z 28010KT 15SM FEW035 M00/M06 A3002 RMK SC2 SLP175",
+		"METAR CYUL 011300Z 36025KMH 1SM -SN DRSN OVC010 M08/M10 A2980",
+		"METAR KXYZ 011200Z 090105G130KT 1/16SM +TSRA VV000 27/26 A2845",
+	}
+
+	for _, have := range tests {
+		m, err := Parse(have)
+		if err != nil {
+			t.Errorf("Parse(%q) = %v", have, err)
+			continue
+		}
+		want := normalize(have)
+		if got := m.String(); got != want {
+			t.Errorf("Parse(%q).String() = %q, want %q", have, got, want)
+		}
+	}
+}
+
+// TestReorder checks that reports out of the standard order, or with groups
+// which are not understood, encode with the groups in the standard order.
+func TestReorder(t *testing.T) {
+	tests := []struct {
+		have string
+		want string
+	}{
+		{
+			have: "METAR KSFO 010056Z 10SM 29012KT BKN040 FEW015 A3002 12/08",
+			want: "METAR KSFO 010056Z 29012KT 10SM BKN040 FEW015 12/08 A3002",
+		},
+		{
+			have: "METAR EGLL 010050Z /////KT 9999 NSC ///// Q////",
+			want: "METAR EGLL 010050Z 9999 NSC /////KT ///// Q////",
+		},
+		{
+			have: "METAR KMSP 010553Z AUTO /////KT 10SM OVC/// M05/M08 A////",
+			want: "METAR KMSP 010553Z AUTO 10SM OVC/// M05/M08 /////KT A////",
+		},
+		{
+			have: "METAR KDEN 011253Z AUTO 00000KT 10SM CLR M12/M17 A3036 ////// RMK AO2",
+			want: "METAR KDEN 011253Z AUTO 00000KT 10SM CLR M12/M17 A3036 ////// RMK AO2",
+		},
+	}
+
+	for _, test := range tests {
+		m, err := Parse(test.have)
+		if err != nil {
+			t.Errorf("Parse(%q) = %v", test.have, err)
+			continue
+		}
+		if got := m.String(); got != test.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", test.have, got, test.want)
+		}
+		if m.Raw != test.have {
+			t.Errorf("Parse(%q).Raw = %q, want the report unchanged", test.have, m.Raw)
+		}
+	}
+}
+
+// normalize returns the text with single spaces between the groups.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestObservation(t *testing.T) {
+	ref := time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)
+	feet := func(v float64) float64 { return units.MustConvert(v, units.Feet, units.Meters) }
+	knots := func(v float64) float64 { return units.MustConvert(v, units.Knots, units.MetersPerSecond) }
+
+	const (
+		full      = "METAR KSFO 010056Z 29012G20KT 10SM FEW015 BKN040 12/08 A3002 RMK AO2 SLP165 P0003 T01220083"
+		calm      = "KSFO 010050Z 00000KT 1 1/2SM R28L/2400V4000FT/U R28R/P6000FT -RA BR VV003 M01/M03 A2992"
+		metric    = "SPECI KSFO 311230Z 24008MPS 9999 +TSRAGR SCT020CB OVC/// 15/ Q1013"
+		remarks   = "KSFO 010553Z AUTO VRB03KT M1/4SM CLR M00/M01 A3010 RMK AO2 SLP982 60000 70125 4/012 T10021006"
+		threeHour = "KSFO 010253Z 27005KT 10SM SCT250 10/05 A3001 RMK AO2 60012"
+	)
+
+	tests := []struct {
+		have    string
+		want    *ds.Observation
+		wantErr bool
+	}{
+		{
+			have: full,
+			want: &ds.Observation{
+				StationID:            "KSFO",
+				Time:                 time.Date(2023, 1, 1, 0, 56, 0, 0, time.UTC),
+				TempC:                units.MustConvert(122, units.TenthsCelsius, units.Celsius),
+				DewPointC:            units.MustConvert(83, units.TenthsCelsius, units.Celsius),
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  units.MustConvert(10165, units.TenthsHectopascals, units.Hectopascals),
+				PressureAltimeterHPa: units.MustConvert(3002, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     290,
+				WindSpeedMPS:         knots(12),
+				WindGustMPS:          knots(20),
+				VisibilityM:          units.MustConvert(10, units.StatuteMiles, units.Meters),
+				SkyCoverOktas:        7,
+				CloudLayers: []*ds.CloudLayer{
+					{Cover: ds.CloudCoverFew, BaseM: feet(1500)},
+					{Cover: ds.CloudCoverBroken, BaseM: feet(4000)},
+				},
+				CeilingM:     feet(4000),
+				Precip1HrMM:  units.MustConvert(3, units.HundredthsInches, units.Millimeters),
+				Precip3HrMM:  ds.UnsetValue,
+				Precip6HrMM:  ds.UnsetValue,
+				Precip24HrMM: ds.UnsetValue,
+				SnowDepthMM:  ds.UnsetValue,
+				Report:       full,
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureSeaLevelHPa":  {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"WindGustMPS":          {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+					"CeilingM":             {Quality: ds.QualityPassed},
+					"Precip1HrMM":          {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			have: calm,
+			want: &ds.Observation{
+				StationID:            "KSFO",
+				Time:                 time.Date(2023, 1, 1, 0, 50, 0, 0, time.UTC),
+				TempC:                -1,
+				DewPointC:            -3,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: units.MustConvert(2992, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         0,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          units.MustConvert(1.5, units.StatuteMiles, units.Meters),
+				RunwayVisualRanges: []*ds.RunwayVisualRange{
+					{Runway: "28L", RangeM: feet(2400), MaxRangeM: feet(4000), Trend: "U"},
+					{Runway: "28R", RangeM: feet(6000), MaxRangeM: ds.UnsetValue, Limit: "P"},
+				},
+				SkyCoverOktas:  8,
+				CloudLayers:    []*ds.CloudLayer{{Cover: ds.CloudCoverObscured, BaseM: feet(300)}},
+				CeilingM:       feet(300),
+				Precip1HrMM:    ds.UnsetValue,
+				Precip3HrMM:    ds.UnsetValue,
+				Precip6HrMM:    ds.UnsetValue,
+				Precip24HrMM:   ds.UnsetValue,
+				PresentWeather: []string{"-RA", "BR"},
+				SnowDepthMM:    ds.UnsetValue,
+				Report:         calm,
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+					"CeilingM":             {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			// The day is in the month before the reference.
+			have: metric,
+			want: &ds.Observation{
+				StationID:            "KSFO",
+				Time:                 time.Date(2022, 12, 31, 12, 30, 0, 0, time.UTC),
+				TempC:                15,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: 1013,
+				WindDirectionDeg:     240,
+				WindSpeedMPS:         8,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          10000,
+				SkyCoverOktas:        8,
+				CloudLayers: []*ds.CloudLayer{
+					{Cover: ds.CloudCoverScattered, BaseM: feet(2000), Type: "CB"},
+					{Cover: ds.CloudCoverOvercast, BaseM: ds.UnsetValue},
+				},
+				CeilingM:       ds.UnsetValue,
+				Precip1HrMM:    ds.UnsetValue,
+				Precip3HrMM:    ds.UnsetValue,
+				Precip6HrMM:    ds.UnsetValue,
+				Precip24HrMM:   ds.UnsetValue,
+				PresentWeather: []string{"+TSRAGR"},
+				SnowDepthMM:    ds.UnsetValue,
+				Report:         metric,
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed, Original: "9999"},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+				},
+			},
+		},
+		{
+			have: remarks,
+			want: &ds.Observation{
+				StationID:            "KSFO",
+				Time:                 time.Date(2023, 1, 1, 5, 53, 0, 0, time.UTC),
+				TempC:                units.MustConvert(-2, units.TenthsCelsius, units.Celsius),
+				DewPointC:            units.MustConvert(-6, units.TenthsCelsius, units.Celsius),
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  units.MustConvert(9982, units.TenthsHectopascals, units.Hectopascals),
+				PressureAltimeterHPa: units.MustConvert(3010, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         knots(3),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          units.MustConvert(0.25, units.StatuteMiles, units.Meters),
+				SkyCoverOktas:        0,
+				CloudLayers:          []*ds.CloudLayer{{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue}},
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          0,
+				Precip24HrMM:         units.MustConvert(125, units.HundredthsInches, units.Millimeters),
+				SnowDepthMM:          units.MustConvert(12, units.Inches, units.Millimeters),
+				Report:               remarks,
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureSeaLevelHPa":  {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed, Original: "M1/4SM"},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+					"Precip6HrMM":          {Quality: ds.QualityTrace, Original: "60000"},
+					"Precip24HrMM":         {Quality: ds.QualityPassed},
+					"SnowDepthMM":          {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			have: threeHour,
+			want: &ds.Observation{
+				StationID:            "KSFO",
+				Time:                 time.Date(2023, 1, 1, 2, 53, 0, 0, time.UTC),
+				TempC:                10,
+				DewPointC:            5,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: units.MustConvert(3001, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         knots(5),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          units.MustConvert(10, units.StatuteMiles, units.Meters),
+				SkyCoverOktas:        4,
+				CloudLayers:          []*ds.CloudLayer{{Cover: ds.CloudCoverScattered, BaseM: feet(25000)}},
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          units.MustConvert(12, units.HundredthsInches, units.Millimeters),
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Report:               threeHour,
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+					"Precip3HrMM":          {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		// There is no day 30 in the February before the reference.
+		{have: "KSFO 301200Z 27005KT", wantErr: true},
+	}
+
+	for _, test := range tests {
+		r := ref
+		if test.wantErr {
+			r = time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+		}
+		got, err := ParseObservation(test.have, r)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseObservation(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseObservation(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
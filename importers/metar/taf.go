@@ -0,0 +1,348 @@
+package metar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+var (
+	periodRe      = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	fromRe        = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	probabilityRe = regexp.MustCompile(`^PROB(30|40)$`)
+)
+
+// Period is the period of a TAF or of one of its change groups, from the
+// start day and hour to the end day and hour, e.g. "0118/0224". The end hour
+// may be 24 for the end of the day.
+type Period struct {
+	FromDay, FromHour int
+	ToDay, ToHour     int
+}
+
+// parsePeriod parses a period group, returning false if it is not one.
+func parsePeriod(tok string) (Period, bool) {
+	m := periodRe.FindStringSubmatch(tok)
+	if m == nil {
+		return Period{}, false
+	}
+	var p Period
+	p.FromDay, _ = strconv.Atoi(m[1])
+	p.FromHour, _ = strconv.Atoi(m[2])
+	p.ToDay, _ = strconv.Atoi(m[3])
+	p.ToHour, _ = strconv.Atoi(m[4])
+	if p.FromDay < 1 || p.FromDay > 31 || p.ToDay < 1 || p.ToDay > 31 || p.FromHour > 24 || p.ToHour > 24 {
+		return Period{}, false
+	}
+	return p, true
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%02d%02d/%02d%02d", p.FromDay, p.FromHour, p.ToDay, p.ToHour)
+}
+
+// Forecast is the base forecast of a TAF or one of its change groups.
+type Forecast struct {
+	// Change is ds.ForecastChangeBase for the base forecast, or the
+	// ds.ForecastChange of the change group.
+	Change string
+	// Probability is the percent probability of a PROB30 or PROB40 group,
+	// or 0. It is either ds.ForecastChangeProbable, or followed by TEMPO.
+	Probability int
+	// FromDay, FromHour and FromMinute are the start of an FM group.
+	FromDay, FromHour, FromMinute int
+	// Period is the period of BECMG, TEMPO and PROB groups.
+	Period Period
+
+	Wind       *Wind
+	Visibility *Visibility
+	// Weather are the weather groups, e.g. "-RA", "BR", or NSW for no
+	// significant weather.
+	Weather []string
+	Sky     []*SkyLayer
+	// Other are the groups which were not understood, such as wind shear or
+	// temperature forecasts.
+	Other []string
+}
+
+// header returns the groups which start the forecast.
+func (f *Forecast) header() []string {
+	var groups []string
+	if f.Probability != 0 {
+		groups = append(groups, fmt.Sprintf("PROB%d", f.Probability))
+	}
+	switch f.Change {
+	case ds.ForecastChangeFrom:
+		return append(groups, fmt.Sprintf("FM%02d%02d%02d", f.FromDay, f.FromHour, f.FromMinute))
+	case ds.ForecastChangeBase:
+		return groups
+	case ds.ForecastChangeBecoming, ds.ForecastChangeTemporary:
+		groups = append(groups, f.Change)
+	}
+	// ds.ForecastChangeProbable is only the probability and period.
+	return append(groups, f.Period.String())
+}
+
+func (f *Forecast) String() string {
+	groups := f.header()
+	if f.Wind != nil {
+		groups = append(groups, f.Wind.String())
+	}
+	if f.Visibility != nil {
+		groups = append(groups, f.Visibility.String())
+	}
+	groups = append(groups, f.Weather...)
+	for _, l := range f.Sky {
+		groups = append(groups, l.String())
+	}
+	groups = append(groups, f.Other...)
+	return strings.Join(groups, " ")
+}
+
+// parseGroup decodes one group of the forecast into f.
+func (f *Forecast) parseGroup(tok string) {
+	if w, ok := parseWind(tok); ok && f.Wind == nil {
+		f.Wind = w
+		return
+	}
+	if v, ok := parseVisibility(tok); ok && f.Visibility == nil {
+		f.Visibility = v
+		return
+	}
+	if tok == "NSW" || isWeather(tok) {
+		f.Weather = append(f.Weather, tok)
+		return
+	}
+	if l, ok := parseSkyLayer(tok); ok {
+		f.Sky = append(f.Sky, l)
+		return
+	}
+	f.Other = append(f.Other, tok)
+}
+
+// TAF is a decoded terminal aerodrome forecast.
+//
+// String encodes each forecast in the standard order of its groups, with
+// Other at the end, so forecasts in the standard order encode back to the
+// same text with single spaces between the groups. Groups out of the standard
+// order, or not understood, are moved, so Raw keeps the forecast as given.
+type TAF struct {
+	// Modifier is AMD for an amended forecast, COR for a corrected one, or "".
+	Modifier string
+	Station  string
+	// Day, Hour and Minute are the UTC time the forecast was issued.
+	Day, Hour, Minute int
+	// Valid is the period the forecast is valid for.
+	Valid Period
+	// Forecasts are the base forecast followed by the change groups. A
+	// cancelled forecast has none.
+	Forecasts []*Forecast
+	// Cancelled is true for a forecast which cancels the previous one for
+	// the valid period, (CNL).
+	Cancelled bool
+
+	// Raw is the forecast exactly as it was given to ParseTAF.
+	Raw string
+}
+
+// ParseTAF decodes a terminal aerodrome forecast. The forecast may be over
+// several lines, and may end with "=". A missing forecast, (NIL), is an
+// error, and a cancelled one, (CNL), has no Forecasts.
+//
+//	TAF KSFO 011720Z 0118/0224 29012KT P6SM FEW015 BKN040
+//	  FM012000 30015G25KT P6SM SCT020
+//	  TEMPO 0120/0124 3SM -RA BR BKN010
+//	  PROB30 0206/0210 2SM BR
+func ParseTAF(report string) (*TAF, error) {
+	tokens := strings.Fields(strings.TrimSuffix(strings.TrimSpace(report), "="))
+	if len(tokens) > 0 && tokens[0] == "TAF" {
+		tokens = tokens[1:]
+	}
+	t := &TAF{Raw: report}
+	if len(tokens) > 0 && (tokens[0] == "AMD" || tokens[0] == "COR") {
+		t.Modifier = tokens[0]
+		tokens = tokens[1:]
+	}
+	if len(tokens) < 3 || !stationRe.MatchString(tokens[0]) {
+		return nil, fmt.Errorf("metar: missing station in TAF %q", report)
+	}
+	t.Station = tokens[0]
+
+	var err error
+	if t.Day, t.Hour, t.Minute, err = parseTime(tokens[1]); err != nil {
+		return nil, fmt.Errorf("metar: %v in TAF %q", err, report)
+	}
+	if tokens[2] == "NIL" || len(tokens) > 3 && tokens[3] == "NIL" {
+		return nil, fmt.Errorf("metar: missing forecast in TAF %q", report)
+	}
+	var ok bool
+	if t.Valid, ok = parsePeriod(tokens[2]); !ok {
+		return nil, fmt.Errorf("metar: invalid valid period %q in TAF %q", tokens[2], report)
+	}
+
+	tokens = tokens[3:]
+	if len(tokens) > 0 && tokens[0] == "CNL" {
+		t.Cancelled = true
+		return t, nil
+	}
+
+	f := &Forecast{Change: ds.ForecastChangeBase}
+	t.Forecasts = append(t.Forecasts, f)
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		next := func() string {
+			if i+1 < len(tokens) {
+				return tokens[i+1]
+			}
+			return ""
+		}
+
+		switch {
+		case fromRe.MatchString(tok):
+			m := fromRe.FindStringSubmatch(tok)
+			f = &Forecast{Change: ds.ForecastChangeFrom}
+			f.FromDay, _ = strconv.Atoi(m[1])
+			f.FromHour, _ = strconv.Atoi(m[2])
+			f.FromMinute, _ = strconv.Atoi(m[3])
+			t.Forecasts = append(t.Forecasts, f)
+			continue
+		case tok == ds.ForecastChangeBecoming || tok == ds.ForecastChangeTemporary || probabilityRe.MatchString(tok):
+			f = &Forecast{Change: ds.ForecastChangeProbable}
+			if m := probabilityRe.FindStringSubmatch(tok); m != nil {
+				f.Probability, _ = strconv.Atoi(m[1])
+				if next() == ds.ForecastChangeTemporary {
+					i++
+					tok = tokens[i]
+				}
+			}
+			if tok == ds.ForecastChangeBecoming || tok == ds.ForecastChangeTemporary {
+				f.Change = tok
+			}
+			p, ok := parsePeriod(next())
+			if !ok {
+				return nil, fmt.Errorf("metar: missing period after %s in TAF %q", tok, report)
+			}
+			f.Period = p
+			i++
+			t.Forecasts = append(t.Forecasts, f)
+			continue
+		}
+
+		// Visibilities of over a mile with a fraction are split over two
+		// groups, e.g. "1 1/2SM".
+		if f.Visibility == nil && next() != "" {
+			if v, ok := parseVisibility(tok + " " + next()); ok {
+				f.Visibility = v
+				i++
+				continue
+			}
+		}
+		f.parseGroup(tok)
+	}
+
+	return t, nil
+}
+
+// String encodes the forecast with single spaces between the groups.
+func (t *TAF) String() string {
+	groups := []string{"TAF"}
+	if t.Modifier != "" {
+		groups = append(groups, t.Modifier)
+	}
+	groups = append(groups, t.Station, fmt.Sprintf("%02d%02d%02dZ", t.Day, t.Hour, t.Minute), t.Valid.String())
+	if t.Cancelled {
+		groups = append(groups, "CNL")
+	}
+	for _, f := range t.Forecasts {
+		if s := f.String(); s != "" {
+			groups = append(groups, s)
+		}
+	}
+	return strings.Join(groups, " ")
+}
+
+// ForecastPeriods converts the forecast into a ForecastPeriod for the base
+// forecast and each change group, with the forecast as their Report, (Raw, or
+// if the forecast was not from ParseTAF, its String encoding). A cancelled
+// forecast has no periods. The forecast only gives the day of the month, so ref is a time at or shortly
+// after it was issued, from which the year and month are taken.
+//
+// The base forecast and FM groups last until the next FM group or the end of
+// the forecast.
+func (t *TAF) ForecastPeriods(ref time.Time) ([]*ds.ForecastPeriod, error) {
+	issued, err := reportTime(t.Day, t.Hour, t.Minute, ref)
+	if err != nil {
+		return nil, err
+	}
+	if t.Cancelled {
+		return nil, nil
+	}
+	validEnd := periodTime(t.Valid.ToDay, t.Valid.ToHour, 0, issued)
+	report := t.Raw
+	if report == "" {
+		report = t.String()
+	}
+
+	var out []*ds.ForecastPeriod
+	var prevailing *ds.ForecastPeriod
+	for _, f := range t.Forecasts {
+		fp := ds.EmptyForecastPeriod()
+		fp.StationID = t.Station
+		fp.Issued = issued
+		fp.Change = f.Change
+		fp.Report = report
+		if f.Probability != 0 {
+			fp.Probability = float64(f.Probability)
+		}
+
+		switch f.Change {
+		case ds.ForecastChangeBase:
+			fp.Start = periodTime(t.Valid.FromDay, t.Valid.FromHour, 0, issued)
+		case ds.ForecastChangeFrom:
+			fp.Start = periodTime(f.FromDay, f.FromHour, f.FromMinute, issued)
+		default:
+			fp.Start = periodTime(f.Period.FromDay, f.Period.FromHour, 0, issued)
+			fp.End = periodTime(f.Period.ToDay, f.Period.ToHour, 0, issued)
+		}
+		if f.Change == ds.ForecastChangeBase || f.Change == ds.ForecastChangeFrom {
+			if prevailing != nil {
+				prevailing.End = fp.Start
+			}
+			prevailing = fp
+			fp.End = validEnd
+		}
+
+		if w := f.Wind; w != nil {
+			fp.WindDirectionDeg = w.DirectionDeg()
+			fp.WindSpeedMPS = w.SpeedMPS()
+			fp.WindGustMPS = w.GustMPS()
+		}
+		if f.Visibility != nil {
+			fp.VisibilityM = f.Visibility.VisibilityM()
+		}
+		for _, w := range f.Weather {
+			if w != "NSW" {
+				fp.PresentWeather = append(fp.PresentWeather, w)
+			}
+		}
+		if len(f.Sky) > 0 {
+			fp.CloudLayers, _, fp.CeilingM = cloudLayers(f.Sky)
+		}
+		out = append(out, fp)
+	}
+	return out, nil
+}
+
+// periodTime returns the time of a day and time in a forecast issued at
+// issued. Days before the day of issue are in the following month.
+func periodTime(day, hour, minute int, issued time.Time) time.Time {
+	month := issued.Month()
+	if day < issued.Day() {
+		month++
+	}
+	return time.Date(issued.Year(), month, day, hour, minute, 0, 0, time.UTC)
+}
@@ -0,0 +1,342 @@
+package metar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const testTAF = `TAF KSFO 011720Z 0118/0224 29012KT P6SM FEW015 BKN040
+  FM012000 30015G25KT P6SM SCT020
+  TEMPO 0120/0124 3SM -RA BR BKN010
+  PROB30 0206/0210 2SM BR
+  BECMG 0212/0214 VRB03KT=`
+
+func TestParseTAF(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *TAF
+		wantErr bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "TAF",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z",
+			wantErr: true,
+		},
+		{
+			have:    "TAF 011720Z 0118/0224 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 0118/0224 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z 011818 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z 0118/3225 29012KT",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z 0118/0224 29012KT TEMPO 3SM",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z 0118/0224 29012KT PROB30",
+			wantErr: true,
+		},
+		// Normal cases.
+		{
+			have: testTAF,
+			want: &TAF{
+				Station: "KSFO",
+				Day:     1,
+				Hour:    17,
+				Minute:  20,
+				Valid:   Period{FromDay: 1, FromHour: 18, ToDay: 2, ToHour: 24},
+				Forecasts: []*Forecast{
+					{
+						Change:     ds.ForecastChangeBase,
+						Wind:       &Wind{Direction: 290, Speed: 12, Units: "KT"},
+						Visibility: &Visibility{Limit: "P", Whole: 6},
+						Sky:        []*SkyLayer{{Cover: "FEW", Height: 15}, {Cover: "BKN", Height: 40}},
+					},
+					{
+						Change:     ds.ForecastChangeFrom,
+						FromDay:    1,
+						FromHour:   20,
+						Wind:       &Wind{Direction: 300, Speed: 15, Gust: 25, Units: "KT"},
+						Visibility: &Visibility{Limit: "P", Whole: 6},
+						Sky:        []*SkyLayer{{Cover: "SCT", Height: 20}},
+					},
+					{
+						Change:     ds.ForecastChangeTemporary,
+						Period:     Period{FromDay: 1, FromHour: 20, ToDay: 1, ToHour: 24},
+						Visibility: &Visibility{Whole: 3},
+						Weather:    []string{"-RA", "BR"},
+						Sky:        []*SkyLayer{{Cover: "BKN", Height: 10}},
+					},
+					{
+						Change:      ds.ForecastChangeProbable,
+						Probability: 30,
+						Period:      Period{FromDay: 2, FromHour: 6, ToDay: 2, ToHour: 10},
+						Visibility:  &Visibility{Whole: 2},
+						Weather:     []string{"BR"},
+					},
+					{
+						Change: ds.ForecastChangeBecoming,
+						Period: Period{FromDay: 2, FromHour: 12, ToDay: 2, ToHour: 14},
+						Wind:   &Wind{Variable: true, Speed: 3, Units: "KT"},
+					},
+				},
+				Raw: testTAF,
+			},
+		},
+		{
+			have: "TAF AMD EGLL 011055Z 0112/0218 24010KT 9999 SCT030 PROB40 TEMPO 0114/0118 4000 SHRA NSW TX15/0114Z",
+			want: &TAF{
+				Modifier: "AMD",
+				Station:  "EGLL",
+				Day:      1,
+				Hour:     10,
+				Minute:   55,
+				Valid:    Period{FromDay: 1, FromHour: 12, ToDay: 2, ToHour: 18},
+				Forecasts: []*Forecast{
+					{
+						Change:     ds.ForecastChangeBase,
+						Wind:       &Wind{Direction: 240, Speed: 10, Units: "KT"},
+						Visibility: &Visibility{Metric: true, Meters: 9999},
+						Sky:        []*SkyLayer{{Cover: "SCT", Height: 30}},
+					},
+					{
+						Change:      ds.ForecastChangeTemporary,
+						Probability: 40,
+						Period:      Period{FromDay: 1, FromHour: 14, ToDay: 1, ToHour: 18},
+						Visibility:  &Visibility{Metric: true, Meters: 4000},
+						Weather:     []string{"SHRA", "NSW"},
+						Other:       []string{"TX15/0114Z"},
+					},
+				},
+				Raw: "TAF AMD EGLL 011055Z 0112/0218 24010KT 9999 SCT030 PROB40 TEMPO 0114/0118 4000 SHRA NSW TX15/0114Z",
+			},
+		},
+		// Cancelled forecasts have no forecasts, and missing ones are errors.
+		{
+			have: "TAF AMD KSFO 011820Z 0118/0224 CNL=",
+			want: &TAF{
+				Modifier:  "AMD",
+				Station:   "KSFO",
+				Day:       1,
+				Hour:      18,
+				Minute:    20,
+				Valid:     Period{FromDay: 1, FromHour: 18, ToDay: 2, ToHour: 24},
+				Cancelled: true,
+				Raw:       "TAF AMD KSFO 011820Z 0118/0224 CNL=",
+			},
+		},
+		{
+			have:    "TAF KSFO 011720Z NIL=",
+			wantErr: true,
+		},
+		{
+			have:    "TAF KSFO 011720Z 0118/0224 NIL=",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseTAF(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseTAF(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseTAF(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+// TestTAFRoundTrip checks that forecasts in the standard order encode back to
+// the same text.
+func TestTAFRoundTrip(t *testing.T) {
+	tests := []string{
+		"TAF KSFO 011720Z 0118/0224 29012KT P6SM FEW015 BKN040",
+		"TAF KSFO 011720Z 0118/0224 29012KT P6SM FEW015 BKN040 FM012000 30015G25KT P6SM SCT020 TEMPO 0120/0124 3SM -RA BR BKN010",
+		"TAF AMD KJFK 011738Z 0118/0224 31015G25KT P6SM SCT050 FM020200 32010KT P6SM FEW250 FM021400 VRB05KT P6SM SKC",
+		"TAF COR KORD 151120Z 1512/1618 27012KT 1 1/2SM -SN BR OVC008 TEMPO 1512/1516 1/2SM SN VV005 FM151800 29015G25KT 3SM -SN BKN015",
+		"TAF KDEN 011130Z 0112/0218 00000KT P6SM SKC BECMG 0114/0116 33010KT",
+		"TAF KMIA 201730Z 2018/2124 09012KT P6SM VCSH FEW025 SCT040 PROB30 2018/2022 3SM TSRA BKN020CB",
+		"TAF KBOS 071730Z 0718/0824 04020G35KT 1/2SM +SN BLSN VV005 FM080600 36015G25KT 2SM -SN BKN010 OVC020",
+		"TAF KSEA 101720Z 1018/1124 16008KT 5SM -RA BR OVC015 TEMPO 1018/1022 3SM RA OVC010 FM110600 18010KT P6SM BKN030",
+		"TAF KLAX 011720Z 0118/0224 VRB03KT 1/4SM FG VV001 FM011900 25008KT P6SM SKC WS015/25040KT",
+		"TAF EGLL 011055Z 0112/0218 24010KT 9999 SCT030 PROB40 TEMPO 0114/0118 4000 SHRA NSW",
+		"TAF LFPG 311100Z 3112/0118 22012KT CAVOK BECMG 3118/3120 4000 -RA BKN012 TEMPO 0100/0106 1500 BR",
+		"TAF EDDF 011100Z 0112/0218 27015KT 9999 NSC TX18/0114Z TN07/0205Z",
+		"TAF RJTT 010500Z 0106/0212 34012KT 9999 FEW030 BECMG 0109/0111 02008KT",
+		"TAF YSSY 010500Z 0106/0212 04015KT 9999 FEW040 FM010900 18010KT 9999 SCT030 PROB30 0112/0116 4000 SHRA",
+		"TAF UUEE 011100Z 0112/0212 18005MPS 0800 FZFG VV001 BECMG 0114/0116 2000 BR OVC004",
+		"TAF CYUL 011140Z 0112/0212 36025KMH 1SM -SN DRSN OVC010 FM011800 33020KMH P6SM SCT030",
+		"TAF AMD KSFO 011820Z 0118/0224 CNL",
+	}
+
+	for _, have := range tests {
+		taf, err := ParseTAF(have)
+		if err != nil {
+			t.Errorf("ParseTAF(%q) = %v", have, err)
+			continue
+		}
+		if got := taf.String(); got != have {
+			t.Errorf("ParseTAF(%q).String() = %q, want %q", have, got, have)
+		}
+	}
+}
+
+func TestForecastPeriods(t *testing.T) {
+	taf, err := ParseTAF(testTAF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC)
+	knots := func(v float64) float64 { return units.MustConvert(v, units.Knots, units.MetersPerSecond) }
+	feet := func(v float64) float64 { return units.MustConvert(v, units.Feet, units.Meters) }
+	miles := func(v float64) float64 { return units.MustConvert(v, units.StatuteMiles, units.Meters) }
+	at := func(day, hour int) time.Time { return time.Date(2023, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	got, err := taf.ForecastPeriods(ref)
+	if err != nil {
+		t.Fatalf("ForecastPeriods() = %v", err)
+	}
+	want := []*ds.ForecastPeriod{
+		{
+			StationID:        "KSFO",
+			Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+			Start:            at(1, 18),
+			End:              at(1, 20),
+			Change:           ds.ForecastChangeBase,
+			Probability:      ds.UnsetValue,
+			WindDirectionDeg: 290,
+			WindSpeedMPS:     knots(12),
+			WindGustMPS:      ds.UnsetValue,
+			VisibilityM:      miles(6),
+			CloudLayers: []*ds.CloudLayer{
+				{Cover: ds.CloudCoverFew, BaseM: feet(1500)},
+				{Cover: ds.CloudCoverBroken, BaseM: feet(4000)},
+			},
+			CeilingM: feet(4000),
+			Report:   testTAF,
+		},
+		{
+			StationID:        "KSFO",
+			Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+			Start:            at(1, 20),
+			End:              at(3, 0),
+			Change:           ds.ForecastChangeFrom,
+			Probability:      ds.UnsetValue,
+			WindDirectionDeg: 300,
+			WindSpeedMPS:     knots(15),
+			WindGustMPS:      knots(25),
+			VisibilityM:      miles(6),
+			CloudLayers:      []*ds.CloudLayer{{Cover: ds.CloudCoverScattered, BaseM: feet(2000)}},
+			CeilingM:         ds.UnsetValue,
+			Report:           testTAF,
+		},
+		{
+			StationID:        "KSFO",
+			Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+			Start:            at(1, 20),
+			End:              at(2, 0),
+			Change:           ds.ForecastChangeTemporary,
+			Probability:      ds.UnsetValue,
+			WindDirectionDeg: ds.UnsetValue,
+			WindSpeedMPS:     ds.UnsetValue,
+			WindGustMPS:      ds.UnsetValue,
+			VisibilityM:      miles(3),
+			CloudLayers:      []*ds.CloudLayer{{Cover: ds.CloudCoverBroken, BaseM: feet(1000)}},
+			CeilingM:         feet(1000),
+			PresentWeather:   []string{"-RA", "BR"},
+			Report:           testTAF,
+		},
+		{
+			StationID:        "KSFO",
+			Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+			Start:            at(2, 6),
+			End:              at(2, 10),
+			Change:           ds.ForecastChangeProbable,
+			Probability:      30,
+			WindDirectionDeg: ds.UnsetValue,
+			WindSpeedMPS:     ds.UnsetValue,
+			WindGustMPS:      ds.UnsetValue,
+			VisibilityM:      miles(2),
+			CeilingM:         ds.UnsetValue,
+			PresentWeather:   []string{"BR"},
+			Report:           testTAF,
+		},
+		{
+			StationID:        "KSFO",
+			Issued:           time.Date(2023, 1, 1, 17, 20, 0, 0, time.UTC),
+			Start:            at(2, 12),
+			End:              at(2, 14),
+			Change:           ds.ForecastChangeBecoming,
+			Probability:      ds.UnsetValue,
+			WindDirectionDeg: ds.UnsetValue,
+			WindSpeedMPS:     knots(3),
+			WindGustMPS:      ds.UnsetValue,
+			VisibilityM:      ds.UnsetValue,
+			CeilingM:         ds.UnsetValue,
+			Report:           testTAF,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ForecastPeriods() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestForecastPeriodsCancelled(t *testing.T) {
+	taf, err := ParseTAF("TAF AMD KSFO 011820Z 0118/0224 CNL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := taf.ForecastPeriods(time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC))
+	if err != nil || len(got) != 0 {
+		t.Errorf("ForecastPeriods() = %v, %v, want no periods", got, err)
+	}
+}
+
+func TestForecastPeriodsMonthEnd(t *testing.T) {
+	taf, err := ParseTAF("TAF LFPG 311100Z 3112/0118 22012KT CAVOK BECMG 3118/3120 4000 -RA BKN012 FM010600 27010KT 9999 NSC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := taf.ForecastPeriods(time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ForecastPeriods() = %v", err)
+	}
+
+	want := []struct{ start, end time.Time }{
+		{time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC), time.Date(2023, 2, 1, 6, 0, 0, 0, time.UTC)},
+		{time.Date(2023, 1, 31, 18, 0, 0, 0, time.UTC), time.Date(2023, 1, 31, 20, 0, 0, 0, time.UTC)},
+		{time.Date(2023, 2, 1, 6, 0, 0, 0, time.UTC), time.Date(2023, 2, 1, 18, 0, 0, 0, time.UTC)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ForecastPeriods() returned %d periods, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if !got[i].Start.Equal(w.start) || !got[i].End.Equal(w.end) {
+			t.Errorf("ForecastPeriods()[%d] = %v to %v, want %v to %v", i, got[i].Start, got[i].End, w.start, w.end)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package epa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rsned/weather/importers/aqi"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// aqiStandards maps the Pollutant Standards of the daily summaries with a US
+// AQI, (without the year the standard was set), to the AQI pollutant and
+// averaging period. The summaries for the other standards, e.g. "CO 1-hour
+// 1971" and "SO2 3-hour 1971", have no AQI.
+var aqiStandards = map[string]aqi.Pollutant{
+	"Ozone 8-hour": aqi.Ozone8Hour,
+	"PM25 24-hour": aqi.PM25,
+	"PM25 Annual":  aqi.PM25,
+	"PM10 24-hour": aqi.PM10,
+	"CO 8-hour":    aqi.CO,
+	"SO2 1-hour":   aqi.SO2,
+	"NO2 1-hour":   aqi.NO2,
+	"NO2 Annual":   aqi.NO2,
+}
+
+// AQI computes the US AQI of a daily summary from the daily files, (one with
+// a PollutantStandard), with the given table, to check the AQI reported with
+// it or to fill it in. Use aqi.TableFor(obs.Time) for the table in use on
+// the day.
+//
+// As in AQS, particulates use the mean for the day, and the gases the highest
+// of their 1 hour, (SO2 and NO2), or 8 hour running averages, (ozone and CO).
+func AQI(obs *ds.PollutantObservation, table *aqi.Table) (int, error) {
+	if obs.PollutantStandard == "" {
+		return 0, fmt.Errorf("epa: AQI needs a daily summary for a pollutant standard, have %s", obs.SampleDuration)
+	}
+	standard := obs.PollutantStandard
+	if i := strings.LastIndexByte(standard, ' '); i > 0 {
+		standard = standard[:i]
+	}
+	p, ok := aqiStandards[standard]
+	if !ok {
+		return 0, fmt.Errorf("epa: pollutant standard %q, (%s), has no AQI", obs.PollutantStandard, obs.SampleDuration)
+	}
+
+	switch p {
+	case aqi.PM25, aqi.PM10:
+		return table.Index(p, obs.Value)
+	}
+	return table.Index(p, obs.MaxValue)
+}
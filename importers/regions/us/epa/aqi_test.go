@@ -0,0 +1,78 @@
+package epa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rsned/weather/importers/aqi"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testDailyCO         = `"06","075","0005","42101",1,37.765946,-122.399044,"WGS84","Carbon monoxide","8-HR RUN AVG END HOUR","CO 8-hour 1971","2023-01-05","Parts per million","None",24,100.0,0.329167,0.5,20,6,"","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-04-12"`
+	testDailyCOOneHr    = `"06","075","0005","42101",1,37.765946,-122.399044,"WGS84","Carbon monoxide","1 HOUR","CO 1-hour 1971","2023-01-05","Parts per million","None",24,100.0,0.35,0.9,18,,"093","INSTRUMENTAL - GAS FILTER CORRELATION","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-04-12"`
+	testDailySO2        = `"06","075","0005","42401",1,37.765946,-122.399044,"WGS84","Sulfur dioxide","1 HOUR","SO2 1-hour 2010","2023-01-05","Parts per billion","None",24,100.0,1.25,3.2,9,4,"100","INSTRUMENTAL - PULSED FLUORESCENT","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-04-12"`
+	testDailySO2ThreeHr = `"06","075","0005","42401",1,37.765946,-122.399044,"WGS84","Sulfur dioxide","3-HR BLK AVG","SO2 3-hour 1971","2023-01-05","Parts per billion","None",8,100.0,1.25,2.1,9,,"","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-04-12"`
+)
+
+func TestAQI(t *testing.T) {
+	noMax := &ds.PollutantObservation{
+		StationID:          "06-075-0005",
+		Source:             DatasetName,
+		License:            ds.LicenseUSGovernment,
+		Citation:           DatasetCitation,
+		Time:               time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC),
+		ParameterCode:      ParameterOzone,
+		ParameterName:      "Ozone",
+		POC:                1,
+		SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+		PollutantStandard:  "Ozone 8-hour 2015",
+		Units:              "Parts per million",
+		Value:              0.031529,
+		MaxValue:           ds.UnsetValue,
+		MaxTime:            time.Date(2023, 7, 1, 19, 0, 0, 0, time.UTC),
+		ObservationCount:   17,
+		ObservationPercent: 100,
+		AQI:                38,
+	}
+
+	tests := []struct {
+		name    string
+		line    string
+		table   *aqi.Table
+		want    int
+		wantErr bool
+	}{
+		{name: "hourly sample", line: testHourly, table: aqi.US2024, wantErr: true},
+		{name: "no AQI", line: testHourlyTemp, table: aqi.US2024, wantErr: true},
+		{name: "ozone", line: testDaily, table: aqi.US2024, want: 38},
+		{name: "PM2.5", line: testDailyPM25, table: aqi.US2015, want: 108},
+		{name: "CO 8-hour", line: testDailyCO, table: aqi.US2024, want: 6},
+		{name: "CO 1-hour", line: testDailyCOOneHr, table: aqi.US2024, wantErr: true},
+		{name: "SO2 1-hour", line: testDailySO2, table: aqi.US2024, want: 4},
+		{name: "SO2 3-hour", line: testDailySO2ThreeHr, table: aqi.US2024, wantErr: true},
+	}
+
+	for _, test := range tests {
+		obs, err := ParseObservationLine(test.line, nil)
+		if err != nil {
+			t.Fatalf("%s: ParseObservationLine() error = %v", test.name, err)
+		}
+		got, err := AQI(obs, test.table)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: AQI() error = %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: AQI() = %d, want %d", test.name, got, test.want)
+		}
+		if !test.wantErr && got != int(obs.AQI) {
+			t.Errorf("%s: AQI() = %d, reported AQI %v", test.name, got, obs.AQI)
+		}
+	}
+
+	if _, err := AQI(noMax, aqi.US2024); err == nil {
+		t.Errorf("AQI() with no 1st Max Value error = nil, want an error")
+	}
+}
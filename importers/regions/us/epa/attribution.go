@@ -0,0 +1,34 @@
+package epa
+
+import (
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	// DatasetName is the name used for the EPA AQS pre-generated files in
+	// Attributions and as the Source of their observations.
+	DatasetName = "EPA AQS"
+
+	// DatasetCitation names the source of the files, as the EPA does not ask
+	// for a specific citation.
+	DatasetCitation = "U.S. Environmental Protection Agency. Air Quality System (AQS) Data Mart, " +
+		"Pre-Generated Data Files."
+)
+
+// AQS data are a U.S. Government work.
+var dataset = ds.Dataset{
+	Name:     DatasetName,
+	License:  ds.LicenseUSGovernment,
+	Citation: DatasetCitation,
+}
+
+// Attribution returns the Attributions for data from the EPA AQS files.
+// Retrieved is when the files were downloaded, and is left out if it is the
+// zero time.
+//
+// The files are regenerated twice a year and are not versioned.
+func Attribution(retrieved time.Time) *ds.Attributions {
+	return dataset.Attributions(retrieved)
+}
@@ -19,5 +19,20 @@ Observations:
 files are updated twice per year: once in June to capture the complete data
 for the prior year and once in December to capture the data for the summer
 (ozone season)."
+
+The sites in aqs_sites.csv become Stations, with the AQS site ID, (the state,
+county and site number codes, e.g. "06-075-0005"), in their RegionalIDs for the
+region the site is in, US, CA or MX, (see ParseSiteLine and SiteID). Each site
+has one or more monitors in aqs_monitors.csv, and the years each parameter was
+measured at a site become its Coverage, (see Stations).
+
+The hourly_*.zip and daily_*.zip files hold one CSV each, with one row per
+sample or daily summary of one parameter by one monitor, (see Observations and
+ParseObservationLine). These are read with utils.ReadLines, which reads the
+files inside zip archives. Each row becomes a ds.PollutantObservation with the
+value in the units it was reported in, and its time in UTC. The hourly rows
+give their GMT time, and the daily rows are converted from the local date with
+the GMT Offset of their site, (see ParseSiteOffsetLine). The AQI of the daily summaries can be recomputed with AQI
+to check the values reported with them.
 */
 package epa
@@ -0,0 +1,125 @@
+package epa
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// The columns of aqs_monitors.csv used for the coverage.
+const (
+	monitorState = iota
+	monitorCounty
+	monitorSite
+	monitorParameterCode
+	monitorParameterName
+	monitorPOC
+	monitorLatitude
+	monitorLongitude
+	monitorDatum
+	monitorFirstYear
+	monitorLastSampleDate
+
+	// The monitor file has many more columns, (networks, agencies, methods
+	// and so on), which are not used.
+	monitorMinColumns
+)
+
+func init() {
+	register.DoFn2x0[string, func(string, *ds.ElementCoverage)](&MonitorParserFn{})
+	register.Emitter2[string, *ds.ElementCoverage]()
+}
+
+// MonitorParserFn is an Apache Beam structural DoFn to process rows from the
+// AQS monitor list into the element coverage keyed by AQS site ID. The header
+// row, malformed rows and monitors which never reported are skipped.
+type MonitorParserFn struct {
+}
+
+// ProcessElement reads one row in and attempts to convert it into an ElementCoverage.
+func (fn *MonitorParserFn) ProcessElement(line string, emit func(string, *ds.ElementCoverage)) {
+	id, coverage, err := ParseMonitorLine(line)
+	if err != nil {
+		return
+	}
+	emit(id, coverage)
+}
+
+// ParseMonitorLine parses one row of aqs_monitors.csv returning the AQS site
+// ID of the monitor and its coverage, with the Parameter Code as the element.
+//
+//	"State Code","County Code","Site Number","Parameter Code","Parameter Name","POC","Latitude","Longitude","Datum","First Year of Data","Last Sample Date",...
+//	"06","075","0005","88101","PM2.5 - Local Conditions","3","37.765946","-122.399044","WGS84","1999","2024-03-31",...
+//
+// A site may have several monitors for the same parameter, told apart by
+// their POC, (Parameter Occurrence Code), so the same element may be returned
+// more than once for a site.
+func ParseMonitorLine(line string) (string, *ds.ElementCoverage, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = -1
+	fields, err := r.Read()
+	if err != nil {
+		return "", nil, fmt.Errorf("epa: malformed aqs_monitors row %q: %v", line, err)
+	}
+	if len(fields) < monitorMinColumns {
+		return "", nil, fmt.Errorf("epa: aqs_monitors row has %d columns, want at least %d", len(fields), monitorMinColumns)
+	}
+	if fields[monitorState] == "State Code" {
+		return "", nil, fmt.Errorf("epa: aqs_monitors header row")
+	}
+
+	id := SiteID(fields[monitorState], fields[monitorCounty], fields[monitorSite])
+	last, err := ds.ParseDate(fields[monitorLastSampleDate])
+	if err != nil || last.IsZero() {
+		return "", nil, fmt.Errorf("epa: no Last Sample Date for %s parameter %s", id, fields[monitorParameterCode])
+	}
+	coverage := &ds.ElementCoverage{
+		Element:   strings.TrimSpace(fields[monitorParameterCode]),
+		FirstYear: int32(utils.ParseIntBounded(fields[monitorFirstYear], 1, 9999, ds.UnsetValue)),
+		LastYear:  int32(last.Year),
+	}
+
+	if len(id) != 11 || len(coverage.Element) != 5 ||
+		coverage.FirstYear == ds.UnsetValue || coverage.FirstYear > coverage.LastYear {
+		return "", nil, fmt.Errorf("epa: malformed aqs_monitors row %q", line)
+	}
+
+	return id, coverage, nil
+}
+
+// ApplyCoverage sets the stations element coverage, combining the coverage
+// of monitors for the same parameter into one spanning all of their years.
+// The stations start and end dates are left as the sites, as the monitors
+// only give years.
+func ApplyCoverage(s *ds.Station, coverage []*ds.ElementCoverage) {
+	if len(coverage) == 0 {
+		return
+	}
+
+	byElement := map[string]*ds.ElementCoverage{}
+	s.Coverage = nil
+	for _, c := range coverage {
+		have, ok := byElement[c.Element]
+		if !ok {
+			cp := *c
+			byElement[c.Element] = &cp
+			s.Coverage = append(s.Coverage, &cp)
+			continue
+		}
+		if c.FirstYear < have.FirstYear {
+			have.FirstYear = c.FirstYear
+		}
+		if c.LastYear > have.LastYear {
+			have.LastYear = c.LastYear
+		}
+	}
+	sort.Slice(s.Coverage, func(i, j int) bool {
+		return s.Coverage[i].Element < s.Coverage[j].Element
+	})
+}
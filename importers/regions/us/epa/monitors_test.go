@@ -0,0 +1,104 @@
+package epa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testMonitorsHeader   = `"State Code","County Code","Site Number","Parameter Code","Parameter Name","POC","Latitude","Longitude","Datum","First Year of Data","Last Sample Date","Monitor Type","Networks","Reporting Agency","PQAO","Collecting Agency","Exclusions","Monitoring Objective","Last Method Code","Last Method","Measurement Scale","Measurement Scale Definition","NAAQS Primary Monitor","QA Primary Monitor","Local Site Name","Address","State Name","County Name","City Name","CBSA Name","Tribe Name","Extraction Date"`
+	testMonitor          = `"06","075","0005","88101","PM2.5 - Local Conditions","3","37.765946","-122.399044","WGS84","2009","2024-03-31","SLAMS","","San Francisco Bay Area AQMD","San Francisco Bay Area AQMD","","","POPULATION EXPOSURE","209","Met One BAM-1022 Mass Monitor w/ VSCC or TE-PM2.5C - Beta Attenuation","NEIGHBORHOOD","500 M TO 4KM","Y","Y","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","","2024-05-08"`
+	testMonitorPOC1      = `"06","075","0005","88101","PM2.5 - Local Conditions","1","37.765946","-122.399044","WGS84","1999","2016-12-31","SLAMS","","San Francisco Bay Area AQMD","San Francisco Bay Area AQMD","","","POPULATION EXPOSURE","118","R & P Model 2025 PM-2.5 Sequential Air Sampler w/VSCC - Gravimetric","NEIGHBORHOOD","500 M TO 4KM","","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","","2024-05-08"`
+	testMonitorNO2       = `"06","075","0005","42602","Nitrogen dioxide (NO2)","1","37.765946","-122.399044","WGS84","1986","2024-03-31","SLAMS","","San Francisco Bay Area AQMD","San Francisco Bay Area AQMD","","","POPULATION EXPOSURE","200","Teledyne API Model T200UP - Photolytic","NEIGHBORHOOD","500 M TO 4KM","Y","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","","2024-05-08"`
+	testMonitorOtherSite = `"06","085","0005","44201","Ozone","1","37.348497","-121.894898","WGS84","2002","2024-03-31","SLAMS","","San Francisco Bay Area AQMD","San Francisco Bay Area AQMD","","","POPULATION EXPOSURE","087","INSTRUMENTAL - ULTRA VIOLET ABSORPTION","NEIGHBORHOOD","500 M TO 4KM","Y","","San Jose - Jackson","158B Jackson St","California","Santa Clara","San Jose","San Jose-Sunnyvale-Santa Clara, CA","","2024-05-08"`
+)
+
+func TestParseMonitorLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		wantID  string
+		wantCov *ds.ElementCoverage
+		wantErr bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testMonitorsHeader,
+			wantErr: true,
+		},
+		{
+			have:    `"06","075","0005","88101","PM2.5 - Local Conditions"`,
+			wantErr: true,
+		},
+		{
+			// Never reported.
+			have:    strings.Replace(testMonitor, `"2024-03-31"`, `""`, 1),
+			wantErr: true,
+		},
+		{
+			// Last sample before first year.
+			have:    strings.Replace(testMonitor, `"2009"`, `"2025"`, 1),
+			wantErr: true,
+		},
+		{
+			// Missing first year.
+			have:    strings.Replace(testMonitor, `"2009"`, `""`, 1),
+			wantErr: true,
+		},
+		// Normal cases.
+		{
+			have:    testMonitor,
+			wantID:  "06-075-0005",
+			wantCov: &ds.ElementCoverage{Element: "88101", FirstYear: 2009, LastYear: 2024},
+		},
+		{
+			have:    testMonitorPOC1,
+			wantID:  "06-075-0005",
+			wantCov: &ds.ElementCoverage{Element: "88101", FirstYear: 1999, LastYear: 2016},
+		},
+	}
+
+	for _, test := range tests {
+		id, cov, err := ParseMonitorLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseMonitorLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if id != test.wantID {
+			t.Errorf("ParseMonitorLine(%q) id = %q, want %q", test.have, id, test.wantID)
+		}
+		if diff := cmp.Diff(test.wantCov, cov); diff != "" {
+			t.Errorf("ParseMonitorLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestApplyCoverage(t *testing.T) {
+	s := ds.EmptyStation()
+	s.StartDate = ds.Date{Year: 1986, Month: 7, Day: 1}
+	s.EndDate = ds.Date{Year: 2024, Month: 5, Day: 8}
+
+	ApplyCoverage(s, []*ds.ElementCoverage{
+		{Element: "88101", FirstYear: 2009, LastYear: 2024},
+		{Element: "42602", FirstYear: 1986, LastYear: 2024},
+		{Element: "88101", FirstYear: 1999, LastYear: 2016},
+	})
+
+	want := []*ds.ElementCoverage{
+		{Element: "42602", FirstYear: 1986, LastYear: 2024},
+		{Element: "88101", FirstYear: 1999, LastYear: 2024},
+	}
+	if diff := cmp.Diff(want, s.Coverage); diff != "" {
+		t.Errorf("ApplyCoverage() diff (-want +got):\n%s", diff)
+	}
+	if s.StartDate != (ds.Date{Year: 1986, Month: 7, Day: 1}) || s.EndDate != (ds.Date{Year: 2024, Month: 5, Day: 8}) {
+		t.Errorf("ApplyCoverage() changed the dates to %v - %v", s.StartDate, s.EndDate)
+	}
+}
@@ -0,0 +1,272 @@
+package epa
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// The AQS parameter codes of the pollutants and meteorological parameters
+// which have pre-generated files.
+const (
+	ParameterOzone    = "44201"
+	ParameterSO2      = "42401"
+	ParameterCO       = "42101"
+	ParameterNO2      = "42602"
+	ParameterPM25FRM  = "88101" // PM2.5 by FRM/FEM methods.
+	ParameterPM25     = "88502" // PM2.5 by other, (non FRM/FEM), methods.
+	ParameterPM10     = "81102"
+	ParameterWindSpd  = "61101"
+	ParameterWindDir  = "61102"
+	ParameterTemp     = "62101"
+	ParameterRH       = "62201"
+	ParameterDewPoint = "62103"
+	ParameterPressure = "64101"
+)
+
+// The columns of the hourly_*.csv files.
+const (
+	hourlyState = iota
+	hourlyCounty
+	hourlySite
+	hourlyParameterCode
+	hourlyPOC
+	hourlyLatitude
+	hourlyLongitude
+	hourlyDatum
+	hourlyParameterName
+	hourlyDateLocal
+	hourlyTimeLocal
+	hourlyDateGMT
+	hourlyTimeGMT
+	hourlyMeasurement
+	hourlyUnits
+	hourlyMDL
+	hourlyUncertainty
+	hourlyQualifier
+	hourlyMethodType
+	hourlyMethodCode
+	hourlyMethodName
+	hourlyStateName
+	hourlyCountyName
+	hourlyLastChange
+
+	hourlyColumns
+)
+
+// The columns of the daily_*.csv files.
+const (
+	dailyState = iota
+	dailyCounty
+	dailySite
+	dailyParameterCode
+	dailyPOC
+	dailyLatitude
+	dailyLongitude
+	dailyDatum
+	dailyParameterName
+	dailySampleDuration
+	dailyPollutantStandard
+	dailyDateLocal
+	dailyUnits
+	dailyEventType
+	dailyObservationCount
+	dailyObservationPercent
+	dailyMean
+	dailyMaxValue
+	dailyMaxHour
+	dailyAQI
+	dailyMethodCode
+	dailyMethodName
+	dailySiteName
+	dailyAddress
+	dailyStateName
+	dailyCountyName
+	dailyCityName
+	dailyCBSAName
+	dailyLastChange
+
+	dailyColumns
+)
+
+// hourlySampleDuration is the SampleDuration of the hourly files, which do
+// not have the column as every sample is for one hour.
+const hourlySampleDuration = "1 HOUR"
+
+func init() {
+	register.DoFn2x0[string, func(*ds.PollutantObservation)](&ObservationParserFn{})
+	register.Emitter1[*ds.PollutantObservation]()
+}
+
+// Observations parses the given PCollection<string> of rows from the hourly
+// or daily files and returns a PCollection<*ds.PollutantObservation>.
+//
+// Offsets maps the AQS site IDs to the GMT Offset of their local standard
+// time, (see ParseSiteOffsetLine), and may be nil to use the timezone at each
+// rows latitude and longitude for the daily files.
+func Observations(s beam.Scope, offsets map[string]time.Duration, lines beam.PCollection) beam.PCollection {
+	s = s.Scope("epa.Observations")
+	return beam.ParDo(s, &ObservationParserFn{Offsets: offsets}, lines)
+}
+
+// ObservationParserFn is an Apache Beam structural DoFn to process rows from
+// the hourly and daily files into PollutantObservations. The header rows and
+// malformed rows are skipped.
+type ObservationParserFn struct {
+	// Offsets maps the AQS site IDs to the GMT Offset of their local
+	// standard time.
+	Offsets map[string]time.Duration
+}
+
+// ProcessElement reads one row in and attempts to convert it into a PollutantObservation.
+func (fn *ObservationParserFn) ProcessElement(line string, emit func(*ds.PollutantObservation)) {
+	if obs, err := ParseObservationLine(line, fn.Offsets); err == nil {
+		emit(obs)
+	}
+}
+
+// ParseObservationLine parses one row of an hourly_*.csv or daily_*.csv file,
+// (told apart by their number of columns), into a PollutantObservation.
+//
+// The hourly files give the time of each sample in GMT as well as local
+// standard time, so the GMT time is used. The daily files only give the local
+// date, which is converted to UTC with the sites GMT Offset from offsets, or
+// if the site is not in offsets, the timezone at the rows latitude and
+// longitude.
+func ParseObservationLine(line string, offsets map[string]time.Duration) (*ds.PollutantObservation, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = -1
+	fields, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("epa: malformed row %q: %v", line, err)
+	}
+	if fields[0] == "State Code" {
+		return nil, fmt.Errorf("epa: header row")
+	}
+
+	switch len(fields) {
+	case hourlyColumns:
+		return parseHourly(fields)
+	case dailyColumns:
+		return parseDaily(fields, offsets)
+	}
+	return nil, fmt.Errorf("epa: row has %d columns, want %d or %d", len(fields), hourlyColumns, dailyColumns)
+}
+
+// parseHourly converts the fields of one row of an hourly file.
+//
+//	"State Code","County Code","Site Num","Parameter Code","POC","Latitude","Longitude","Datum","Parameter Name","Date Local","Time Local","Date GMT","Time GMT","Sample Measurement","Units of Measure","MDL","Uncertainty","Qualifier","Method Type","Method Code","Method Name","State Name","County Name","Date of Last Change"
+//	"06","075","0005","44201",1,37.765946,-122.399044,"WGS84","Ozone","2023-07-01","13:00","2023-07-01","21:00",0.041,"Parts per million",0.005,,"","FEM","087","INSTRUMENTAL - ULTRA VIOLET ABSORPTION","California","San Francisco","2023-10-18"
+func parseHourly(fields []string) (*ds.PollutantObservation, error) {
+	obs, _, _, err := newObservation(fields[hourlyState], fields[hourlyCounty], fields[hourlySite],
+		fields[hourlyParameterCode], fields[hourlyPOC], fields[hourlyLatitude], fields[hourlyLongitude])
+	if err != nil {
+		return nil, err
+	}
+
+	obs.Time, err = time.Parse("2006-01-02 15:04", strings.TrimSpace(fields[hourlyDateGMT])+" "+strings.TrimSpace(fields[hourlyTimeGMT]))
+	if err != nil {
+		return nil, fmt.Errorf("epa: invalid GMT time for %s: %v", obs.StationID, err)
+	}
+
+	obs.Value = utils.ParseFloat(fields[hourlyMeasurement], ds.UnsetValue)
+	if obs.Value == ds.UnsetValue {
+		return nil, fmt.Errorf("epa: missing Sample Measurement for %s", obs.StationID)
+	}
+	obs.ParameterName = strings.TrimSpace(fields[hourlyParameterName])
+	obs.MethodCode = strings.TrimSpace(fields[hourlyMethodCode])
+	obs.SampleDuration = hourlySampleDuration
+	obs.Units = strings.TrimSpace(fields[hourlyUnits])
+	if q := strings.TrimSpace(fields[hourlyQualifier]); q != "" {
+		obs.Qualifiers = []string{q}
+	}
+	return obs, nil
+}
+
+// parseDaily converts the fields of one row of a daily file. Each row is a
+// summary of the samples for one day, (midnight to midnight local standard
+// time), for one sample duration and pollutant standard.
+//
+//	"State Code","County Code","Site Num","Parameter Code","POC","Latitude","Longitude","Datum","Parameter Name","Sample Duration","Pollutant Standard","Date Local","Units of Measure","Event Type","Observation Count","Observation Percent","Arithmetic Mean","1st Max Value","1st Max Hour","AQI","Method Code","Method Name","Local Site Name","Address","State Name","County Name","City Name","CBSA Name","Date of Last Change"
+//	"06","075","0005","44201",1,37.765946,-122.399044,"WGS84","Ozone","8-HR RUN AVG BEGIN HOUR","Ozone 8-hour 2015","2023-07-01","Parts per million","None",17,100.0,0.031529,0.041,11,38,"","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-10-18"
+//
+// The Event Type, whether samples affected by exceptional events such as
+// wildfires are "Included" or "Excluded", is kept in the Qualifiers when
+// there were any.
+func parseDaily(fields []string, offsets map[string]time.Duration) (*ds.PollutantObservation, error) {
+	obs, lat, lng, err := newObservation(fields[dailyState], fields[dailyCounty], fields[dailySite],
+		fields[dailyParameterCode], fields[dailyPOC], fields[dailyLatitude], fields[dailyLongitude])
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := time.Parse(ds.DateLayout, strings.TrimSpace(fields[dailyDateLocal]))
+	if err != nil {
+		return nil, fmt.Errorf("epa: invalid Date Local for %s: %v", obs.StationID, err)
+	}
+	if offset, ok := offsets[obs.StationID]; ok {
+		obs.Time = local.Add(-offset)
+	} else if obs.Time, err = geography.LocalStandardToUTC(local, geography.Timezone(lat, lng)); err != nil {
+		return nil, err
+	}
+	if hour := utils.ParseIntBounded(fields[dailyMaxHour], 0, 23, ds.UnsetValue); hour != ds.UnsetValue {
+		obs.MaxTime = obs.Time.Add(time.Duration(hour) * time.Hour)
+	}
+
+	obs.Value = utils.ParseFloat(fields[dailyMean], ds.UnsetValue)
+	if obs.Value == ds.UnsetValue {
+		return nil, fmt.Errorf("epa: missing Arithmetic Mean for %s", obs.StationID)
+	}
+	obs.MaxValue = utils.ParseFloat(fields[dailyMaxValue], ds.UnsetValue)
+	obs.ObservationCount = int32(utils.ParseIntBounded(fields[dailyObservationCount], 0, 1440, ds.UnsetValue))
+	obs.ObservationPercent = utils.ParseFloatBounded(fields[dailyObservationPercent], 0, 100, ds.UnsetValue)
+	obs.AQI = utils.ParseFloatBounded(fields[dailyAQI], 0, 999, ds.UnsetValue)
+
+	obs.ParameterName = strings.TrimSpace(fields[dailyParameterName])
+	obs.MethodCode = strings.TrimSpace(fields[dailyMethodCode])
+	obs.SampleDuration = strings.TrimSpace(fields[dailySampleDuration])
+	obs.PollutantStandard = strings.TrimSpace(fields[dailyPollutantStandard])
+	obs.Units = strings.TrimSpace(fields[dailyUnits])
+	if event := strings.TrimSpace(fields[dailyEventType]); event != "" && event != "None" {
+		obs.Qualifiers = []string{event}
+	}
+	return obs, nil
+}
+
+// newObservation returns a PollutantObservation with the fields common to the
+// hourly and daily files set, and the latitude and longitude of the site.
+func newObservation(state, county, site, parameter, poc, lat, lng string) (*ds.PollutantObservation, float64, float64, error) {
+	id := SiteID(state, county, site)
+	if len(id) != 11 {
+		return nil, 0, 0, fmt.Errorf("epa: invalid site ID %q", id)
+	}
+
+	obs := ds.EmptyPollutantObservation()
+	obs.StationID = id
+	obs.Source = DatasetName
+	obs.License = dataset.License
+	obs.Citation = dataset.Citation
+	obs.ParameterCode = strings.TrimSpace(parameter)
+	if len(obs.ParameterCode) != 5 {
+		return nil, 0, 0, fmt.Errorf("epa: invalid Parameter Code %q for %s", parameter, id)
+	}
+	obs.POC = int32(utils.ParseIntBounded(poc, 1, 99, ds.UnsetValue))
+	if obs.POC == ds.UnsetValue {
+		return nil, 0, 0, fmt.Errorf("epa: invalid POC %q for %s", poc, id)
+	}
+
+	latitude := utils.ParseFloatBounded(lat, -90, 90, ds.UnsetValue)
+	longitude := utils.ParseFloatBounded(lng, -180, 180, ds.UnsetValue)
+	if latitude == ds.UnsetValue || longitude == ds.UnsetValue {
+		return nil, 0, 0, fmt.Errorf("epa: invalid location %q, %q for %s", lat, lng, id)
+	}
+	return obs, latitude, longitude, nil
+}
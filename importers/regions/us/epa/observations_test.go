@@ -0,0 +1,342 @@
+package epa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testHourlyHeader = `"State Code","County Code","Site Num","Parameter Code","POC","Latitude","Longitude","Datum","Parameter Name","Date Local","Time Local","Date GMT","Time GMT","Sample Measurement","Units of Measure","MDL","Uncertainty","Qualifier","Method Type","Method Code","Method Name","State Name","County Name","Date of Last Change"`
+	testHourly       = `"06","075","0005","44201",1,37.765946,-122.399044,"WGS84","Ozone","2023-07-01","13:00","2023-07-01","21:00",0.041,"Parts per million",0.005,,"","FEM","087","INSTRUMENTAL - ULTRA VIOLET ABSORPTION","California","San Francisco","2023-10-18"`
+	testHourlyCanada = `"CC","040","0207","42602",1,42.292889,-83.073139,"WGS84","Nitrogen dioxide (NO2)","2023-07-01","13:00","2023-07-01","18:00",9.1,"Parts per billion",,,"","FEM","099","INSTRUMENTAL - CHEMILUMINESCENCE","Country Of Canada","Windsor","2023-10-18"`
+	testHourlyTemp   = `"06","075","0005","62101",1,37.765946,-122.399044,"WGS84","Outdoor Temperature","2023-01-01","00:00","2023-01-01","08:00",52.3,"Degrees Fahrenheit",,,"V","Non-FRM","020","Instrumental - Elec. Resist. Therm.","California","San Francisco","2023-04-12"`
+
+	testDailyHeader = `"State Code","County Code","Site Num","Parameter Code","POC","Latitude","Longitude","Datum","Parameter Name","Sample Duration","Pollutant Standard","Date Local","Units of Measure","Event Type","Observation Count","Observation Percent","Arithmetic Mean","1st Max Value","1st Max Hour","AQI","Method Code","Method Name","Local Site Name","Address","State Name","County Name","City Name","CBSA Name","Date of Last Change"`
+	testDaily       = `"06","075","0005","44201",1,37.765946,-122.399044,"WGS84","Ozone","8-HR RUN AVG BEGIN HOUR","Ozone 8-hour 2015","2023-07-01","Parts per million","None",17,100.0,0.031529,0.041,11,38,"","","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2023-10-18"`
+	testDailyCanada = `"CC","040","0207","42602",1,42.292889,-83.073139,"WGS84","Nitrogen dioxide (NO2)","1 HOUR","NO2 1-hour 2010","2023-07-01","Parts per billion","None",24,100.0,9.1,17.5,6,16,"","","Windsor West","College Ave.","Country Of Canada","Windsor","Not in a city","","2023-10-18"`
+	testDailyPM25   = `"06","075","0005","88101",3,37.765946,-122.399044,"WGS84","PM2.5 - Local Conditions","1 HOUR","PM25 24-hour 2012","2020-09-09","Micrograms/cubic meter (LC)","Included",24,100.0,38.5,61.0,10,108,"209","Met One BAM-1022","San Francisco","10 Arkansas St.","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","2021-03-02"`
+)
+
+func TestParseObservationLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		offsets map[string]time.Duration
+		want    *ds.PollutantObservation
+		wantErr bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testHourlyHeader,
+			wantErr: true,
+		},
+		{
+			have:    testDailyHeader,
+			wantErr: true,
+		},
+		{
+			have:    `"06","075","0005","44201",1,37.765946,-122.399044`,
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testHourly, `"0005"`, `"5"`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testHourly, `"44201",1`, `"44201",`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testHourly, `37.765946,-122.399044`, `,`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testHourly, `"21:00"`, `"9 PM"`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testHourly, `0.041,`, `,`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testDaily, `"2023-07-01"`, `"07/01/2023"`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testDaily, `0.031529`, ``, 1),
+			wantErr: true,
+		},
+		// Normal cases.
+		{
+			have: testHourly,
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 21, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterOzone,
+				ParameterName:      "Ozone",
+				POC:                1,
+				MethodCode:         "087",
+				SampleDuration:     "1 HOUR",
+				Units:              "Parts per million",
+				Value:              0.041,
+				MaxValue:           ds.UnsetValue,
+				ObservationCount:   ds.UnsetValue,
+				ObservationPercent: ds.UnsetValue,
+				AQI:                ds.UnsetValue,
+			},
+		},
+		{
+			have: testHourlyTemp,
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterTemp,
+				ParameterName:      "Outdoor Temperature",
+				POC:                1,
+				MethodCode:         "020",
+				SampleDuration:     "1 HOUR",
+				Units:              "Degrees Fahrenheit",
+				Value:              52.3,
+				MaxValue:           ds.UnsetValue,
+				ObservationCount:   ds.UnsetValue,
+				ObservationPercent: ds.UnsetValue,
+				AQI:                ds.UnsetValue,
+				Qualifiers:         []string{"V"},
+			},
+		},
+		{
+			have: testHourlyCanada,
+			want: &ds.PollutantObservation{
+				StationID:          "CC-040-0207",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 18, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterNO2,
+				ParameterName:      "Nitrogen dioxide (NO2)",
+				POC:                1,
+				MethodCode:         "099",
+				SampleDuration:     "1 HOUR",
+				Units:              "Parts per billion",
+				Value:              9.1,
+				MaxValue:           ds.UnsetValue,
+				ObservationCount:   ds.UnsetValue,
+				ObservationPercent: ds.UnsetValue,
+				AQI:                ds.UnsetValue,
+			},
+		},
+		{
+			have: testDaily,
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterOzone,
+				ParameterName:      "Ozone",
+				POC:                1,
+				SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+				PollutantStandard:  "Ozone 8-hour 2015",
+				Units:              "Parts per million",
+				Value:              0.031529,
+				MaxValue:           0.041,
+				MaxTime:            time.Date(2023, 7, 1, 19, 0, 0, 0, time.UTC),
+				ObservationCount:   17,
+				ObservationPercent: 100,
+				AQI:                38,
+			},
+		},
+		{
+			have: testDailyPM25,
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2020, 9, 9, 8, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterPM25FRM,
+				ParameterName:      "PM2.5 - Local Conditions",
+				POC:                3,
+				MethodCode:         "209",
+				SampleDuration:     "1 HOUR",
+				PollutantStandard:  "PM25 24-hour 2012",
+				Units:              "Micrograms/cubic meter (LC)",
+				Value:              38.5,
+				MaxValue:           61,
+				MaxTime:            time.Date(2020, 9, 9, 18, 0, 0, 0, time.UTC),
+				ObservationCount:   24,
+				ObservationPercent: 100,
+				AQI:                108,
+				Qualifiers:         []string{"Included"},
+			},
+		},
+		{
+			have:    testDailyCanada,
+			offsets: map[string]time.Duration{"CC-040-0207": -5 * time.Hour},
+			want: &ds.PollutantObservation{
+				StationID:          "CC-040-0207",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 5, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterNO2,
+				ParameterName:      "Nitrogen dioxide (NO2)",
+				POC:                1,
+				SampleDuration:     "1 HOUR",
+				PollutantStandard:  "NO2 1-hour 2010",
+				Units:              "Parts per billion",
+				Value:              9.1,
+				MaxValue:           17.5,
+				MaxTime:            time.Date(2023, 7, 1, 11, 0, 0, 0, time.UTC),
+				ObservationCount:   24,
+				ObservationPercent: 100,
+				AQI:                16,
+			},
+		},
+		{
+			// The site's GMT Offset is used over the timezone at its location.
+			have:    testDaily,
+			offsets: map[string]time.Duration{"06-075-0005": -7 * time.Hour},
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 7, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterOzone,
+				ParameterName:      "Ozone",
+				POC:                1,
+				SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+				PollutantStandard:  "Ozone 8-hour 2015",
+				Units:              "Parts per million",
+				Value:              0.031529,
+				MaxValue:           0.041,
+				MaxTime:            time.Date(2023, 7, 1, 18, 0, 0, 0, time.UTC),
+				ObservationCount:   17,
+				ObservationPercent: 100,
+				AQI:                38,
+			},
+		},
+		{
+			// No 1st Max Hour or AQI, as for the parameters without one.
+			have: strings.Replace(testDaily, `0.041,11,38`, `0.041,,`, 1),
+			want: &ds.PollutantObservation{
+				StationID:          "06-075-0005",
+				Source:             DatasetName,
+				License:            ds.LicenseUSGovernment,
+				Citation:           DatasetCitation,
+				Time:               time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC),
+				ParameterCode:      ParameterOzone,
+				ParameterName:      "Ozone",
+				POC:                1,
+				SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+				PollutantStandard:  "Ozone 8-hour 2015",
+				Units:              "Parts per million",
+				Value:              0.031529,
+				MaxValue:           0.041,
+				ObservationCount:   17,
+				ObservationPercent: 100,
+				AQI:                ds.UnsetValue,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseObservationLine(test.have, test.offsets)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseObservationLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseObservationLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestObservations(t *testing.T) {
+	beam.Init()
+	p, s := beam.NewPipelineWithRoot()
+
+	offsets := map[string]time.Duration{"06-075-0005": -8 * time.Hour, "CC-040-0207": -5 * time.Hour}
+	lines := beam.Create(s, testHourlyHeader, testHourly, testDailyHeader, testDaily,
+		strings.Replace(testDaily, `"06","075","0005"`, `"CC","040","0207"`, 1), "not a row")
+	passert.Equals(s, Observations(s, offsets, lines),
+		&ds.PollutantObservation{
+			StationID:          "06-075-0005",
+			Source:             DatasetName,
+			License:            ds.LicenseUSGovernment,
+			Citation:           DatasetCitation,
+			Time:               time.Date(2023, 7, 1, 21, 0, 0, 0, time.UTC),
+			ParameterCode:      ParameterOzone,
+			ParameterName:      "Ozone",
+			POC:                1,
+			MethodCode:         "087",
+			SampleDuration:     "1 HOUR",
+			Units:              "Parts per million",
+			Value:              0.041,
+			MaxValue:           ds.UnsetValue,
+			ObservationCount:   ds.UnsetValue,
+			ObservationPercent: ds.UnsetValue,
+			AQI:                ds.UnsetValue,
+		},
+		&ds.PollutantObservation{
+			StationID:          "06-075-0005",
+			Source:             DatasetName,
+			License:            ds.LicenseUSGovernment,
+			Citation:           DatasetCitation,
+			Time:               time.Date(2023, 7, 1, 8, 0, 0, 0, time.UTC),
+			ParameterCode:      ParameterOzone,
+			ParameterName:      "Ozone",
+			POC:                1,
+			SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+			PollutantStandard:  "Ozone 8-hour 2015",
+			Units:              "Parts per million",
+			Value:              0.031529,
+			MaxValue:           0.041,
+			MaxTime:            time.Date(2023, 7, 1, 19, 0, 0, 0, time.UTC),
+			ObservationCount:   17,
+			ObservationPercent: 100,
+			AQI:                38,
+		},
+		&ds.PollutantObservation{
+			StationID:          "CC-040-0207",
+			Source:             DatasetName,
+			License:            ds.LicenseUSGovernment,
+			Citation:           DatasetCitation,
+			Time:               time.Date(2023, 7, 1, 5, 0, 0, 0, time.UTC),
+			ParameterCode:      ParameterOzone,
+			ParameterName:      "Ozone",
+			POC:                1,
+			SampleDuration:     "8-HR RUN AVG BEGIN HOUR",
+			PollutantStandard:  "Ozone 8-hour 2015",
+			Units:              "Parts per million",
+			Value:              0.031529,
+			MaxValue:           0.041,
+			MaxTime:            time.Date(2023, 7, 1, 16, 0, 0, 0, time.UTC),
+			ObservationCount:   17,
+			ObservationPercent: 100,
+			AQI:                38,
+		})
+
+	if err := ptest.Run(p); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+}
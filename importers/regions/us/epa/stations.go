@@ -0,0 +1,248 @@
+package epa
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// SourceName identifies the EPA AQS sites when merging them with other sources.
+const SourceName = "epa"
+
+// RegionalIDPrefix is put before the AQS site ID in the stations RegionalIDs
+// for the region of the site, e.g. "EPA:06-075-0005" for the US, or
+// "EPA:CC-040-0207" for Canada.
+const RegionalIDPrefix = "EPA:"
+
+// The columns of aqs_sites.csv.
+const (
+	siteState = iota
+	siteCounty
+	siteNumber
+	siteLatitude
+	siteLongitude
+	siteDatum
+	siteElevation
+	siteLandUse
+	siteLocationSetting
+	siteEstablished
+	siteClosed
+	siteMetState
+	siteMetCounty
+	siteMetNumber
+	siteMetType
+	siteMetDistance
+	siteMetDirection
+	siteGMTOffset
+	siteOwningAgency
+	siteName
+	siteAddress
+	siteZipCode
+	siteStateName
+	siteCountyName
+	siteCityName
+	siteCBSAName
+	siteTribeName
+	siteExtractionDate
+
+	siteColumns
+)
+
+// notInACity is the City Name given for sites outside of any city.
+const notInACity = "Not in a city"
+
+func init() {
+	register.DoFn2x0[string, func(*ds.Station)](&StationParserFn{})
+	register.Function1x2(stationKeyFn)
+	register.Function4x0(applyCoverageFn)
+	register.Iter1[*ds.Station]()
+	register.Iter1[*ds.ElementCoverage]()
+	register.Emitter1[*ds.Station]()
+}
+
+// SiteID returns the AQS site ID from its state, county and site number
+// codes, e.g. "06-075-0005". This is how AQS identifies a site across all of
+// its files.
+func SiteID(state, county, site string) string {
+	return strings.TrimSpace(state) + "-" + strings.TrimSpace(county) + "-" + strings.TrimSpace(site)
+}
+
+// Stations returns a PCollection<*ds.Station> with one Station for each row
+// in the given PCollection<string> of aqs_sites.csv rows, with the Coverage of
+// each station set from the given PCollection<string> of aqs_monitors.csv
+// rows.
+func Stations(s beam.Scope, retrieved time.Time, sites, monitors beam.PCollection) beam.PCollection {
+	s = s.Scope("epa.Stations")
+	stations := beam.ParDo(s, stationKeyFn, beam.ParDo(s, &StationParserFn{Retrieved: retrieved}, sites))
+	coverage := beam.ParDo(s, &MonitorParserFn{}, monitors)
+	return beam.ParDo(s, applyCoverageFn, beam.CoGroupByKey(s, stations, coverage))
+}
+
+// StationParserFn is an Apache Beam structural DoFn to process rows from the
+// AQS site list, aqs_sites.csv, into Stations. The header row and malformed
+// rows are skipped.
+type StationParserFn struct {
+	// Retrieved is when the site file was downloaded, for the stations
+	// Attributions. It is left out if it is the zero time.
+	Retrieved time.Time
+}
+
+// ProcessElement reads one row in and attempts to convert it into a Station.
+func (fn *StationParserFn) ProcessElement(line string, emit func(*ds.Station)) {
+	station, err := ParseSiteLine(line)
+	if err != nil {
+		return
+	}
+	station.Attributions = Attribution(fn.Retrieved)
+	emit(station)
+}
+
+// ParseSiteLine parses one row of aqs_sites.csv into a Station.
+//
+//	"State Code","County Code","Site Number","Latitude","Longitude","Datum","Elevation","Land Use","Location Setting","Site Established Date","Site Closed Date",...,"Local Site Name","Address","Zip Code","State Name","County Name","City Name","CBSA Name","Tribe Name","Extraction Date"
+//	"06","075","0005","37.765946","-122.399044","WGS84","18","COMMERCIAL","URBAN AND CENTER CITY","1986-07-01","",...,"San Francisco","10 Arkansas St.","94107","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","","2024-05-08"
+//
+// The State Code is the FIPS 5-2 numeric state code for US sites, "CC" for
+// sites in Canada and "80" for sites in Mexico. The Elevation is in meters.
+// Sites still open have no Site Closed Date, so their EndDate is the
+// Extraction Date of the file.
+func ParseSiteLine(line string) (*ds.Station, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = siteColumns
+	fields, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("epa: malformed aqs_sites row %q: %v", line, err)
+	}
+	if fields[siteState] == "State Code" {
+		return nil, fmt.Errorf("epa: aqs_sites header row")
+	}
+
+	id := SiteID(fields[siteState], fields[siteCounty], fields[siteNumber])
+	if len(id) != 11 {
+		return nil, fmt.Errorf("epa: invalid site ID %q", id)
+	}
+
+	station := ds.EmptyStation()
+	station.Name = strings.TrimSpace(fields[siteName])
+
+	state := strings.TrimSpace(fields[siteState])
+	region := "US"
+	switch state {
+	case "CC":
+		region = "CA"
+	case "80":
+		region = "MX"
+	}
+	station.Identifiers.AddRegionalID(region, RegionalIDPrefix+id)
+
+	station.StartDate, err = ds.ParseDate(fields[siteEstablished])
+	if err != nil {
+		return nil, fmt.Errorf("epa: invalid Site Established Date for %s: %v", id, err)
+	}
+	closed := fields[siteClosed]
+	if strings.TrimSpace(closed) == "" {
+		closed = fields[siteExtractionDate]
+	}
+	station.EndDate, err = ds.ParseDate(closed)
+	if err != nil {
+		return nil, fmt.Errorf("epa: invalid Site Closed Date for %s: %v", id, err)
+	}
+
+	// A few sites have no known location, and are given as 0, 0.
+	g := station.Geography
+	if lat, lng := utils.ParseFloat(fields[siteLatitude], 0), utils.ParseFloat(fields[siteLongitude], 0); lat != 0 || lng != 0 {
+		g.Lat = float32(lat)
+		g.Lng = float32(lng)
+		if datum := strings.TrimSpace(fields[siteDatum]); datum != "UNKNOWN" {
+			g.Datum = datum
+		}
+		geography.Normalize(g)
+	}
+
+	g.ElevationMeters = ds.UnsetValue
+	if elev := strings.TrimSpace(fields[siteElevation]); elev != "" {
+		g.ElevationMeters = int32(utils.ParseFloat(elev, ds.UnsetValue))
+	}
+	g.LandUse = strings.TrimSpace(fields[siteLandUse])
+	g.LocationSetting = strings.TrimSpace(fields[siteLocationSetting])
+
+	g.StreetAddress = strings.TrimSpace(fields[siteAddress])
+	g.PostalCode = strings.TrimSpace(fields[siteZipCode])
+	g.Subdivision2Name = strings.TrimSpace(fields[siteCountyName])
+	if city := strings.TrimSpace(fields[siteCityName]); city != notInACity {
+		g.Locality = city
+	}
+
+	if r, ok := geography.RegionForCode(region); ok {
+		r.Apply(g)
+	}
+	if sub, ok := geography.SubdivisionFor(region, state); ok {
+		sub.Apply(g)
+	}
+
+	return station, nil
+}
+
+// ParseSiteOffsetLine parses the AQS site ID and the GMT Offset of its local
+// standard time from one row of aqs_sites.csv, for Observations. The offset
+// is given in hours, e.g. "-8" for US Pacific sites.
+func ParseSiteOffsetLine(line string) (string, time.Duration, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = siteColumns
+	fields, err := r.Read()
+	if err != nil {
+		return "", 0, fmt.Errorf("epa: malformed aqs_sites row %q: %v", line, err)
+	}
+
+	id := SiteID(fields[siteState], fields[siteCounty], fields[siteNumber])
+	hours := utils.ParseFloatBounded(fields[siteGMTOffset], -12, 14, ds.UnsetValue)
+	if hours == ds.UnsetValue {
+		return "", 0, fmt.Errorf("epa: invalid GMT Offset %q for %s", fields[siteGMTOffset], id)
+	}
+	return id, time.Duration(hours * float64(time.Hour)), nil
+}
+
+// stationKeyFn keys the station by its AQS site ID.
+func stationKeyFn(s *ds.Station) (string, *ds.Station) {
+	return stationSiteID(s), s
+}
+
+// stationSiteID returns the AQS site ID of the station from its RegionalIDs,
+// for whichever region the site is in, or "" if it has none.
+func stationSiteID(s *ds.Station) string {
+	if s.Identifiers == nil {
+		return ""
+	}
+	for region := range s.Identifiers.RegionalIDs {
+		for _, id := range s.Identifiers.RegionalIDsIn(region) {
+			if site, ok := strings.CutPrefix(id, RegionalIDPrefix); ok {
+				return site
+			}
+		}
+	}
+	return ""
+}
+
+// applyCoverageFn adds the coverage from the monitors at a site onto its
+// Station.
+func applyCoverageFn(_ string, stations func(**ds.Station) bool, coverage func(**ds.ElementCoverage) bool, emit func(*ds.Station)) {
+	var cov []*ds.ElementCoverage
+	var c *ds.ElementCoverage
+	for coverage(&c) {
+		cov = append(cov, c)
+	}
+
+	var s *ds.Station
+	for stations(&s) {
+		ApplyCoverage(s, cov)
+		emit(s)
+	}
+}
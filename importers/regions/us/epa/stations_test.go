@@ -0,0 +1,357 @@
+package epa
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testSitesHeader = `"State Code","County Code","Site Number","Latitude","Longitude","Datum","Elevation","Land Use","Location Setting","Site Established Date","Site Closed Date","Met Site State Code","Met Site County Code","Met Site Site Number","Met Site Type","Met Site Distance","Met Site Direction","GMT Offset","Owning Agency","Local Site Name","Address","Zip Code","State Name","County Name","City Name","CBSA Name","Tribe Name","Extraction Date"`
+	testSite        = `"06","075","0005","37.765946","-122.399044","WGS84","18","COMMERCIAL","URBAN AND CENTER CITY","1986-07-01","","","","","","","","-8","Bay Area AQMD","San Francisco","10 Arkansas St.","94107","California","San Francisco","San Francisco","San Francisco-Oakland-Hayward, CA","","2024-05-08"`
+	testClosedSite  = `"06","001","0003","37.8","-122.2","NAD27","","RESIDENTIAL","SUBURBAN","1967-01-01","1980-12-31","","","","","","","-8","","","","","California","Alameda","Not in a city","","","2024-05-08"`
+)
+
+func TestParseSiteLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *ds.Station
+		wantErr bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testSitesHeader,
+			wantErr: true,
+		},
+		{
+			have:    `"06","075","0005","37.765946"`,
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testSite, `"0005"`, `"5"`, 1),
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testSite, `"1986-07-01"`, `"July 1986"`, 1),
+			wantErr: true,
+		},
+		// Normal cases.
+		{
+			have: testSite,
+			want: &ds.Station{
+				Name: "San Francisco",
+				Identifiers: &ds.Identifiers{
+					RegionalIDs: map[string]string{"US": "EPA:06-075-0005"},
+				},
+				Geography: &ds.Geography{
+					Continent:        "North America",
+					MetaRegion:       "NA",
+					RegionCode:       "US",
+					RegionName:       "United States",
+					Subdivision1Code: "US-CA",
+					Subdivision1Name: "California",
+					Subdivision2Name: "San Francisco",
+					Locality:         "San Francisco",
+					PostalCode:       "94107",
+					StreetAddress:    "10 Arkansas St.",
+					Lat:              37.765946,
+					Lng:              -122.399044,
+					LatE7:            377659450,
+					LngE7:            -1223990400,
+					Datum:            "WGS84",
+					ElevationMeters:  18,
+					LandUse:          "COMMERCIAL",
+					LocationSetting:  "URBAN AND CENTER CITY",
+					S2CellID:         0x808f7fccfa40d8d5,
+					GeoHash:          "9q8yyepdxdkv",
+					PlusCode:         "849VQJ82+99",
+					H3Cell:           0x8f283082e330ca2,
+					Timezone:         "America/Los_Angeles",
+				},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 1986, Month: 7, Day: 1},
+				EndDate:      ds.Date{Year: 2024, Month: 5, Day: 8},
+			},
+		},
+		{
+			have: testClosedSite,
+			want: &ds.Station{
+				Identifiers: &ds.Identifiers{
+					RegionalIDs: map[string]string{"US": "EPA:06-001-0003"},
+				},
+				Geography: &ds.Geography{
+					Continent:        "North America",
+					MetaRegion:       "NA",
+					RegionCode:       "US",
+					RegionName:       "United States",
+					Subdivision1Code: "US-CA",
+					Subdivision1Name: "California",
+					Subdivision2Name: "Alameda",
+					Lat:              37.8,
+					Lng:              -122.2,
+					LatE7:            378000000,
+					LngE7:            -1222000000,
+					Datum:            "NAD27",
+					ElevationMeters:  ds.UnsetValue,
+					LandUse:          "RESIDENTIAL",
+					LocationSetting:  "SUBURBAN",
+					S2CellID:         0x808f864adfce2ebb,
+					GeoHash:          "9q9p5328vwez",
+					PlusCode:         "849VRR22+22",
+					H3Cell:           0x8f28308a4152b91,
+					Timezone:         "America/Los_Angeles",
+				},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 1967, Month: 1, Day: 1},
+				EndDate:      ds.Date{Year: 1980, Month: 12, Day: 31},
+			},
+		},
+		{
+			have: strings.Replace(strings.Replace(testSite, `"06","075"`, `"CC","040"`, 1), `"California"`, `"Country Of Canada"`, 1),
+			want: &ds.Station{
+				Name: "San Francisco",
+				Identifiers: &ds.Identifiers{
+					RegionalIDs: map[string]string{"CA": "EPA:CC-040-0005"},
+				},
+				Geography: &ds.Geography{
+					Continent:        "North America",
+					MetaRegion:       "NA",
+					RegionCode:       "CA",
+					RegionName:       "Canada",
+					Subdivision2Name: "San Francisco",
+					Locality:         "San Francisco",
+					PostalCode:       "94107",
+					StreetAddress:    "10 Arkansas St.",
+					Lat:              37.765946,
+					Lng:              -122.399044,
+					LatE7:            377659450,
+					LngE7:            -1223990400,
+					Datum:            "WGS84",
+					ElevationMeters:  18,
+					LandUse:          "COMMERCIAL",
+					LocationSetting:  "URBAN AND CENTER CITY",
+					S2CellID:         0x808f7fccfa40d8d5,
+					GeoHash:          "9q8yyepdxdkv",
+					PlusCode:         "849VQJ82+99",
+					H3Cell:           0x8f283082e330ca2,
+					Timezone:         "America/Los_Angeles",
+				},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 1986, Month: 7, Day: 1},
+				EndDate:      ds.Date{Year: 2024, Month: 5, Day: 8},
+			},
+		},
+		{
+			// No known location.
+			have: strings.Replace(testSite, `"37.765946","-122.399044","WGS84"`, `"0","0","UNKNOWN"`, 1),
+			want: &ds.Station{
+				Name: "San Francisco",
+				Identifiers: &ds.Identifiers{
+					RegionalIDs: map[string]string{"US": "EPA:06-075-0005"},
+				},
+				Geography: &ds.Geography{
+					Continent:        "North America",
+					MetaRegion:       "NA",
+					RegionCode:       "US",
+					RegionName:       "United States",
+					Subdivision1Code: "US-CA",
+					Subdivision1Name: "California",
+					Subdivision2Name: "San Francisco",
+					Locality:         "San Francisco",
+					PostalCode:       "94107",
+					StreetAddress:    "10 Arkansas St.",
+					ElevationMeters:  18,
+					LandUse:          "COMMERCIAL",
+					LocationSetting:  "URBAN AND CENTER CITY",
+				},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 1986, Month: 7, Day: 1},
+				EndDate:      ds.Date{Year: 2024, Month: 5, Day: 8},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseSiteLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseSiteLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseSiteLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestParseSiteOffsetLine(t *testing.T) {
+	tests := []struct {
+		have       string
+		wantID     string
+		wantOffset time.Duration
+		wantErr    bool
+	}{
+		// Bad input strings.
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testSitesHeader,
+			wantErr: true,
+		},
+		{
+			have:    strings.Replace(testSite, `"-8"`, `""`, 1),
+			wantErr: true,
+		},
+		// Normal cases.
+		{
+			have:       testSite,
+			wantID:     "06-075-0005",
+			wantOffset: -8 * time.Hour,
+		},
+		{
+			have:       strings.Replace(strings.Replace(testSite, `"06","075"`, `"CC","001"`, 1), `"-8"`, `"-3.5"`, 1),
+			wantID:     "CC-001-0005",
+			wantOffset: -3*time.Hour - 30*time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		id, offset, err := ParseSiteOffsetLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseSiteOffsetLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if id != test.wantID || offset != test.wantOffset {
+			t.Errorf("ParseSiteOffsetLine(%q) = %q, %v, want %q, %v", test.have, id, offset, test.wantID, test.wantOffset)
+		}
+	}
+}
+
+func TestStations(t *testing.T) {
+	beam.Init()
+	retrieved := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	want := &ds.Station{
+		Name: "San Francisco",
+		Identifiers: &ds.Identifiers{
+			RegionalIDs: map[string]string{"US": "EPA:06-075-0005"},
+		},
+		Geography: &ds.Geography{
+			Continent:        "North America",
+			MetaRegion:       "NA",
+			RegionCode:       "US",
+			RegionName:       "United States",
+			Subdivision1Code: "US-CA",
+			Subdivision1Name: "California",
+			Subdivision2Name: "San Francisco",
+			Locality:         "San Francisco",
+			PostalCode:       "94107",
+			StreetAddress:    "10 Arkansas St.",
+			Lat:              37.765946,
+			Lng:              -122.399044,
+			LatE7:            377659450,
+			LngE7:            -1223990400,
+			Datum:            "WGS84",
+			ElevationMeters:  18,
+			LandUse:          "COMMERCIAL",
+			LocationSetting:  "URBAN AND CENTER CITY",
+			S2CellID:         0x808f7fccfa40d8d5,
+			GeoHash:          "9q8yyepdxdkv",
+			PlusCode:         "849VQJ82+99",
+			H3Cell:           0x8f283082e330ca2,
+			Timezone:         "America/Los_Angeles",
+		},
+		Attributions: Attribution(retrieved),
+		Coverage: []*ds.ElementCoverage{
+			{Element: "42602", FirstYear: 1986, LastYear: 2024},
+			{Element: "88101", FirstYear: 1999, LastYear: 2024},
+		},
+		StartDate: ds.Date{Year: 1986, Month: 7, Day: 1},
+		EndDate:   ds.Date{Year: 2024, Month: 5, Day: 8},
+	}
+	wantClosed := &ds.Station{
+		Identifiers: &ds.Identifiers{
+			RegionalIDs: map[string]string{"US": "EPA:06-001-0003"},
+		},
+		Geography: &ds.Geography{
+			Continent:        "North America",
+			MetaRegion:       "NA",
+			RegionCode:       "US",
+			RegionName:       "United States",
+			Subdivision1Code: "US-CA",
+			Subdivision1Name: "California",
+			Subdivision2Name: "Alameda",
+			Lat:              37.8,
+			Lng:              -122.2,
+			LatE7:            378000000,
+			LngE7:            -1222000000,
+			Datum:            "NAD27",
+			ElevationMeters:  ds.UnsetValue,
+			LandUse:          "RESIDENTIAL",
+			LocationSetting:  "SUBURBAN",
+			S2CellID:         0x808f864adfce2ebb,
+			GeoHash:          "9q9p5328vwez",
+			PlusCode:         "849VRR22+22",
+			H3Cell:           0x8f28308a4152b91,
+			Timezone:         "America/Los_Angeles",
+		},
+		Attributions: Attribution(retrieved),
+		StartDate:    ds.Date{Year: 1967, Month: 1, Day: 1},
+		EndDate:      ds.Date{Year: 1980, Month: 12, Day: 31},
+	}
+
+	p, s := beam.NewPipelineWithRoot()
+	sites := beam.Create(s, testSitesHeader, testSite, testClosedSite)
+	monitors := beam.Create(s, testMonitorsHeader, testMonitor, testMonitorPOC1, testMonitorNO2, testMonitorOtherSite)
+	passert.Equals(s, Stations(s, retrieved, sites, monitors), want, wantClosed)
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("Stations failed: %v", err)
+	}
+}
+
+func TestStationKey(t *testing.T) {
+	tests := []struct {
+		have *ds.Station
+		want string
+	}{
+		{
+			have: &ds.Station{Identifiers: &ds.Identifiers{
+				RegionalIDs: map[string]string{"US": "EPA:06-075-0005"},
+			}},
+			want: "06-075-0005",
+		},
+		{
+			have: &ds.Station{Identifiers: &ds.Identifiers{
+				RegionalIDs: map[string]string{"CA": "EPA:CC-040-0207"},
+			}},
+			want: "CC-040-0207",
+		},
+		{
+			// The site ID is found among the other IDs for the region.
+			have: &ds.Station{Identifiers: &ds.Identifiers{
+				RegionalIDs: map[string]string{"US": "EPA:06-075-0005 HCDN:AL293"},
+			}},
+			want: "06-075-0005",
+		},
+		{have: ds.EmptyStation(), want: ""},
+	}
+
+	for _, test := range tests {
+		if got, _ := stationKeyFn(test.have); got != test.want {
+			t.Errorf("stationKeyFn(%v) = %q, want %q", test.have.Identifiers.RegionalIDs, got, test.want)
+		}
+	}
+}
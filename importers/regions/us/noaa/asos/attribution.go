@@ -0,0 +1,55 @@
+package asos
+
+import (
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	// OneMinuteDatasetName is the name used for the ASOS one minute data in
+	// Attributions and as the Source of its observations.
+	OneMinuteDatasetName = "ASOS-1MIN"
+
+	// FiveMinuteDatasetName is the name used for the ASOS five minute data
+	// in Attributions and as the Source of its observations.
+	FiveMinuteDatasetName = "ASOS-5MIN"
+
+	// OneMinuteDatasetCitation cites the ASOS one minute data.
+	OneMinuteDatasetCitation = "NOAA National Centers for Environmental Information: " +
+		"Automated Surface Observing System (ASOS) One Minute Data, TD-6405 and TD-6406."
+
+	// FiveMinuteDatasetCitation cites the ASOS five minute data.
+	FiveMinuteDatasetCitation = "NOAA National Centers for Environmental Information: " +
+		"Automated Surface Observing System (ASOS) Five Minute Data, TD-6401."
+)
+
+// The ASOS data are a U.S. Government work.
+var (
+	oneMinuteDataset = ds.Dataset{
+		Name:     OneMinuteDatasetName,
+		License:  ds.LicenseUSGovernment,
+		Citation: OneMinuteDatasetCitation,
+		Networks: []string{"ASOS"},
+	}
+	fiveMinuteDataset = ds.Dataset{
+		Name:     FiveMinuteDatasetName,
+		License:  ds.LicenseUSGovernment,
+		Citation: FiveMinuteDatasetCitation,
+		Networks: []string{"ASOS"},
+	}
+)
+
+// OneMinuteAttribution returns the Attributions for the ASOS one minute data.
+// Retrieved is when the data files were downloaded, and is left out if it is
+// the zero time.
+func OneMinuteAttribution(retrieved time.Time) *ds.Attributions {
+	return oneMinuteDataset.Attributions(retrieved)
+}
+
+// FiveMinuteAttribution returns the Attributions for the ASOS five minute
+// data. Retrieved is when the data files were downloaded, and is left out if
+// it is the zero time.
+func FiveMinuteAttribution(retrieved time.Time) *ds.Attributions {
+	return fiveMinuteDataset.Attributions(retrieved)
+}
@@ -5,12 +5,41 @@ and observational data.
 Data files are located:
 
 	https://www.ncei.noaa.gov/pub/data/asos-onemin/
+	https://www.ncei.noaa.gov/pub/data/asos-fivemin/
 
 File format documentation:
 
 	https://www.ncei.noaa.gov/pub/data/asos-onemin/td6405.txt
 	https://www.ncei.noaa.gov/pub/data/asos-onemin/td6406.txt
+	https://www.ncei.noaa.gov/pub/data/asos-fivemin/td6401.txt
 
-TODO(rsned): Add more to this documentation for the package.
+The one minute data is split over two pages, with one file per station per
+month for each. Page 1, (TD 6405), holds the visibility and wind, and page 2,
+(TD 6406), the precipitation, pressure, temperature and dew point:
+
+	64050KLHX201701.dat
+	64060KLHX201701.dat
+
+Each line starts with the stations WBAN, ICAO and IATA IDs, and the time in
+local standard time followed by the UTC time of day. Local standard time is
+used all year round, so the stations timezone is needed to find the UTC time
+across the change of date. The values follow in columns whose spacing drifts
+between stations and over the years, and missing values are often left blank,
+so the values are told apart by their form rather than their column:
+
+	03013KLHX LHX2017010100000700   0.127 N                 0.128 N      270     6    272     9
+	03013KLHX LHX2017010100000700   NP [0000   ]  0.00         25.148  25.150  25.146    30    14
+
+The two pages are joined on station and minute into one Observation, (see
+OneMinuteObservations and JoinPages).
+
+The five minute data, (TD 6401), has one file per station per month, and each
+line holds the METAR or SPECI report made at that time after the station,
+local time and some record fields:
+
+	03013KLHX LHX2017010100001001 01/01/17 00:00:31  5-MIN KLHX 010700Z AUTO 27006KT 10SM CLR M01/M10 A3007 RMK AO2 T10061100
+
+The reports are decoded with the metar package, which keeps the report text
+in the Observation for provenance, (see FiveMinuteObservations).
 */
 package asos
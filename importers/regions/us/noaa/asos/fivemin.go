@@ -0,0 +1,97 @@
+package asos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/metar"
+)
+
+// fiveMinuteHeaderLength is the length of the fixed width station and time
+// fields at the start of every five minute line.
+//
+//	WBAN          1-5
+//	ICAO          6-9
+//	IATA         11-13
+//	LST date    14-25   YYYYMMDDHHMM in local standard time
+const fiveMinuteHeaderLength = 25
+
+// maxOffset is more than the largest offset of local standard time from UTC,
+// used to find a time after the UTC time of the report from the local time.
+const maxOffset = 14 * time.Hour
+
+func init() {
+	register.DoFn2x0[string, func(*ds.Observation)](&FiveMinuteParserFn{})
+}
+
+// FiveMinuteObservations parses the given PCollection<string> of five minute
+// lines and returns a PCollection<*ds.Observation> of their METAR reports.
+func FiveMinuteObservations(s beam.Scope, lines beam.PCollection) beam.PCollection {
+	s = s.Scope("asos.FiveMinuteObservations")
+	return beam.ParDo(s, &FiveMinuteParserFn{}, lines)
+}
+
+// FiveMinuteParserFn is an Apache Beam structural DoFn to process five minute
+// lines into Observations. Malformed lines are skipped.
+type FiveMinuteParserFn struct {
+}
+
+// ProcessElement reads one line in and attempts to convert it into an Observation.
+func (fn *FiveMinuteParserFn) ProcessElement(line string, emit func(*ds.Observation)) {
+	if obs, err := ParseFiveMinuteLine(line); err == nil {
+		emit(obs)
+	}
+}
+
+// ParseFiveMinuteLine parses one line of the five minute, (TD 6401), data
+// into an Observation. Each line holds the METAR report made at the time,
+// after the station and time fields and some record fields, e.g.
+//
+//	03013KLHX LHX2017010100001001 01/01/17 00:00:31  5-MIN KLHX 010700Z AUTO 27006KT 10SM CLR M01/M10 A3007 RMK AO2 T10061100
+//
+// The report starts at the stations ICAO code, and is decoded with
+// metar.ParseObservation. The time of the Observation is the UTC time of the
+// report, so the stations timezone is not needed.
+func ParseFiveMinuteLine(line string) (*ds.Observation, error) {
+	if len(line) < fiveMinuteHeaderLength {
+		return nil, fmt.Errorf("asos: line has length %d, want at least %d", len(line), fiveMinuteHeaderLength)
+	}
+
+	icao := strings.TrimSpace(line[5:9])
+	if icao == "" {
+		return nil, fmt.Errorf("asos: missing ICAO in line %q", line)
+	}
+	lst, err := time.Parse("200601021504", line[13:25])
+	if err != nil {
+		return nil, fmt.Errorf("asos: invalid local standard time in line %q", line)
+	}
+
+	// The report is passed on as it is in the line, so that the
+	// Observation keeps it exactly.
+	rest := line[fiveMinuteHeaderLength:]
+	start := -1
+	for i := range rest {
+		end := i + len(icao)
+		if strings.HasPrefix(rest[i:], icao) && (i == 0 || rest[i-1] == ' ') && (end == len(rest) || rest[end] == ' ') {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("asos: missing METAR in line %q", line)
+	}
+
+	obs, err := metar.ParseObservation(rest[start:], lst.Add(maxOffset))
+	if err != nil {
+		return nil, err
+	}
+	obs.Source = FiveMinuteDatasetName
+	obs.License = fiveMinuteDataset.License
+	obs.Citation = fiveMinuteDataset.Citation
+	return obs, nil
+}
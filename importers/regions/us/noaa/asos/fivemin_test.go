@@ -0,0 +1,171 @@
+package asos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testFiveMinute     = "03013KLHX LHX2017010100001001 01/01/17 00:00:31  5-MIN KLHX 010700Z AUTO 27006KT 10SM CLR M01/M10 A3007 RMK AO2 T10061100"
+	testFiveMinuteNext = "03013KLHX LHX2017013117551001 01/31/17 17:55:31  5-MIN KLHX 010055Z AUTO 27006KT 10SM CLR M01/M10 A3007"
+)
+
+func TestParseFiveMinuteLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *ds.Observation
+		wantErr bool
+	}{
+		{
+			// The T group gives the temperatures in tenths.
+			name: "valid",
+			line: testFiveMinute,
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               FiveMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             FiveMinuteDatasetCitation,
+				Time:                 time.Date(2017, 1, 1, 7, 0, 0, 0, time.UTC),
+				TempC:                units.MustConvert(-6, units.TenthsCelsius, units.Celsius),
+				DewPointC:            units.MustConvert(-100, units.TenthsCelsius, units.Celsius),
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: units.MustConvert(3007, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          units.MustConvert(10, units.StatuteMiles, units.Meters),
+				SkyCoverOktas:        0,
+				CloudLayers:          []*ds.CloudLayer{{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue}},
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Report:               "KLHX 010700Z AUTO 27006KT 10SM CLR M01/M10 A3007 RMK AO2 T10061100",
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+				},
+			},
+		},
+		{
+			// The local time is still in January, but the report is in February.
+			name: "next month in UTC",
+			line: testFiveMinuteNext,
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               FiveMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             FiveMinuteDatasetCitation,
+				Time:                 time.Date(2017, 2, 1, 0, 55, 0, 0, time.UTC),
+				TempC:                -1,
+				DewPointC:            -10,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: units.MustConvert(3007, units.HundredthsInchesOfHg, units.Hectopascals),
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          units.MustConvert(10, units.StatuteMiles, units.Meters),
+				SkyCoverOktas:        0,
+				CloudLayers:          []*ds.CloudLayer{{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue}},
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Report:               "KLHX 010055Z AUTO 27006KT 10SM CLR M01/M10 A3007",
+				Flags: ds.Flags{
+					"TempC":                {Quality: ds.QualityPassed},
+					"DewPointC":            {Quality: ds.QualityPassed},
+					"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":         {Quality: ds.QualityPassed},
+					"VisibilityM":          {Quality: ds.QualityPassed},
+					"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+				},
+			},
+		},
+		{name: "short line", line: "03013KLHX LHX20170101", wantErr: true},
+		{name: "missing report", line: "03013KLHX LHX2017010100001001 01/01/17 00:00:31  5-MIN", wantErr: true},
+		{name: "bad report", line: "03013KLHX LHX2017010100001001 01/01/17 00:00:31  5-MIN KLHX 27006KT", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseFiveMinuteLine(test.line)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ParseFiveMinuteLine() error = %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: ParseFiveMinuteLine() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestFiveMinuteObservations(t *testing.T) {
+	beam.Init()
+	p, s := beam.NewPipelineWithRoot()
+
+	lines := beam.Create(s, testFiveMinuteNext, "not a line")
+	got := FiveMinuteObservations(s, lines)
+
+	passert.Equals(s, got, &ds.Observation{
+		StationID:            "KLHX",
+		Source:               FiveMinuteDatasetName,
+		License:              ds.LicenseUSGovernment,
+		Citation:             FiveMinuteDatasetCitation,
+		Time:                 time.Date(2017, 2, 1, 0, 55, 0, 0, time.UTC),
+		TempC:                -1,
+		DewPointC:            -10,
+		RelativeHumidity:     ds.UnsetValue,
+		PressureStationHPa:   ds.UnsetValue,
+		PressureSeaLevelHPa:  ds.UnsetValue,
+		PressureAltimeterHPa: units.MustConvert(3007, units.HundredthsInchesOfHg, units.Hectopascals),
+		WindDirectionDeg:     270,
+		WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+		WindGustMPS:          ds.UnsetValue,
+		VisibilityM:          units.MustConvert(10, units.StatuteMiles, units.Meters),
+		SkyCoverOktas:        0,
+		CloudLayers:          []*ds.CloudLayer{{Cover: ds.CloudCoverClear, BaseM: ds.UnsetValue}},
+		CeilingM:             ds.UnsetValue,
+		Precip1HrMM:          ds.UnsetValue,
+		Precip3HrMM:          ds.UnsetValue,
+		Precip6HrMM:          ds.UnsetValue,
+		Precip24HrMM:         ds.UnsetValue,
+		SnowDepthMM:          ds.UnsetValue,
+		Report:               "KLHX 010055Z AUTO 27006KT 10SM CLR M01/M10 A3007",
+		Flags: ds.Flags{
+			"TempC":                {Quality: ds.QualityPassed},
+			"DewPointC":            {Quality: ds.QualityPassed},
+			"PressureAltimeterHPa": {Quality: ds.QualityPassed},
+			"WindDirectionDeg":     {Quality: ds.QualityPassed},
+			"WindSpeedMPS":         {Quality: ds.QualityPassed},
+			"VisibilityM":          {Quality: ds.QualityPassed},
+			"SkyCoverOktas":        {Quality: ds.QualityEstimated},
+		},
+	})
+
+	if err := ptest.Run(p); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+}
@@ -0,0 +1,432 @@
+package asos
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
+	"github.com/rsned/weather/importers/units"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// headerLength is the length of the fixed width station and time fields at
+// the start of every one minute line. The layout of the rest of the line
+// drifts from station to station and over the years, and values are often
+// left out entirely, so the values after the header are told apart by their
+// form rather than by their column.
+//
+//	WBAN          1-5
+//	ICAO          6-9
+//	IATA         11-13
+//	LST date    14-25   YYYYMMDDHHMM in local standard time
+//	UTC time    26-29   HHMM
+const headerLength = 29
+
+// header holds the fixed fields at the start of a one minute line.
+type header struct {
+	icao string
+	time time.Time
+}
+
+// parseHeader parses the fixed width fields at the start of a one minute line,
+// and returns them along with the whitespace separated fields which follow.
+//
+// The local standard time is converted to UTC using the stations timezone if
+// given. Otherwise the offset from the UTC time of day in the line is used.
+func parseHeader(line, tz string) (*header, []string, error) {
+	if len(line) < headerLength {
+		return nil, nil, fmt.Errorf("asos: line has length %d, want at least %d", len(line), headerLength)
+	}
+
+	icao := strings.TrimSpace(line[5:9])
+	if icao == "" {
+		return nil, nil, fmt.Errorf("asos: missing ICAO in line %q", line)
+	}
+	lst, err := time.Parse("200601021504", line[13:25])
+	if err != nil {
+		return nil, nil, fmt.Errorf("asos: invalid local standard time in line %q", line)
+	}
+
+	var t time.Time
+	if tz != "" {
+		if t, err = geography.LocalStandardToUTC(lst, tz); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		utc, err := time.Parse("1504", line[25:29])
+		if err != nil {
+			return nil, nil, fmt.Errorf("asos: invalid UTC time in line %q", line)
+		}
+		// The offset is the difference between the two times of day, which
+		// is always within 12 hours for the US and its territories.
+		diff := (utc.Hour()*60 + utc.Minute()) - (lst.Hour()*60 + lst.Minute())
+		diff = ((diff+720)%1440+1440)%1440 - 720
+		t = lst.Add(time.Duration(diff) * time.Minute)
+	}
+
+	return &header{icao: icao, time: t}, strings.Fields(line[headerLength:]), nil
+}
+
+// newObservation starts a new Observation for the line header.
+func newObservation(h *header) *ds.Observation {
+	obs := ds.EmptyObservation()
+	obs.StationID = h.icao
+	obs.Source = OneMinuteDatasetName
+	obs.License = oneMinuteDataset.License
+	obs.Citation = oneMinuteDataset.Citation
+	obs.Time = h.time
+	return obs
+}
+
+// ParsePage1Line parses one line of the page 1, (TD 6405), one minute data for
+// the station in the given IANA timezone into an Observation with its wind.
+//
+//	Visibility extinction coefficient, or M, and D or N for day or night (sensor 1)
+//	Visibility extinction coefficient, or M, and D or N for day or night (sensor 2)
+//	Two minute average wind direction (degrees)
+//	Two minute average wind speed (knots)
+//	Direction of the maximum five second wind (degrees)
+//	Speed of the maximum five second wind (knots)
+//	Runway visual range runway and value (feet), when reported
+//
+// e.g.,
+//
+//	03013KLHX LHX2017010100000700   0.127 N                 0.128 N      270     6    272     6
+//
+// The extinction coefficients are not converted to a visibility, as ASOS uses
+// its own day and night algorithms for that.
+func ParsePage1Line(line, tz string) (*ds.Observation, error) {
+	h, fields, err := parseHeader(line, tz)
+	if err != nil {
+		return nil, err
+	}
+
+	wind := page1Wind(fields)
+	if wind == nil {
+		return nil, fmt.Errorf("asos: missing wind values in page 1 line %q", line)
+	}
+
+	obs := newObservation(h)
+	dir := parseValue(wind[0], 0, 360)
+	speed := parseValue(wind[1], 0, 200)
+	gust := parseValue(wind[3], 0, 200)
+	if speed != ds.UnsetValue {
+		obs.WindSpeedMPS = units.MustConvert(speed, units.Knots, units.MetersPerSecond)
+		obs.Flags.Set("WindSpeedMPS", &ds.Flag{Quality: ds.QualityPassed})
+	}
+	// Calm winds have no direction.
+	if dir != ds.UnsetValue && speed != 0 {
+		obs.WindDirectionDeg = dir
+		obs.Flags.Set("WindDirectionDeg", &ds.Flag{Quality: ds.QualityPassed})
+	}
+	if gust != ds.UnsetValue {
+		obs.WindGustMPS = units.MustConvert(gust, units.Knots, units.MetersPerSecond)
+		obs.Flags.Set("WindGustMPS", &ds.Flag{Quality: ds.QualityPassed})
+	}
+	return obs, nil
+}
+
+// page1Wind returns the four wind values from the fields after the header of a
+// page 1 line, or nil if they are not all there.
+//
+// Either sensors extinction coefficient, its day or night flag, or the whole
+// pair may be left out, so each field is checked by its shape rather than its
+// position. A coefficient has a decimal point, or is M when missing, and the
+// wind values are whole numbers or M. A lone M is only taken as a coefficient
+// when it is followed by a flag, or by more than the four wind values.
+func page1Wind(fields []string) []string {
+	for sensor := 0; sensor < 2 && isExtinction(fields); sensor++ {
+		if !isDayNightFlag(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && isDayNightFlag(fields[0]) {
+			fields = fields[1:]
+		}
+	}
+	if windValues(fields) < 4 {
+		return nil
+	}
+	return fields[:4]
+}
+
+// isExtinction reports if the fields start with a sensors extinction
+// coefficient or its day or night flag.
+func isExtinction(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	switch f := fields[0]; {
+	case isDayNightFlag(f):
+		return true
+	case f == "M":
+		return len(fields) > 1 && isDayNightFlag(fields[1]) || windValues(fields) > 4
+	}
+	return strings.Contains(fields[0], ".") && utils.ParseFloat(fields[0], ds.UnsetValue) != ds.UnsetValue
+}
+
+// isDayNightFlag reports if the field is the D or N flag after an extinction
+// coefficient.
+func isDayNightFlag(f string) bool {
+	return f == "D" || f == "N"
+}
+
+// windValues returns the number of fields at the start of fields which have
+// the shape of a wind value, a whole number or M.
+func windValues(fields []string) int {
+	for i, f := range fields {
+		if f == "M" {
+			continue
+		}
+		if _, err := strconv.Atoi(f); err != nil {
+			return i
+		}
+	}
+	return len(fields)
+}
+
+// ParsePage2Line parses one line of the page 2, (TD 6406), one minute data for
+// the station in the given IANA timezone into an Observation with its
+// precipitation type, pressure, temperature and dew point.
+//
+//	Precipitation identifier, (NP for none, R for rain, S for snow, P for unknown)
+//	Precipitation amount (inches)
+//	Frozen precipitation sensor frequency
+//	Pressure (inches of mercury, sensor 1)
+//	Pressure (inches of mercury, sensor 2)
+//	Pressure (inches of mercury, sensor 3)
+//	Dry bulb temperature (degrees F)
+//	Dew point temperature (degrees F)
+//
+// e.g.,
+//
+//	03013KLHX LHX2017010100000700   NP [0000   ]  0.00         25.148  25.150  25.146    30    14
+//
+// Fields are often missing entirely rather than marked with an M, so the
+// pressures are found by their form, (three decimal places), and the
+// temperatures are the two fields after them. The precipitation amount is
+// not kept, as Observations do not hold one minute totals.
+func ParsePage2Line(line, tz string) (*ds.Observation, error) {
+	h, fields, err := parseHeader(line, tz)
+	if err != nil {
+		return nil, err
+	}
+
+	obs := newObservation(h)
+	if len(fields) > 0 {
+		if code := precipCode(fields[0]); code != "" {
+			obs.PresentWeather = []string{code}
+		}
+	}
+
+	last := -1
+	var pressures []float64
+	for i, f := range fields {
+		dot := strings.Index(f, ".")
+		if dot < 0 || len(f)-dot != 4 {
+			continue
+		}
+		last = i
+		if p := parseValue(f, 15, 35); p != ds.UnsetValue {
+			pressures = append(pressures, p)
+		}
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("asos: missing pressure values in page 2 line %q", line)
+	}
+
+	if len(pressures) > 0 {
+		obs.PressureStationHPa = units.MustConvert(median(pressures), units.InchesOfMercury, units.Hectopascals)
+		obs.Flags.Set("PressureStationHPa", &ds.Flag{Quality: ds.QualityPassed})
+	}
+
+	temps := fields[last+1:]
+	if len(temps) > 0 {
+		if v := parseValue(temps[0], -100, 150); v != ds.UnsetValue {
+			obs.TempC = units.MustConvert(v, units.Fahrenheit, units.Celsius)
+			obs.Flags.Set("TempC", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+	if len(temps) > 1 {
+		if v := parseValue(temps[1], -100, 150); v != ds.UnsetValue {
+			obs.DewPointC = units.MustConvert(v, units.Fahrenheit, units.Celsius)
+			obs.Flags.Set("DewPointC", &ds.Flag{Quality: ds.QualityPassed})
+		}
+	}
+	return obs, nil
+}
+
+// parseValue parses a numeric field, returning UnsetValue if it is missing,
+// (M), malformed or outside of [min, max].
+func parseValue(s string, min, max float64) float64 {
+	return utils.ParseFloatBounded(s, min, max, ds.UnsetValue)
+}
+
+// median returns the median of the values from the pressure sensors, so that
+// one faulty sensor out of three is ignored.
+func median(values []float64) float64 {
+	v := append([]float64(nil), values...)
+	sort.Float64s(v)
+	if len(v)%2 == 1 {
+		return v[len(v)/2]
+	}
+	return (v[len(v)/2-1] + v[len(v)/2]) / 2
+}
+
+// precipCode converts the precipitation identifier into a METAR present
+// weather code, e.g. "R-" to "-RA". "" is returned for no or unknown
+// precipitation identifiers.
+func precipCode(id string) string {
+	intensity := ""
+	switch {
+	case strings.HasSuffix(id, "-"), strings.HasSuffix(id, "+"):
+		intensity = id[len(id)-1:]
+		id = id[:len(id)-1]
+	}
+
+	var code string
+	switch id {
+	case "R":
+		code = "RA"
+	case "S":
+		code = "SN"
+	case "P":
+		code = "UP"
+	default:
+		return ""
+	}
+	return intensity + code
+}
+
+// JoinPages joins the page 1 and page 2 observations for the same station and
+// minute together into one Observation, sorted by station and then time.
+// Observations that only appear on one of the pages are kept as they are.
+func JoinPages(page1, page2 []*ds.Observation) []*ds.Observation {
+	minutes := make(map[string]*ds.Observation)
+	var keys []string
+	for _, o := range append(append([]*ds.Observation(nil), page1...), page2...) {
+		key, _ := observationKeyFn(o)
+		if prev, ok := minutes[key]; ok {
+			mergeObservation(prev, o)
+			continue
+		}
+		minutes[key] = o
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	out := make([]*ds.Observation, len(keys))
+	for i, k := range keys {
+		out[i] = minutes[k]
+	}
+	return out
+}
+
+// mergeObservation copies the values which are set in o into obs.
+func mergeObservation(obs, o *ds.Observation) {
+	for _, f := range []struct {
+		name     string
+		dst, src *float64
+	}{
+		{"TempC", &obs.TempC, &o.TempC},
+		{"DewPointC", &obs.DewPointC, &o.DewPointC},
+		{"PressureStationHPa", &obs.PressureStationHPa, &o.PressureStationHPa},
+		{"WindDirectionDeg", &obs.WindDirectionDeg, &o.WindDirectionDeg},
+		{"WindSpeedMPS", &obs.WindSpeedMPS, &o.WindSpeedMPS},
+		{"WindGustMPS", &obs.WindGustMPS, &o.WindGustMPS},
+	} {
+		if *f.src == ds.UnsetValue {
+			continue
+		}
+		*f.dst = *f.src
+		obs.Flags.Set(f.name, o.Flags[f.name])
+	}
+	obs.PresentWeather = append(obs.PresentWeather, o.PresentWeather...)
+}
+
+func init() {
+	register.DoFn2x0[string, func(*ds.Observation)](&Page1ParserFn{})
+	register.DoFn2x0[string, func(*ds.Observation)](&Page2ParserFn{})
+	register.Function1x2(observationKeyFn)
+	register.Function4x0(joinPagesFn)
+	register.Emitter1[*ds.Observation]()
+	register.Iter1[*ds.Observation]()
+}
+
+// OneMinuteObservations parses the given PCollection<string>s of page 1 and
+// page 2 lines, and returns a PCollection<*ds.Observation> with one joined
+// Observation for each station minute.
+//
+// Timezones maps the stations ICAO codes to their IANA timezone, (see
+// geography.Timezone), and may be nil to rely on the UTC time in each line.
+func OneMinuteObservations(s beam.Scope, timezones map[string]string, page1, page2 beam.PCollection) beam.PCollection {
+	s = s.Scope("asos.OneMinuteObservations")
+	p1 := beam.ParDo(s, observationKeyFn, beam.ParDo(s, &Page1ParserFn{Timezones: timezones}, page1))
+	p2 := beam.ParDo(s, observationKeyFn, beam.ParDo(s, &Page2ParserFn{Timezones: timezones}, page2))
+	return beam.ParDo(s, joinPagesFn, beam.CoGroupByKey(s, p1, p2))
+}
+
+// Page1ParserFn is an Apache Beam structural DoFn to process page 1 one minute
+// lines into Observations. Malformed lines are skipped.
+type Page1ParserFn struct {
+	// Timezones maps the stations ICAO codes to their IANA timezone.
+	Timezones map[string]string
+}
+
+// ProcessElement reads one line in and attempts to convert it into an Observation.
+func (fn *Page1ParserFn) ProcessElement(line string, emit func(*ds.Observation)) {
+	if obs, err := ParsePage1Line(line, lineTimezone(fn.Timezones, line)); err == nil {
+		emit(obs)
+	}
+}
+
+// Page2ParserFn is an Apache Beam structural DoFn to process page 2 one minute
+// lines into Observations. Malformed lines are skipped.
+type Page2ParserFn struct {
+	// Timezones maps the stations ICAO codes to their IANA timezone.
+	Timezones map[string]string
+}
+
+// ProcessElement reads one line in and attempts to convert it into an Observation.
+func (fn *Page2ParserFn) ProcessElement(line string, emit func(*ds.Observation)) {
+	if obs, err := ParsePage2Line(line, lineTimezone(fn.Timezones, line)); err == nil {
+		emit(obs)
+	}
+}
+
+// lineTimezone returns the timezone for the station of the line, or "" if
+// it is not known.
+func lineTimezone(timezones map[string]string, line string) string {
+	if len(line) < headerLength {
+		return ""
+	}
+	return timezones[strings.TrimSpace(line[5:9])]
+}
+
+// observationKeyFn keys the observation by its station and time.
+func observationKeyFn(o *ds.Observation) (string, *ds.Observation) {
+	return o.StationID + "," + ds.FormatTime(o.Time), o
+}
+
+// joinPagesFn joins the page 1 and page 2 observations for one station minute.
+func joinPagesFn(_ string, page1, page2 func(**ds.Observation) bool, emit func(*ds.Observation)) {
+	var out, o *ds.Observation
+	for _, iter := range []func(**ds.Observation) bool{page1, page2} {
+		for iter(&o) {
+			if out == nil {
+				out = o
+				continue
+			}
+			mergeObservation(out, o)
+		}
+	}
+	if out != nil {
+		emit(out)
+	}
+}
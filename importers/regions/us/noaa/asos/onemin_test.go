@@ -0,0 +1,638 @@
+package asos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/units"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testPage1 = "03013KLHX LHX2017010100000700   0.127 N                 0.128 N      270     6    272     9   "
+	testPage2 = "03013KLHX LHX2017010100000700   NP [0000   ]  0.00         25.148  25.150  25.146    30    14   "
+)
+
+var testTime = time.Date(2017, 1, 1, 7, 0, 0, 0, time.UTC)
+
+func TestParseHeaderTime(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		tz   string
+		want time.Time
+	}{
+		{
+			name: "from timezone",
+			line: testPage1,
+			tz:   "America/Denver",
+			want: testTime,
+		},
+		{
+			name: "from UTC time of day",
+			line: testPage1,
+			want: testTime,
+		},
+		{
+			// LST is always used, so a summer time is still 7 hours behind.
+			name: "summer in timezone",
+			line: "03013KLHX LHX2017070112300730   0.127 N   270     6    272     9",
+			tz:   "America/Denver",
+			want: time.Date(2017, 7, 1, 19, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "UTC time of day on the next day",
+			line: "03013KLHX LHX2017123120590359   0.127 N   270     6    272     9",
+			want: time.Date(2018, 1, 1, 3, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "east of UTC",
+			line: "41415PGUM GUM2017010100001400   0.127 N   270     6    272     9",
+			want: time.Date(2016, 12, 31, 14, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		h, _, err := parseHeader(test.line, test.tz)
+		if err != nil {
+			t.Errorf("%s: parseHeader() = %v", test.name, err)
+			continue
+		}
+		if !h.time.Equal(test.want) {
+			t.Errorf("%s: parseHeader() time = %v, want %v", test.name, h.time, test.want)
+		}
+	}
+}
+
+func TestParsePage1Line(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *ds.Observation
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			line: testPage1,
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                ds.UnsetValue,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+				WindGustMPS:          units.MustConvert(9, units.Knots, units.MetersPerSecond),
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"WindDirectionDeg": {Quality: ds.QualityPassed},
+					"WindSpeedMPS":     {Quality: ds.QualityPassed},
+					"WindGustMPS":      {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name: "calm",
+			line: "03013KLHX LHX2017010100000700   0.127 N   0.128 N      0     0      0     0",
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                ds.UnsetValue,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         0,
+				WindGustMPS:          0,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"WindSpeedMPS": {Quality: ds.QualityPassed},
+					"WindGustMPS":  {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name: "missing gust",
+			line: "03013KLHX LHX2017010100000700   0.127 N   0.128 N    270     6    272     M",
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                ds.UnsetValue,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"WindDirectionDeg": {Quality: ds.QualityPassed},
+					"WindSpeedMPS":     {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name: "missing coefficient and gust",
+			line: "03013KLHX LHX2017010100000700   M      270     6    272     M",
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                ds.UnsetValue,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     270,
+				WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"WindDirectionDeg": {Quality: ds.QualityPassed},
+					"WindSpeedMPS":     {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name:    "missing wind",
+			line:    "03013KLHX LHX2017010100000700   0.127 N   0.128 N",
+			wantErr: true,
+		},
+		{
+			name:    "short wind",
+			line:    "03013KLHX LHX2017010100000700   0.127 N   0.128 N    270     6",
+			wantErr: true,
+		},
+		{
+			name:    "short line",
+			line:    "03013KLHX LHX20170101",
+			wantErr: true,
+		},
+		{
+			name:    "bad time",
+			line:    "03013KLHX LHX2017013200000700   0.127 N   0.128 N    270     6    272     9",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePage1Line(test.line, "America/Denver")
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ParsePage1Line() error = %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: ParsePage1Line() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+// TestParsePage1LineForms checks the other forms the values before the wind
+// are found in, which should all parse the same as testPage1.
+func TestParsePage1LineForms(t *testing.T) {
+	want, err := ParsePage1Line(testPage1, "America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{
+			name: "day flags and runway visual range",
+			line: "03013KLHX LHX2017010100000700   0.127 D  0.128 D  270     6    272     9  36R50+",
+		},
+		{
+			name: "missing extinction coefficients",
+			line: "03013KLHX LHX2017010100000700   M     N                 M     N      270     6    272     9",
+		},
+		{
+			name: "one missing extinction coefficient",
+			line: "03013KLHX LHX2017010100000700   0.127 N                 M     N      270     6    272     9",
+		},
+		{
+			name: "no extinction coefficients",
+			line: "03013KLHX LHX2017010100000700                 270     6    272     9",
+		},
+		{
+			name: "missing day or night flag",
+			line: "03013KLHX LHX2017010100000700   0.127 N   0.128   270     6    272     9",
+		},
+		{
+			name: "missing first day or night flag",
+			line: "03013KLHX LHX2017010100000700   0.127   0.128 N   270     6    272     9",
+		},
+		{
+			name: "missing second sensor",
+			line: "03013KLHX LHX2017010100000700   0.127 N        270     6    272     9  36R50+",
+		},
+		{
+			name: "only day or night flags",
+			line: "03013KLHX LHX2017010100000700         N                 N      270     6    272     9",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePage1Line(test.line, "America/Denver")
+		if err != nil {
+			t.Errorf("%s: ParsePage1Line() error = %v", test.name, err)
+			continue
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: ParsePage1Line() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestParsePage2Line(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    *ds.Observation
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			line: testPage2,
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                units.MustConvert(30, units.Fahrenheit, units.Celsius),
+				DewPointC:            units.MustConvert(14, units.Fahrenheit, units.Celsius),
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   units.MustConvert(25.148, units.InchesOfMercury, units.Hectopascals),
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         ds.UnsetValue,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"TempC":              {Quality: ds.QualityPassed},
+					"DewPointC":          {Quality: ds.QualityPassed},
+					"PressureStationHPa": {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name: "light snow",
+			line: "03013KLHX LHX2017010100000700   S- [0000   ]  0.00         25.148  25.150  25.146    30    14",
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                units.MustConvert(30, units.Fahrenheit, units.Celsius),
+				DewPointC:            units.MustConvert(14, units.Fahrenheit, units.Celsius),
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   units.MustConvert(25.148, units.InchesOfMercury, units.Hectopascals),
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         ds.UnsetValue,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				PresentWeather:       []string{"-SN"},
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"TempC":              {Quality: ds.QualityPassed},
+					"DewPointC":          {Quality: ds.QualityPassed},
+					"PressureStationHPa": {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name: "missing temperatures",
+			line: "03013KLHX LHX2017010100000700   NP [0000   ]  0.00   25.148  25.150  25.146     M     M",
+			want: &ds.Observation{
+				StationID:            "KLHX",
+				Source:               OneMinuteDatasetName,
+				License:              ds.LicenseUSGovernment,
+				Citation:             OneMinuteDatasetCitation,
+				Time:                 testTime,
+				TempC:                ds.UnsetValue,
+				DewPointC:            ds.UnsetValue,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   units.MustConvert(25.148, units.InchesOfMercury, units.Hectopascals),
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         ds.UnsetValue,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        ds.UnsetValue,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          ds.UnsetValue,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"PressureStationHPa": {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			name:    "no pressures",
+			line:    "03013KLHX LHX2017010100000700   NP [0000   ]  0.00    30    14",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePage2Line(test.line, "America/Denver")
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ParsePage2Line() error = %v, wantErr %v", test.name, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: ParsePage2Line() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+// TestParsePage2LineForms checks the other forms the values before the
+// pressures are found in, which should all parse the same as testPage2.
+func TestParsePage2LineForms(t *testing.T) {
+	want, err := ParsePage2Line(testPage2, "America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{
+			name: "missing precipitation fields",
+			line: "03013KLHX LHX2017010100000700            25.148  25.150  25.146    30    14",
+		},
+		{
+			name: "faulty and missing sensors",
+			line: "03013KLHX LHX2017010100000700   NP  0.00  25.148  99.999  25.150  25.146   30    14",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePage2Line(test.line, "America/Denver")
+		if err != nil {
+			t.Errorf("%s: ParsePage2Line() error = %v", test.name, err)
+			continue
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: ParsePage2Line() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestPrecipCode(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"NP", ""},
+		{"R", "RA"},
+		{"R-", "-RA"},
+		{"R+", "+RA"},
+		{"S", "SN"},
+		{"S+", "+SN"},
+		{"P-", "-UP"},
+		{"[0000", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := precipCode(test.id); got != test.want {
+			t.Errorf("precipCode(%q) = %q, want %q", test.id, got, test.want)
+		}
+	}
+}
+
+func TestJoinPages(t *testing.T) {
+	p1, err := ParsePage1Line(testPage1, "America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := ParsePage2Line(testPage2, "America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := ParsePage1Line("03013KLHX LHX2017010100010701   0.127 N   0.128 N    270     6    272     9", "America/Denver")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := JoinPages([]*ds.Observation{next, p1}, []*ds.Observation{p2})
+	want := []*ds.Observation{
+		{
+			StationID:            "KLHX",
+			Source:               OneMinuteDatasetName,
+			License:              ds.LicenseUSGovernment,
+			Citation:             OneMinuteDatasetCitation,
+			Time:                 testTime,
+			TempC:                units.MustConvert(30, units.Fahrenheit, units.Celsius),
+			DewPointC:            units.MustConvert(14, units.Fahrenheit, units.Celsius),
+			RelativeHumidity:     ds.UnsetValue,
+			PressureStationHPa:   units.MustConvert(25.148, units.InchesOfMercury, units.Hectopascals),
+			PressureSeaLevelHPa:  ds.UnsetValue,
+			PressureAltimeterHPa: ds.UnsetValue,
+			WindDirectionDeg:     270,
+			WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+			WindGustMPS:          units.MustConvert(9, units.Knots, units.MetersPerSecond),
+			VisibilityM:          ds.UnsetValue,
+			SkyCoverOktas:        ds.UnsetValue,
+			CeilingM:             ds.UnsetValue,
+			Precip1HrMM:          ds.UnsetValue,
+			Precip3HrMM:          ds.UnsetValue,
+			Precip6HrMM:          ds.UnsetValue,
+			Precip24HrMM:         ds.UnsetValue,
+			SnowDepthMM:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempC":              {Quality: ds.QualityPassed},
+				"DewPointC":          {Quality: ds.QualityPassed},
+				"PressureStationHPa": {Quality: ds.QualityPassed},
+				"WindDirectionDeg":   {Quality: ds.QualityPassed},
+				"WindSpeedMPS":       {Quality: ds.QualityPassed},
+				"WindGustMPS":        {Quality: ds.QualityPassed},
+			},
+		},
+		{
+			StationID:            "KLHX",
+			Source:               OneMinuteDatasetName,
+			License:              ds.LicenseUSGovernment,
+			Citation:             OneMinuteDatasetCitation,
+			Time:                 testTime.Add(time.Minute),
+			TempC:                ds.UnsetValue,
+			DewPointC:            ds.UnsetValue,
+			RelativeHumidity:     ds.UnsetValue,
+			PressureStationHPa:   ds.UnsetValue,
+			PressureSeaLevelHPa:  ds.UnsetValue,
+			PressureAltimeterHPa: ds.UnsetValue,
+			WindDirectionDeg:     270,
+			WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+			WindGustMPS:          units.MustConvert(9, units.Knots, units.MetersPerSecond),
+			VisibilityM:          ds.UnsetValue,
+			SkyCoverOktas:        ds.UnsetValue,
+			CeilingM:             ds.UnsetValue,
+			Precip1HrMM:          ds.UnsetValue,
+			Precip3HrMM:          ds.UnsetValue,
+			Precip6HrMM:          ds.UnsetValue,
+			Precip24HrMM:         ds.UnsetValue,
+			SnowDepthMM:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"WindDirectionDeg": {Quality: ds.QualityPassed},
+				"WindSpeedMPS":     {Quality: ds.QualityPassed},
+				"WindGustMPS":      {Quality: ds.QualityPassed},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("JoinPages() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestOneMinuteObservations(t *testing.T) {
+	beam.Init()
+	p, s := beam.NewPipelineWithRoot()
+
+	page1 := beam.Create(s, testPage1, "03013KLHX LHX2017010100010701   0.127 N   0.128 N    270     6    272     9", "not a line")
+	page2 := beam.Create(s, testPage2)
+	got := OneMinuteObservations(s, map[string]string{"KLHX": "America/Denver"}, page1, page2)
+
+	passert.Equals(s, got,
+		&ds.Observation{
+			StationID:            "KLHX",
+			Source:               OneMinuteDatasetName,
+			License:              ds.LicenseUSGovernment,
+			Citation:             OneMinuteDatasetCitation,
+			Time:                 testTime,
+			TempC:                units.MustConvert(30, units.Fahrenheit, units.Celsius),
+			DewPointC:            units.MustConvert(14, units.Fahrenheit, units.Celsius),
+			RelativeHumidity:     ds.UnsetValue,
+			PressureStationHPa:   units.MustConvert(25.148, units.InchesOfMercury, units.Hectopascals),
+			PressureSeaLevelHPa:  ds.UnsetValue,
+			PressureAltimeterHPa: ds.UnsetValue,
+			WindDirectionDeg:     270,
+			WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+			WindGustMPS:          units.MustConvert(9, units.Knots, units.MetersPerSecond),
+			VisibilityM:          ds.UnsetValue,
+			SkyCoverOktas:        ds.UnsetValue,
+			CeilingM:             ds.UnsetValue,
+			Precip1HrMM:          ds.UnsetValue,
+			Precip3HrMM:          ds.UnsetValue,
+			Precip6HrMM:          ds.UnsetValue,
+			Precip24HrMM:         ds.UnsetValue,
+			SnowDepthMM:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempC":              {Quality: ds.QualityPassed},
+				"DewPointC":          {Quality: ds.QualityPassed},
+				"PressureStationHPa": {Quality: ds.QualityPassed},
+				"WindDirectionDeg":   {Quality: ds.QualityPassed},
+				"WindSpeedMPS":       {Quality: ds.QualityPassed},
+				"WindGustMPS":        {Quality: ds.QualityPassed},
+			},
+		},
+		&ds.Observation{
+			StationID:            "KLHX",
+			Source:               OneMinuteDatasetName,
+			License:              ds.LicenseUSGovernment,
+			Citation:             OneMinuteDatasetCitation,
+			Time:                 testTime.Add(time.Minute),
+			TempC:                ds.UnsetValue,
+			DewPointC:            ds.UnsetValue,
+			RelativeHumidity:     ds.UnsetValue,
+			PressureStationHPa:   ds.UnsetValue,
+			PressureSeaLevelHPa:  ds.UnsetValue,
+			PressureAltimeterHPa: ds.UnsetValue,
+			WindDirectionDeg:     270,
+			WindSpeedMPS:         units.MustConvert(6, units.Knots, units.MetersPerSecond),
+			WindGustMPS:          units.MustConvert(9, units.Knots, units.MetersPerSecond),
+			VisibilityM:          ds.UnsetValue,
+			SkyCoverOktas:        ds.UnsetValue,
+			CeilingM:             ds.UnsetValue,
+			Precip1HrMM:          ds.UnsetValue,
+			Precip3HrMM:          ds.UnsetValue,
+			Precip6HrMM:          ds.UnsetValue,
+			Precip24HrMM:         ds.UnsetValue,
+			SnowDepthMM:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"WindDirectionDeg": {Quality: ds.QualityPassed},
+				"WindSpeedMPS":     {Quality: ds.QualityPassed},
+				"WindGustMPS":      {Quality: ds.QualityPassed},
+			},
+		})
+
+	if err := ptest.Run(p); err != nil {
+		t.Fatalf("pipeline failed: %v", err)
+	}
+}
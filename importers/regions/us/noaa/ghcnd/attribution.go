@@ -0,0 +1,63 @@
+package ghcnd
+
+import (
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+const (
+	// DatasetName is the name used for GHCN-D in Attributions and as the
+	// Source of its observations.
+	DatasetName = "GHCN-D"
+	// DatasetVersion is the major version of GHCN-D the parsers handle.
+	DatasetVersion = "3"
+	// DatasetDOI is the DOI of the GHCN-D dataset.
+	DatasetDOI = "10.7289/V5D21VHZ"
+
+	// DatasetCitation is the citation NOAA asks users of GHCN-D to include.
+	DatasetCitation = "Menne, M.J., I. Durre, B. Korzeniewski, S. McNeill, K. Thomas, " +
+		"X. Yin, S. Anthony, R. Ray, R.S. Vose, B.E. Gleason, and T.G. Houston (2012): " +
+		"Global Historical Climatology Network - Daily (GHCN-Daily), Version 3. " +
+		"NOAA National Climatic Data Center. doi:10.7289/V5D21VHZ"
+)
+
+var dataset = ds.Dataset{
+	Name:     DatasetName,
+	Version:  DatasetVersion,
+	DOI:      DatasetDOI,
+	License:  noaa.License,
+	Citation: DatasetCitation,
+}
+
+// networks maps the network code, (the third character of a GHCN ID), to the
+// name of the network that station numbering comes from.
+var networks = map[byte]string{
+	'1': "CoCoRaHS",
+	'C': "US COOP",
+	'E': "ECA&D",
+	'M': "WMO",
+	'N': "National Meteorological or Hydrological Center",
+	'R': "RAWS",
+	'S': "SNOTEL",
+	'W': "WBAN/ICAO",
+}
+
+// Attribution returns the Attributions for data from GHCN-D. Retrieved is when
+// the data files were downloaded, and is left out if it is the zero time.
+func Attribution(retrieved time.Time) *ds.Attributions {
+	return dataset.Attributions(retrieved)
+}
+
+// stationAttribution returns the Attributions for the station with the given
+// GHCN ID, adding the network its ID comes from.
+func stationAttribution(id string, retrieved time.Time) *ds.Attributions {
+	a := Attribution(retrieved)
+	if len(id) > 2 {
+		if network, ok := networks[id[2]]; ok {
+			a.Networks = []string{network}
+		}
+	}
+	return a
+}
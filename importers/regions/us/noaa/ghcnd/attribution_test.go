@@ -0,0 +1,62 @@
+package ghcnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+func TestStationAttribution(t *testing.T) {
+	retrieved := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("PDT", -7*3600))
+	tests := []struct {
+		id        string
+		retrieved time.Time
+		want      *ds.Attributions
+	}{
+		{
+			id: "US1CASF0001",
+			want: &ds.Attributions{
+				Datasets:  []string{"GHCN-D"},
+				Versions:  []string{"GHCN-D 3"},
+				DOIs:      []string{"10.7289/V5D21VHZ"},
+				Licenses:  []string{noaa.License},
+				Citations: []string{DatasetCitation},
+				Networks:  []string{"CoCoRaHS"},
+			},
+		},
+		{
+			id:        "USC00045123",
+			retrieved: retrieved,
+			want: &ds.Attributions{
+				Datasets:  []string{"GHCN-D"},
+				Versions:  []string{"GHCN-D 3"},
+				DOIs:      []string{"10.7289/V5D21VHZ"},
+				Licenses:  []string{noaa.License},
+				Citations: []string{DatasetCitation},
+				Retrieved: []string{"GHCN-D 2024-05-01T19:30:00Z"},
+				Networks:  []string{"US COOP"},
+			},
+		},
+		{
+			// Unknown network code.
+			id: "XX000000001",
+			want: &ds.Attributions{
+				Datasets:  []string{"GHCN-D"},
+				Versions:  []string{"GHCN-D 3"},
+				DOIs:      []string{"10.7289/V5D21VHZ"},
+				Licenses:  []string{noaa.License},
+				Citations: []string{DatasetCitation},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, stationAttribution(test.id, test.retrieved)); diff != "" {
+			t.Errorf("stationAttribution(%q) diff (-want +got):\n%s", test.id, diff)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ghcnd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	// dlyLineLength is the length of every row in a .dly file.
+	dlyLineLength = 269
+	// dlyHeaderLength is the number of characters before the first day.
+	dlyHeaderLength = 21
+	// dlyDayLength is the number of characters for each days value and flags.
+	dlyDayLength = 8
+)
+
+// DlyParserFn is an Apache Beam structural DoFn to process rows from a GHCN-D
+// by_station .dly file into Records.
+type DlyParserFn struct {
+}
+
+func init() {
+	register.DoFn2x0[string, func(*Record)](&DlyParserFn{})
+}
+
+// ProcessElement reads one row in and emits a Record for each day in the
+// month that has a value.
+func (fn *DlyParserFn) ProcessElement(line string, emit func(*Record)) {
+	records, err := ParseDlyLine(line)
+	if err != nil {
+		return
+	}
+	for _, r := range records {
+		emit(r)
+	}
+}
+
+// ParseDlyLine parses one row from a .dly file into the Records for each day of
+// the month which has a value. Days with a missing value (-9999), including
+// those past the end of shorter months, are dropped.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme.txt
+//
+//	------------------------------
+//	Variable   Columns   Type
+//	------------------------------
+//	ID            1-11   Character
+//	YEAR         12-15   Integer
+//	MONTH        16-17   Integer
+//	ELEMENT      18-21   Character
+//	VALUE1       22-26   Integer
+//	MFLAG1       27-27   Character
+//	QFLAG1       28-28   Character
+//	SFLAG1       29-29   Character
+//	VALUE2       30-34   Integer
+//	MFLAG2       35-35   Character
+//	QFLAG2       36-36   Character
+//	SFLAG2       37-37   Character
+//	  .           .          .
+//	  .           .          .
+//	  .           .          .
+//	VALUE31    262-266   Integer
+//	MFLAG31    267-267   Character
+//	QFLAG31    268-268   Character
+//	SFLAG31    269-269   Character
+//	------------------------------
+func ParseDlyLine(line string) ([]*Record, error) {
+	if len(line) != dlyLineLength {
+		return nil, fmt.Errorf("ghcnd: .dly row has length %d, want %d", len(line), dlyLineLength)
+	}
+
+	id := strings.TrimSpace(line[0:11])
+	year := utils.ParseIntBounded(line[11:15], 1, 9999, ds.UnsetValue)
+	month := utils.ParseIntBounded(line[15:17], 1, 12, ds.UnsetValue)
+	element := strings.TrimSpace(line[17:21])
+	if len(id) != 11 || year == ds.UnsetValue || month == ds.UnsetValue || len(element) != 4 {
+		return nil, fmt.Errorf("ghcnd: malformed .dly row %q", line[0:dlyHeaderLength])
+	}
+
+	var records []*Record
+	for day := 0; day < 31; day++ {
+		field := line[dlyHeaderLength+day*dlyDayLength : dlyHeaderLength+(day+1)*dlyDayLength]
+
+		value := utils.ParseInt(field[0:5], ds.UnsetValue)
+		if value == ds.UnsetValue {
+			continue
+		}
+
+		records = append(records, &Record{
+			StationID: id,
+			Date:      ds.Date{Year: int(year), Month: int(month), Day: day + 1},
+			Element:   element,
+			Value:     value,
+			MFlag:     strings.TrimSpace(field[5:6]),
+			QFlag:     strings.TrimSpace(field[6:7]),
+			SFlag:     strings.TrimSpace(field[7:8]),
+		})
+	}
+
+	return records, nil
+}
+
+// DlyReader reads the Records from a .dly file without needing a Beam pipeline.
+// Either the plain text or gzip compressed files are accepted.
+//
+// The rows in a .dly file are ordered by element and then by month, so the
+// records for one station day are spread throughout the file. Use
+// CombineRecords on the full set of records to turn them into DailyObservations.
+type DlyReader struct {
+	scanner *bufio.Scanner
+	pending []*Record
+}
+
+// NewDlyReader returns a DlyReader reading from r.
+func NewDlyReader(r io.Reader) (*DlyReader, error) {
+	ur, err := utils.MaybeGunzip(r)
+	if err != nil {
+		return nil, err
+	}
+	return &DlyReader{scanner: bufio.NewScanner(ur)}, nil
+}
+
+// Next returns the next Record in the file. io.EOF is returned once there are
+// no more records. Malformed rows are skipped.
+func (d *DlyReader) Next() (*Record, error) {
+	for len(d.pending) == 0 {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+
+		records, err := ParseDlyLine(d.scanner.Text())
+		if err != nil {
+			continue
+		}
+		d.pending = records
+	}
+
+	r := d.pending[0]
+	d.pending = d.pending[1:]
+	return r, nil
+}
@@ -0,0 +1,220 @@
+package ghcnd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+// dlyLine builds a .dly row for the given month with the given number of days
+// set to value, and the remaining days set to missing.
+func dlyLine(id string, year, month int, element string, days int, value int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-11s%04d%02d%-4s", id, year, month, element)
+	for day := 1; day <= 31; day++ {
+		if day <= days {
+			fmt.Fprintf(&b, "%5d  W", value)
+		} else {
+			fmt.Fprintf(&b, "%5d   ", ds.UnsetValue)
+		}
+	}
+	return b.String()
+}
+
+func TestParseDlyLine(t *testing.T) {
+	tests := []struct {
+		have      string
+		wantCount int
+		wantFirst *Record
+		wantErr   bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "pizza hamburgers",
+			wantErr: true,
+		},
+		{
+			// Month out of range.
+			have:    dlyLine("USW00023234", 2023, 13, "TMAX", 31, 139),
+			wantErr: true,
+		},
+		{
+			// All days missing.
+			have:      dlyLine("USW00023234", 2023, 1, "TMAX", 0, 139),
+			wantCount: 0,
+		},
+		{
+			have:      dlyLine("USW00023234", 2023, 1, "TMAX", 31, 139),
+			wantCount: 31,
+			wantFirst: &Record{
+				StationID: "USW00023234",
+				Date:      ds.Date{Year: 2023, Month: 1, Day: 1},
+				Element:   "TMAX",
+				Value:     139,
+				SFlag:     "W",
+			},
+		},
+		{
+			// February should only have 28 days, the rest are -9999.
+			have:      dlyLine("USW00023234", 2023, 2, "PRCP", 28, -5),
+			wantCount: 28,
+			wantFirst: &Record{
+				StationID: "USW00023234",
+				Date:      ds.Date{Year: 2023, Month: 2, Day: 1},
+				Element:   "PRCP",
+				Value:     -5,
+				SFlag:     "W",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDlyLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseDlyLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if len(got) != test.wantCount {
+			t.Errorf("len(ParseDlyLine(%q)) = %d, want %d", test.have, len(got), test.wantCount)
+			continue
+		}
+		if test.wantFirst == nil {
+			continue
+		}
+		if diff := cmp.Diff(test.wantFirst, got[0]); diff != "" {
+			t.Errorf("ParseDlyLine(%q)[0] = %+v, want %+v\ndiff: %s", test.have, got[0], test.wantFirst, diff)
+		}
+	}
+}
+
+func TestDlyReader(t *testing.T) {
+	have := strings.Join([]string{
+		dlyLine("USW00023234", 2023, 2, "TMAX", 2, 139),
+		"not a dly row",
+		dlyLine("USW00023234", 2023, 2, "TMIN", 1, 89),
+	}, "\n")
+
+	r, err := NewDlyReader(strings.NewReader(have))
+	if err != nil {
+		t.Fatalf("NewDlyReader() error = %v", err)
+	}
+
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		records = append(records, rec)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("DlyReader returned %d records, want 3", len(records))
+	}
+
+	want := []*ds.DailyObservation{
+		{
+			StationID:               "USW00023234",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 2, Day: 1},
+			TempCMin:                8.9,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.9,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                ds.UnsetValue,
+			SnowfallMM:              ds.UnsetValue,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        ds.UnsetValue,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMin": {Quality: ds.QualityPassed, Original: "  W"},
+				"TempCMax": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		},
+		{
+			StationID:               "USW00023234",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 2, Day: 2},
+			TempCMin:                ds.UnsetValue,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.9,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                ds.UnsetValue,
+			SnowfallMM:              ds.UnsetValue,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        ds.UnsetValue,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMax": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		},
+	}
+	got := CombineRecords(records)
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CombineRecords(%v) = %v, want %v\ndiff: %s", records, got, want, diff)
+	}
+}
+
+func TestDlyParserFn(t *testing.T) {
+	beam.Init()
+	pipeline, scope := beam.NewPipelineWithRoot()
+
+	lines := beam.Create(scope,
+		dlyLine("USW00023234", 2023, 2, "SNWD", 28, 0),
+		dlyLine("USW00023234", 2023, 4, "SNWD", 30, 10),
+		"pizza hamburgers")
+	records := beam.ParDo(scope, &DlyParserFn{}, lines)
+	passert.Count(scope, records, "records", 58)
+
+	if err := ptest.Run(pipeline); err != nil {
+		t.Errorf("Failed to execute job: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package ghcnd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// YearParserFn is an Apache Beam structural DoFn to process rows from a GHCN-D
+// by_year file into Records.
+type YearParserFn struct {
+}
+
+func init() {
+	register.DoFn2x0[string, func(*Record)](&YearParserFn{})
+	register.Emitter1[*Record]()
+}
+
+// ProcessElement reads one row in and attempts to convert it into a Record.
+func (fn *YearParserFn) ProcessElement(line string, emit func(*Record)) {
+	r, err := ParseYearLine(line)
+	if err != nil {
+		return
+	}
+	emit(r)
+}
+
+// ParseYearLine parses one row from a by_year file.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme-by_year.txt
+//
+//	ID = 11 character station identification code
+//	YEAR/MONTH/DAY = 8 character date in YYYYMMDD format (e.g. 19860529 = May 29, 1986)
+//	ELEMENT = 4 character indicator of element type
+//	DATA VALUE = 5 character data value for ELEMENT
+//	M-FLAG = 1 character Measurement Flag
+//	Q-FLAG = 1 character Quality Flag
+//	S-FLAG = 1 character Source Flag
+//	OBS-TIME = 4-character time of observation in hour-minute format (i.e. 0700 =7:00 am)
+//
+// e.g.,
+//
+//	USW00023234,20230101,TMAX,139,,,W,2400
+func ParseYearLine(line string) (*Record, error) {
+	parts := strings.Split(strings.TrimSpace(line), ",")
+	if len(parts) != 8 {
+		return nil, fmt.Errorf("ghcnd: by_year row has %d fields, want 8", len(parts))
+	}
+	date, err := ds.ParseDate(parts[1])
+	if err != nil || len(strings.TrimSpace(parts[1])) != 8 {
+		return nil, fmt.Errorf("ghcnd: malformed by_year row %q", line)
+	}
+
+	r := &Record{
+		StationID: strings.TrimSpace(parts[0]),
+		Date:      date,
+		Element:   strings.TrimSpace(parts[2]),
+		Value:     utils.ParseInt(parts[3], ds.UnsetValue),
+		MFlag:     strings.TrimSpace(parts[4]),
+		QFlag:     strings.TrimSpace(parts[5]),
+		SFlag:     strings.TrimSpace(parts[6]),
+		ObsTime:   strings.TrimSpace(parts[7]),
+	}
+
+	if len(r.StationID) != 11 || len(r.Element) != 4 {
+		return nil, fmt.Errorf("ghcnd: malformed by_year row %q", line)
+	}
+	if r.Value == ds.UnsetValue {
+		return nil, fmt.Errorf("ghcnd: missing value in by_year row %q", line)
+	}
+
+	return r, nil
+}
+
+// YearReader reads DailyObservations from a by_year file without needing a
+// Beam pipeline. Either the plain text or gzip compressed files are accepted.
+//
+// The by_year files keep all the rows for a given station day together, so the
+// reader only holds on to one station day at a time. Rows for a station day
+// that are not contiguous in the input are returned as separate observations.
+type YearReader struct {
+	scanner *bufio.Scanner
+	pending *Record
+}
+
+// NewYearReader returns a YearReader reading from r.
+func NewYearReader(r io.Reader) (*YearReader, error) {
+	ur, err := utils.MaybeGunzip(r)
+	if err != nil {
+		return nil, err
+	}
+	return &YearReader{scanner: bufio.NewScanner(ur)}, nil
+}
+
+// Next returns the next DailyObservation in the file. io.EOF is returned
+// once there are no more observations. Malformed rows are skipped.
+func (y *YearReader) Next() (*ds.DailyObservation, error) {
+	var obs *ds.DailyObservation
+	if y.pending != nil {
+		obs = newDailyObservation(y.pending)
+		y.pending = nil
+	}
+
+	for y.scanner.Scan() {
+		r, err := ParseYearLine(y.scanner.Text())
+		if err != nil {
+			continue
+		}
+
+		if obs == nil {
+			obs = newDailyObservation(r)
+			continue
+		}
+
+		if r.StationID != obs.StationID || r.Date != obs.Date {
+			y.pending = r
+			return obs, nil
+		}
+		applyRecord(obs, r)
+	}
+
+	if err := y.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if obs == nil {
+		return nil, io.EOF
+	}
+	return obs, nil
+}
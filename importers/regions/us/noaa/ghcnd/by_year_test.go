@@ -0,0 +1,308 @@
+package ghcnd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+// A small snippet of a by_year file covering two station days.
+const yearRows = `USW00023234,20230101,TMAX,139,,,W,2400
+USW00023234,20230101,TMIN,89,,,W,2400
+USW00023234,20230101,PRCP,56,,,W,2400
+USW00023234,20230101,SNOW,0,,,W,
+USW00023234,20230101,AWND,45,,,W,
+USW00094728,20230101,TMAX,133,,,W,2400
+USW00094728,20230101,TMIN,72,,X,W,2400
+`
+
+func TestParseYearLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *Record
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "pizza,hamburgers",
+			wantErr: true,
+		},
+		{
+			// Missing data value.
+			have:    "USW00023234,20230101,TMAX,,,,W,2400",
+			wantErr: true,
+		},
+		{
+			// Station ID too short.
+			have:    "USW0002323,20230101,TMAX,139,,,W,2400",
+			wantErr: true,
+		},
+		{
+			// Invalid date.
+			have:    "USW00023234,20230230,TMAX,139,,,W,2400",
+			wantErr: true,
+		},
+		{
+			// Date not in the YYYYMMDD format.
+			have:    "USW00023234,2023-01-01,TMAX,139,,,W,2400",
+			wantErr: true,
+		},
+		{
+			have: "USW00023234,20230101,TMAX,139,,,W,2400",
+			want: &Record{
+				StationID: "USW00023234",
+				Date:      ds.Date{Year: 2023, Month: 1, Day: 1},
+				Element:   "TMAX",
+				Value:     139,
+				SFlag:     "W",
+				ObsTime:   "2400",
+			},
+		},
+		{
+			have: "ASN00015643,18900101,PRCP,-12,T,O,a,",
+			want: &Record{
+				StationID: "ASN00015643",
+				Date:      ds.Date{Year: 1890, Month: 1, Day: 1},
+				Element:   "PRCP",
+				Value:     -12,
+				MFlag:     "T",
+				QFlag:     "O",
+				SFlag:     "a",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseYearLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseYearLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseYearLine(%q) = %+v, want %+v\ndiff: %s", test.have, got, test.want, diff)
+		}
+	}
+}
+
+func TestYearReader(t *testing.T) {
+	want := []*ds.DailyObservation{
+		{
+			StationID:               "USW00023234",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+			TempCMin:                8.9,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.9,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                5.6,
+			SnowfallMM:              0,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        4.5,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMin":         {Quality: ds.QualityPassed, Original: "  W"},
+				"TempCMax":         {Quality: ds.QualityPassed, Original: "  W"},
+				"PrecipMM":         {Quality: ds.QualityPassed, Original: "  W"},
+				"SnowfallMM":       {Quality: ds.QualityPassed, Original: "  W"},
+				"WindSpeedMPSMean": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		},
+		{
+			// The TMIN value failed a quality check so it is kept, but flagged.
+			StationID:               "USW00094728",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+			TempCMin:                7.2,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.3,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                ds.UnsetValue,
+			SnowfallMM:              ds.UnsetValue,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        ds.UnsetValue,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMin": {Quality: ds.QualityFailed, Original: " XW"},
+				"TempCMax": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		},
+	}
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(yearRows))
+	w.Close()
+
+	tests := []struct {
+		name string
+		have io.Reader
+	}{
+		{
+			name: "plain text",
+			have: strings.NewReader(yearRows),
+		},
+		{
+			name: "gzip",
+			have: &gz,
+		},
+	}
+
+	for _, test := range tests {
+		r, err := NewYearReader(test.have)
+		if err != nil {
+			t.Fatalf("%s: NewYearReader() error = %v", test.name, err)
+		}
+
+		var got []*ds.DailyObservation
+		for {
+			obs, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: Next() error = %v", test.name, err)
+			}
+			got = append(got, obs)
+		}
+
+		if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+			t.Errorf("%s: YearReader = %+v\ndiff: %s", test.name, got, diff)
+		}
+	}
+}
+
+func TestDailyObservations(t *testing.T) {
+	beam.Init()
+	pipeline, scope := beam.NewPipelineWithRoot()
+
+	var have []any
+	for _, line := range strings.Split(yearRows, "\n") {
+		have = append(have, line)
+	}
+	lines := beam.Create(scope, have...)
+	records := beam.ParDo(scope, &YearParserFn{}, lines)
+	obs := DailyObservations(scope, records)
+
+	passert.Equals(scope, obs,
+		&ds.DailyObservation{
+			StationID:               "USW00023234",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+			TempCMin:                8.9,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.9,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                5.6,
+			SnowfallMM:              0,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        4.5,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMin":         {Quality: ds.QualityPassed, Original: "  W"},
+				"TempCMax":         {Quality: ds.QualityPassed, Original: "  W"},
+				"PrecipMM":         {Quality: ds.QualityPassed, Original: "  W"},
+				"SnowfallMM":       {Quality: ds.QualityPassed, Original: "  W"},
+				"WindSpeedMPSMean": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		},
+		&ds.DailyObservation{
+			StationID:               "USW00094728",
+			Source:                  DatasetName,
+			License:                 noaa.License,
+			Citation:                DatasetCitation,
+			Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+			TempCMin:                7.2,
+			TempCMean:               ds.UnsetValue,
+			TempCMax:                13.3,
+			DewPointCMean:           ds.UnsetValue,
+			RelativeHumidityMean:    ds.UnsetValue,
+			PrecipMM:                ds.UnsetValue,
+			SnowfallMM:              ds.UnsetValue,
+			SnowDepthMM:             ds.UnsetValue,
+			WindSpeedMPSMean:        ds.UnsetValue,
+			WindSpeedMPSMax:         ds.UnsetValue,
+			WindDirectionDegMax:     ds.UnsetValue,
+			WindGustMPSMax:          ds.UnsetValue,
+			PressureStationHPaMean:  ds.UnsetValue,
+			PressureSeaLevelHPaMean: ds.UnsetValue,
+			VisibilityMMean:         ds.UnsetValue,
+			SunshineMinutes:         ds.UnsetValue,
+			SunshinePercent:         ds.UnsetValue,
+			TempCount:               ds.UnsetValue,
+			DewPointCount:           ds.UnsetValue,
+			PressureStationCount:    ds.UnsetValue,
+			PressureSeaLevelCount:   ds.UnsetValue,
+			VisibilityCount:         ds.UnsetValue,
+			WindSpeedCount:          ds.UnsetValue,
+			Flags: ds.Flags{
+				"TempCMin": {Quality: ds.QualityFailed, Original: " XW"},
+				"TempCMax": {Quality: ds.QualityPassed, Original: "  W"},
+			},
+		})
+
+	if err := ptest.Run(pipeline); err != nil {
+		t.Errorf("Failed to execute job: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package ghcnd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+)
+
+// Country is one row from the GHCN-D countries file.
+type Country struct {
+	// FIPS is the FIPS 10-4 country code, the same as the first two
+	// characters of the GHCN ID.
+	FIPS string
+	Name string
+}
+
+// CountryParserFn is an Apache Beam structural DoFn to process rows from the
+// GHCN-D countries file.
+type CountryParserFn struct {
+}
+
+func init() {
+	register.DoFn2x0[string, func(*Country)](&CountryParserFn{})
+	register.Emitter1[*Country]()
+}
+
+// ProcessElement reads one row in and attempts to convert it into a Country.
+func (fn *CountryParserFn) ProcessElement(line string, emit func(*Country)) {
+	c, err := ParseCountryLine(line)
+	if err != nil {
+		return
+	}
+	emit(c)
+}
+
+// ParseCountryLine parses one row from ghcnd-countries.txt.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme.txt
+//
+// V. FORMAT OF "ghcnd-countries.txt"
+//
+//	------------------------------
+//	Variable   Columns   Type
+//	------------------------------
+//	CODE          1-2    Character
+//	NAME         4-64    Character
+//	------------------------------
+//
+// CODE is the FIPS country code of the country where the station is located
+// (from FIPS Publication 10-4 at www.cia.gov/cia/publications/factbook/appendix/appendix-d.html).
+func ParseCountryLine(line string) (*Country, error) {
+	if len(line) < 4 || line[2] != ' ' {
+		return nil, fmt.Errorf("ghcnd: malformed country row %q", line)
+	}
+
+	c := &Country{
+		FIPS: strings.TrimSpace(line[0:2]),
+		Name: strings.TrimSpace(line[3:]),
+	}
+	if len(c.FIPS) != 2 || c.Name == "" {
+		return nil, fmt.Errorf("ghcnd: malformed country row %q", line)
+	}
+	return c, nil
+}
@@ -0,0 +1,47 @@
+package ghcnd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCountryLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *Country
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "US",
+			wantErr: true,
+		},
+		{
+			have:    "USA United States",
+			wantErr: true,
+		},
+		{
+			have: "US United States",
+			want: &Country{FIPS: "US", Name: "United States"},
+		},
+		{
+			have: "CG Congo (Kinshasa)                                          ",
+			want: &Country{FIPS: "CG", Name: "Congo (Kinshasa)"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseCountryLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseCountryLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseCountryLine(%q) = %+v, want %+v\ndiff: %s", test.have, got, test.want, diff)
+		}
+	}
+}
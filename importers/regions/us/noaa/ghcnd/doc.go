@@ -5,6 +5,7 @@ reporting station and observational data.
 Data files are located:
 
 	https://www.ncei.noaa.gov/pub/data/ghcn/daily/by_year/
+	https://www.ncei.noaa.gov/pub/data/ghcn/daily/all/
 
 File format documentation:
 
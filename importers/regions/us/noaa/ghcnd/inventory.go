@@ -0,0 +1,100 @@
+package ghcnd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// InventoryParserFn is an Apache Beam structural DoFn to process rows from the
+// GHCN-D inventory file into the element coverage keyed by GHCN ID.
+type InventoryParserFn struct {
+}
+
+func init() {
+	register.DoFn2x0[string, func(string, *ds.ElementCoverage)](&InventoryParserFn{})
+	register.Emitter2[string, *ds.ElementCoverage]()
+}
+
+// ProcessElement reads one row in and attempts to convert it into an ElementCoverage.
+func (fn *InventoryParserFn) ProcessElement(line string, emit func(string, *ds.ElementCoverage)) {
+	id, coverage, err := ParseInventoryLine(line)
+	if err != nil {
+		return
+	}
+	emit(id, coverage)
+}
+
+// ParseInventoryLine parses one row from ghcnd-inventory.txt returning the GHCN
+// ID of the station and the coverage for the element.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme.txt
+//
+// VII. FORMAT OF "ghcnd-inventory.txt"
+//
+//	------------------------------
+//	Variable   Columns   Type
+//	------------------------------
+//	ID            1-11   Character
+//	LATITUDE     13-20   Real
+//	LONGITUDE    22-30   Real
+//	ELEMENT      32-35   Character
+//	FIRSTYEAR    37-40   Integer
+//	LASTYEAR     42-45   Integer
+//	------------------------------
+//
+// The latitude and longitude are the same as in ghcnd-stations.txt so they are
+// not returned.
+func ParseInventoryLine(line string) (string, *ds.ElementCoverage, error) {
+	if len(line) != 45 {
+		return "", nil, fmt.Errorf("ghcnd: inventory row has length %d, want 45", len(line))
+	}
+
+	id := strings.TrimSpace(line[0:11])
+	coverage := &ds.ElementCoverage{
+		Element:   strings.TrimSpace(line[31:35]),
+		FirstYear: int32(utils.ParseIntBounded(line[36:40], 1, 9999, ds.UnsetValue)),
+		LastYear:  int32(utils.ParseIntBounded(line[41:45], 1, 9999, ds.UnsetValue)),
+	}
+
+	if len(id) != 11 || len(coverage.Element) != 4 ||
+		coverage.FirstYear == ds.UnsetValue || coverage.LastYear == ds.UnsetValue ||
+		coverage.FirstYear > coverage.LastYear {
+		return "", nil, fmt.Errorf("ghcnd: malformed inventory row %q", line)
+	}
+
+	return id, coverage, nil
+}
+
+// ApplyCoverage sets the stations element coverage and updates its start and end
+// dates to cover the full period of record across all the elements. The inventory
+// only has years, so the dates span from the start of the first year through to
+// the end of the last year.
+func ApplyCoverage(s *ds.Station, coverage []*ds.ElementCoverage) {
+	if len(coverage) == 0 {
+		return
+	}
+
+	s.Coverage = append([]*ds.ElementCoverage(nil), coverage...)
+	sort.Slice(s.Coverage, func(i, j int) bool {
+		return s.Coverage[i].Element < s.Coverage[j].Element
+	})
+
+	first, last := coverage[0].FirstYear, coverage[0].LastYear
+	for _, c := range coverage[1:] {
+		if c.FirstYear < first {
+			first = c.FirstYear
+		}
+		if c.LastYear > last {
+			last = c.LastYear
+		}
+	}
+
+	s.StartDate = ds.Date{Year: int(first), Month: 1, Day: 1}
+	s.EndDate = ds.Date{Year: int(last), Month: 12, Day: 31}
+}
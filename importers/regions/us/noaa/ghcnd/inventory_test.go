@@ -0,0 +1,118 @@
+package ghcnd
+
+import (
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestParseInventoryLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		wantID  string
+		wantCov *ds.ElementCoverage
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "pizza hamburgers",
+			wantErr: true,
+		},
+		{
+			// Last year before first year.
+			have:    "USW00023234  37.6197 -122.3656 TMAX 2023 1945",
+			wantErr: true,
+		},
+		{
+			// Missing year.
+			have:    "USW00023234  37.6197 -122.3656 TMAX      1945",
+			wantErr: true,
+		},
+		{
+			have:   "USW00023234  37.6197 -122.3656 TMAX 1945 2023",
+			wantID: "USW00023234",
+			wantCov: &ds.ElementCoverage{
+				Element:   "TMAX",
+				FirstYear: 1945,
+				LastYear:  2023,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		id, cov, err := ParseInventoryLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseInventoryLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if id != test.wantID {
+			t.Errorf("ParseInventoryLine(%q) id = %q, want %q", test.have, id, test.wantID)
+		}
+		if diff := cmp.Diff(test.wantCov, cov); diff != "" {
+			t.Errorf("ParseInventoryLine(%q) = %+v, want %+v\ndiff: %s", test.have, cov, test.wantCov, diff)
+		}
+	}
+}
+
+func TestApplyCoverage(t *testing.T) {
+	tests := []struct {
+		have []*ds.ElementCoverage
+		want *ds.Station
+	}{
+		{
+			have: nil,
+			want: ds.EmptyStation(),
+		},
+		{
+			have: []*ds.ElementCoverage{
+				{Element: "TMIN", FirstYear: 1945, LastYear: 2023},
+				{Element: "PRCP", FirstYear: 1893, LastYear: 2022},
+				{Element: "TMAX", FirstYear: 1945, LastYear: 2023},
+			},
+			want: &ds.Station{
+				Identifiers:  &ds.Identifiers{},
+				Geography:    &ds.Geography{},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 1893, Month: 1, Day: 1},
+				EndDate:      ds.Date{Year: 2023, Month: 12, Day: 31},
+				Coverage: []*ds.ElementCoverage{
+					{Element: "PRCP", FirstYear: 1893, LastYear: 2022},
+					{Element: "TMAX", FirstYear: 1945, LastYear: 2023},
+					{Element: "TMIN", FirstYear: 1945, LastYear: 2023},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := ds.EmptyStation()
+		ApplyCoverage(got, test.have)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ApplyCoverage(%v) = %+v, want %+v\ndiff: %s", test.have, got, test.want, diff)
+		}
+	}
+}
+
+func TestInventoryParserFn(t *testing.T) {
+	beam.Init()
+	pipeline, scope := beam.NewPipelineWithRoot()
+
+	lines := beam.Create(scope,
+		"USW00023234  37.6197 -122.3656 TMAX 1945 2023",
+		"USW00023234  37.6197 -122.3656 PRCP 1893 2023",
+		"pizza hamburgers")
+	coverage := beam.ParDo(scope, &InventoryParserFn{}, lines)
+	passert.Count(scope, coverage, "coverage", 2)
+
+	if err := ptest.Run(pipeline); err != nil {
+		t.Errorf("Failed to execute job: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package ghcnd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/units"
+)
+
+// The core GHCN-D elements that are converted into DailyObservation fields.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme.txt
+const (
+	ElementPrecip    = "PRCP" // Precipitation (tenths of mm)
+	ElementSnowfall  = "SNOW" // Snowfall (mm)
+	ElementSnowDepth = "SNWD" // Snow depth (mm)
+	ElementTempMax   = "TMAX" // Maximum temperature (tenths of degrees C)
+	ElementTempMin   = "TMIN" // Minimum temperature (tenths of degrees C)
+	ElementTempAvg   = "TAVG" // Average temperature (tenths of degrees C)
+
+	ElementDewPointAvg         = "ADPT" // Average dew point temperature (tenths of degrees C)
+	ElementHumidityAvg         = "RHAV" // Average relative humidity (percent)
+	ElementPressureSeaLevelAvg = "ASLP" // Average sea level pressure (tenths of hPa)
+	ElementPressureStationAvg  = "ASTP" // Average station level pressure (tenths of hPa)
+	ElementWindSpeedAvg        = "AWND" // Average wind speed (tenths of meters per second)
+	ElementWindSpeedFastest2   = "WSF2" // Fastest 2-minute wind speed (tenths of meters per second)
+	ElementWindDirFastest2     = "WDF2" // Direction of fastest 2-minute wind (degrees)
+	ElementWindGustPeak        = "WSFG" // Peak gust wind speed (tenths of meters per second)
+	ElementSunshineTotal       = "TSUN" // Daily total sunshine (minutes)
+	ElementSunshinePercent     = "PSUN" // Daily percent of possible sunshine (percent)
+)
+
+// Record is the value of a single element for one station on one day, exactly
+// as it appears in the GHCN-D source files. The value is left in the units of
+// the element (e.g., tenths of degrees C) and is only converted when the
+// records are combined into a DailyObservation.
+type Record struct {
+	StationID string
+	Date      ds.Date
+	Element   string
+	Value     int64
+	MFlag     string
+	QFlag     string
+	SFlag     string
+	ObsTime   string // Observation time in HHMM format, if given.
+}
+
+func init() {
+	register.Function1x2(recordKeyFn)
+	register.DoFn3x0[string, func(**Record) bool, func(*ds.DailyObservation)](&combineRecordsFn{})
+	register.Iter1[*Record]()
+	register.Emitter1[*ds.DailyObservation]()
+}
+
+// DailyObservations groups the GHCN-D records in the given PCollection<*Record>
+// by station and day, and returns a PCollection<*ds.DailyObservation> with one
+// entry for each station day.
+func DailyObservations(s beam.Scope, records beam.PCollection) beam.PCollection {
+	s = s.Scope("ghcnd.DailyObservations")
+	keyed := beam.ParDo(s, recordKeyFn, records)
+	grouped := beam.GroupByKey(s, keyed)
+	return beam.ParDo(s, &combineRecordsFn{}, grouped)
+}
+
+// recordKeyFn keys the record by its station and date.
+func recordKeyFn(r *Record) (string, *Record) {
+	return r.StationID + "," + r.Date.String(), r
+}
+
+// combineRecordsFn merges all the records for one station day together.
+type combineRecordsFn struct {
+}
+
+// ProcessElement combines all the records for the given station day into one
+// DailyObservation.
+func (fn *combineRecordsFn) ProcessElement(_ string, iter func(**Record) bool, emit func(*ds.DailyObservation)) {
+	var obs *ds.DailyObservation
+	var r *Record
+	for iter(&r) {
+		if obs == nil {
+			obs = newDailyObservation(r)
+			continue
+		}
+		applyRecord(obs, r)
+	}
+
+	if obs != nil {
+		emit(obs)
+	}
+}
+
+// CombineRecords groups the given records by station and day, and returns one
+// DailyObservation for each station day, sorted by station and then date.
+//
+// This is the in memory equivalent of DailyObservations for use when the
+// records fit in memory, such as those from one stations .dly file.
+func CombineRecords(records []*Record) []*ds.DailyObservation {
+	days := make(map[string]*ds.DailyObservation)
+	for _, r := range records {
+		key, _ := recordKeyFn(r)
+		if obs, ok := days[key]; ok {
+			applyRecord(obs, r)
+			continue
+		}
+		days[key] = newDailyObservation(r)
+	}
+
+	obs := make([]*ds.DailyObservation, 0, len(days))
+	for _, o := range days {
+		obs = append(obs, o)
+	}
+	sort.Slice(obs, func(i, j int) bool {
+		if obs[i].StationID != obs[j].StationID {
+			return obs[i].StationID < obs[j].StationID
+		}
+		return obs[i].Date.Before(obs[j].Date)
+	})
+	return obs
+}
+
+// newDailyObservation starts a new DailyObservation from the given record.
+func newDailyObservation(r *Record) *ds.DailyObservation {
+	obs := ds.EmptyDailyObservation()
+	obs.StationID = r.StationID
+	obs.Source = DatasetName
+	obs.License = dataset.License
+	obs.Citation = dataset.Citation
+	obs.Date = r.Date
+	applyRecord(obs, r)
+	return obs
+}
+
+// elementField describes where an elements value goes in a DailyObservation.
+type elementField struct {
+	// name is the name of the field, used for its quality Flag.
+	name string
+	// from is the unit of the elements values, and to the unit of the field.
+	from, to units.Unit
+	// field returns the field in the observation.
+	field func(*ds.DailyObservation) *float64
+}
+
+// elementFields maps each of the tracked elements to its field.
+var elementFields = map[string]elementField{
+	ElementTempMax:             {"TempCMax", units.TenthsCelsius, units.Celsius, func(o *ds.DailyObservation) *float64 { return &o.TempCMax }},
+	ElementTempMin:             {"TempCMin", units.TenthsCelsius, units.Celsius, func(o *ds.DailyObservation) *float64 { return &o.TempCMin }},
+	ElementTempAvg:             {"TempCMean", units.TenthsCelsius, units.Celsius, func(o *ds.DailyObservation) *float64 { return &o.TempCMean }},
+	ElementPrecip:              {"PrecipMM", units.TenthsMillimeters, units.Millimeters, func(o *ds.DailyObservation) *float64 { return &o.PrecipMM }},
+	ElementSnowfall:            {"SnowfallMM", units.Millimeters, units.Millimeters, func(o *ds.DailyObservation) *float64 { return &o.SnowfallMM }},
+	ElementSnowDepth:           {"SnowDepthMM", units.Millimeters, units.Millimeters, func(o *ds.DailyObservation) *float64 { return &o.SnowDepthMM }},
+	ElementDewPointAvg:         {"DewPointCMean", units.TenthsCelsius, units.Celsius, func(o *ds.DailyObservation) *float64 { return &o.DewPointCMean }},
+	ElementHumidityAvg:         {"RelativeHumidityMean", units.Percent, units.Percent, func(o *ds.DailyObservation) *float64 { return &o.RelativeHumidityMean }},
+	ElementPressureSeaLevelAvg: {"PressureSeaLevelHPaMean", units.TenthsHectopascals, units.Hectopascals, func(o *ds.DailyObservation) *float64 { return &o.PressureSeaLevelHPaMean }},
+	ElementPressureStationAvg:  {"PressureStationHPaMean", units.TenthsHectopascals, units.Hectopascals, func(o *ds.DailyObservation) *float64 { return &o.PressureStationHPaMean }},
+	ElementWindSpeedAvg:        {"WindSpeedMPSMean", units.TenthsMetersPerSecond, units.MetersPerSecond, func(o *ds.DailyObservation) *float64 { return &o.WindSpeedMPSMean }},
+	ElementWindSpeedFastest2:   {"WindSpeedMPSMax", units.TenthsMetersPerSecond, units.MetersPerSecond, func(o *ds.DailyObservation) *float64 { return &o.WindSpeedMPSMax }},
+	ElementWindDirFastest2:     {"WindDirectionDegMax", units.Degrees, units.Degrees, func(o *ds.DailyObservation) *float64 { return &o.WindDirectionDegMax }},
+	ElementWindGustPeak:        {"WindGustMPSMax", units.TenthsMetersPerSecond, units.MetersPerSecond, func(o *ds.DailyObservation) *float64 { return &o.WindGustMPSMax }},
+	ElementSunshineTotal:       {"SunshineMinutes", units.Minutes, units.Minutes, func(o *ds.DailyObservation) *float64 { return &o.SunshineMinutes }},
+	ElementSunshinePercent:     {"SunshinePercent", units.Percent, units.Percent, func(o *ds.DailyObservation) *float64 { return &o.SunshinePercent }},
+}
+
+// applyRecord converts the records value into SI units and sets it on the
+// matching field of the observation along with its quality Flag. Elements
+// which are not tracked in the DailyObservation are ignored.
+func applyRecord(obs *ds.DailyObservation, r *Record) {
+	if r.Value == ds.UnsetValue {
+		return
+	}
+	f, ok := elementFields[r.Element]
+	if !ok {
+		return
+	}
+
+	*f.field(obs) = units.MustConvert(float64(r.Value), f.from, f.to)
+	obs.Flags.Set(f.name, recordFlag(r))
+}
+
+// recordFlag returns the normalized quality Flag for the record.
+//
+// Any Q-FLAG means the value failed one of the quality assurance checks. The
+// M-FLAG marks trace precipitation, and values presumed to be zero which were
+// never actually measured. The S-FLAG only gives the source of the value.
+func recordFlag(r *Record) *ds.Flag {
+	q := ds.QualityPassed
+	switch {
+	case r.QFlag != "":
+		q = ds.QualityFailed
+	case r.MFlag == "T":
+		q = ds.QualityTrace
+	case r.MFlag == "P":
+		q = ds.QualityEstimated
+	}
+	return &ds.Flag{
+		Quality:  q,
+		Original: fmt.Sprintf("%1s%1s%1s", r.MFlag, r.QFlag, r.SFlag),
+	}
+}
@@ -0,0 +1,99 @@
+package ghcnd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+	"github.com/rsned/weather/importers/units"
+)
+
+func TestCombineRecordsElements(t *testing.T) {
+	record := func(element string, value int64, qflag string) *Record {
+		return &Record{StationID: "USW00023234", Date: ds.Date{Year: 2023, Month: 1, Day: 1}, Element: element, Value: value, QFlag: qflag}
+	}
+	records := []*Record{
+		record(ElementTempAvg, 112, ""),
+		record(ElementDewPointAvg, 67, ""),
+		record(ElementHumidityAvg, 78, ""),
+		record(ElementPressureSeaLevelAvg, 10153, ""),
+		record(ElementPressureStationAvg, 10149, ""),
+		record(ElementWindSpeedAvg, 45, ""),
+		record(ElementWindSpeedFastest2, 112, ""),
+		record(ElementWindDirFastest2, 290, ""),
+		record(ElementWindGustPeak, 161, ""),
+		record(ElementSunshineTotal, 312, ""),
+		record(ElementSunshinePercent, 55, ""),
+		// Failed quality checks, so kept but flagged.
+		record(ElementTempMax, 999, "X"),
+		// Trace precipitation.
+		{StationID: "USW00023234", Date: ds.Date{Year: 2023, Month: 1, Day: 1}, Element: ElementPrecip, Value: 0, MFlag: "T", SFlag: "W"},
+		// Presumed zero.
+		{StationID: "USW00023234", Date: ds.Date{Year: 2023, Month: 1, Day: 1}, Element: ElementSnowfall, Value: 0, MFlag: "P"},
+		// Not a tracked element.
+		record("WT01", 1, ""),
+	}
+
+	want := &ds.DailyObservation{
+		StationID:               "USW00023234",
+		Source:                  DatasetName,
+		License:                 noaa.License,
+		Citation:                DatasetCitation,
+		Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+		TempCMin:                ds.UnsetValue,
+		TempCMean:               11.2,
+		TempCMax:                99.9,
+		DewPointCMean:           6.7,
+		RelativeHumidityMean:    78,
+		PrecipMM:                0,
+		SnowfallMM:              0,
+		SnowDepthMM:             ds.UnsetValue,
+		WindSpeedMPSMean:        4.5,
+		WindSpeedMPSMax:         11.2,
+		WindDirectionDegMax:     290,
+		WindGustMPSMax:          16.1,
+		PressureStationHPaMean:  1014.9,
+		PressureSeaLevelHPaMean: 1015.3,
+		VisibilityMMean:         ds.UnsetValue,
+		SunshineMinutes:         312,
+		SunshinePercent:         55,
+		TempCount:               ds.UnsetValue,
+		DewPointCount:           ds.UnsetValue,
+		PressureStationCount:    ds.UnsetValue,
+		PressureSeaLevelCount:   ds.UnsetValue,
+		VisibilityCount:         ds.UnsetValue,
+		WindSpeedCount:          ds.UnsetValue,
+		Flags: ds.Flags{
+			"TempCMean":               {Quality: ds.QualityPassed, Original: "   "},
+			"TempCMax":                {Quality: ds.QualityFailed, Original: " X "},
+			"DewPointCMean":           {Quality: ds.QualityPassed, Original: "   "},
+			"RelativeHumidityMean":    {Quality: ds.QualityPassed, Original: "   "},
+			"PrecipMM":                {Quality: ds.QualityTrace, Original: "T W"},
+			"SnowfallMM":              {Quality: ds.QualityEstimated, Original: "P  "},
+			"WindSpeedMPSMean":        {Quality: ds.QualityPassed, Original: "   "},
+			"WindSpeedMPSMax":         {Quality: ds.QualityPassed, Original: "   "},
+			"WindDirectionDegMax":     {Quality: ds.QualityPassed, Original: "   "},
+			"WindGustMPSMax":          {Quality: ds.QualityPassed, Original: "   "},
+			"PressureStationHPaMean":  {Quality: ds.QualityPassed, Original: "   "},
+			"PressureSeaLevelHPaMean": {Quality: ds.QualityPassed, Original: "   "},
+			"SunshineMinutes":         {Quality: ds.QualityPassed, Original: "   "},
+			"SunshinePercent":         {Quality: ds.QualityPassed, Original: "   "},
+		},
+	}
+
+	got := CombineRecords(records)
+	if diff := cmp.Diff([]*ds.DailyObservation{want}, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CombineRecords() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestElementFieldUnits(t *testing.T) {
+	for element, f := range elementFields {
+		if _, err := units.Convert(1, f.from, f.to); err != nil {
+			t.Errorf("elementFields[%q] has mismatched units: %v", element, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ghcnd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+)
+
+// State is one row from the GHCN-D states file.
+type State struct {
+	// Code is the postal code for the US State or Canadian Province.
+	Code string
+	Name string
+}
+
+// StateParserFn is an Apache Beam structural DoFn to process rows from the
+// GHCN-D states file.
+type StateParserFn struct {
+}
+
+func init() {
+	register.DoFn2x0[string, func(*State)](&StateParserFn{})
+	register.Emitter1[*State]()
+}
+
+// ProcessElement reads one row in and attempts to convert it into a State.
+func (fn *StateParserFn) ProcessElement(line string, emit func(*State)) {
+	s, err := ParseStateLine(line)
+	if err != nil {
+		return
+	}
+	emit(s)
+}
+
+// ParseStateLine parses one row from ghcnd-states.txt.
+//
+// https://www.ncei.noaa.gov/pub/data/ghcn/daily/readme.txt
+//
+// VI. FORMAT OF "ghcnd-states.txt"
+//
+//	------------------------------
+//	Variable   Columns   Type
+//	------------------------------
+//	CODE          1-2    Character
+//	NAME         4-50    Character
+//	------------------------------
+//
+// The codes are the same as those used in the STATE column of ghcnd-stations.txt
+// and can be converted to ISO 3166-2 with geography.SubdivisionFor.
+func ParseStateLine(line string) (*State, error) {
+	if len(line) < 4 || line[2] != ' ' {
+		return nil, fmt.Errorf("ghcnd: malformed state row %q", line)
+	}
+
+	s := &State{
+		Code: strings.TrimSpace(line[0:2]),
+		Name: strings.TrimSpace(line[3:]),
+	}
+	if len(s.Code) != 2 || s.Name == "" {
+		return nil, fmt.Errorf("ghcnd: malformed state row %q", line)
+	}
+	return s, nil
+}
@@ -0,0 +1,43 @@
+package ghcnd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStateLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *State
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    "CAL CALIFORNIA",
+			wantErr: true,
+		},
+		{
+			have: "CA CALIFORNIA",
+			want: &State{Code: "CA", Name: "CALIFORNIA"},
+		},
+		{
+			have: "NL NEWFOUNDLAND AND LABRADOR                      ",
+			want: &State{Code: "NL", Name: "NEWFOUNDLAND AND LABRADOR"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseStateLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseStateLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseStateLine(%q) = %+v, want %+v\ndiff: %s", test.have, got, test.want, diff)
+		}
+	}
+}
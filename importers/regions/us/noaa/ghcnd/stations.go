@@ -2,15 +2,23 @@ package ghcnd
 
 import (
 	"strings"
+	"time"
 
 	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
 	"github.com/rsned/weather/importers/utils"
 
 	ds "github.com/rsned/weather/datastructures"
 )
 
+// SourceName identifies the GHCN-D stations when merging them with other sources.
+const SourceName = "ghcnd"
+
 // StationParserFn is an Apache Beam structural DoFn to process rows from a GHCN-D staton file.
 type StationParserFn struct {
+	// Retrieved is when the stations file was downloaded, for the stations
+	// Attributions. It is left out if it is the zero time.
+	Retrieved time.Time
 }
 
 func init() {
@@ -54,25 +62,35 @@ func (s *StationParserFn) ProcessElement(line string, emit func(*ds.Station)) {
 	// The first character of the ID relates the ID to other ID systems as well.
 	//
 	station.Identifiers.GhcnID = strings.TrimSpace(line[0:11])
+	station.Attributions = stationAttribution(station.Identifiers.GhcnID, s.Retrieved)
 
 	// TODO(rsned): Build tool to parse and convert these into a standardized form.
 	// LATITUDE   is latitude of the station (in decimal degrees).
 	// LONGITUDE  is the longitude of the station (in decimal degrees).
 	station.Geography.Lat = float32(utils.ParseFloat(line[12:20], 0))
 	station.Geography.Lng = float32(utils.ParseFloat(line[21:30], 0))
+	geography.Normalize(station.Geography)
 
 	// ELEVATION  is the elevation of the station (in meters, missing = -999.9).
-	station.Geography.ElevationMeters = int32(utils.ParseFloat(line[31:37], -9999))
+	if elev := utils.ParseFloat(line[31:37], ds.UnsetValue); elev > -999 {
+		station.Geography.ElevationMeters = int32(elev)
+	} else {
+		station.Geography.ElevationMeters = ds.UnsetValue
+	}
 
-	// TODO(rsned): Update these to be dynamic based on the actual rows values.
-	station.Geography.Continent = "North America"
-	station.Geography.MetaRegion = "NA"
-	station.Geography.RegionCode = "US"
-	station.Geography.RegionName = "United States"
+	// The first two characters of the ID are the FIPS country code.
+	if region, ok := geography.RegionForFIPS(line[0:2]); ok {
+		region.Apply(station.Geography)
+	}
 
-	// TODO(rsned): Convert this to ISO 3166-2 form.
 	// STATE      is the U.S. postal code for the state (for U.S. stations only).
-	station.Geography.Subdivision1Code = strings.TrimSpace(line[38:40])
+	//
+	// Canadian stations also use the postal code for their Province. Codes
+	// that don't map to an ISO 3166-2 subdivision in the stations region
+	// are dropped.
+	if sub, ok := geography.SubdivisionFor(station.Geography.RegionCode, line[38:40]); ok {
+		sub.Apply(station.Geography)
+	}
 
 	station.Name = strings.TrimSpace(line[41:71])
 
@@ -103,10 +121,8 @@ func (s *StationParserFn) ProcessElement(line string, emit func(*ds.Station)) {
 	// been matched to this station), then the field is blank.
 	station.Identifiers.WmoID = strings.TrimSpace(line[80:85])
 
-	// TODO(rsned): Update these to be dynamic.
-	station.StartDate = "0000-01-01"
-	station.EndDate = "9999-12-31"
-	station.LastUpdated = "2023-04-15"
+	// The stations period of record is not part of this file, it comes from
+	// ghcnd-inventory.txt and is set by ApplyCoverage.
 
 	emit(station)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
 	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
 	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
 )
 
 func TestFoo(t *testing.T) {
@@ -45,15 +46,63 @@ func TestFoo(t *testing.T) {
 					MetaRegion:       "NA",
 					RegionCode:       "US",
 					RegionName:       "United States",
-					Subdivision1Code: "CA",
+					Subdivision1Code: "US-CA",
+					Subdivision1Name: "California",
 					ElevationMeters:  3,
 					Lat:              37.619701,
 					Lng:              -122.365601,
+					LatE7:            376197000,
+					LngE7:            -1223656000,
+					S2CellID:         0x808f77e64ed8f301,
+					GeoHash:          "9q8yp82ndu77",
+					PlusCode:         "849VJJ9M+VQ",
+					H3Cell:           0x8f2830905ab28b1,
+					Timezone:         "America/Los_Angeles",
+				},
+				Attributions: &ds.Attributions{
+					Datasets:  []string{"GHCN-D"},
+					Versions:  []string{"GHCN-D 3"},
+					DOIs:      []string{"10.7289/V5D21VHZ"},
+					Licenses:  []string{noaa.License},
+					Citations: []string{DatasetCitation},
+					Networks:  []string{"WBAN/ICAO"},
+				},
+			},
+			wantEmpty: false,
+		},
+		{
+			have: `ASN00066062 -33.8607  151.2050   39.0    SYDNEY (OBSERVATORY HILL)      GSN     94768`,
+			want: &ds.Station{
+				ID:   "",
+				Name: "SYDNEY (OBSERVATORY HILL)",
+				Identifiers: &ds.Identifiers{
+					WmoID:  "94768",
+					GhcnID: "ASN00066062",
+				},
+				Geography: &ds.Geography{
+					Continent:       "Oceania",
+					MetaRegion:      "APAC",
+					RegionCode:      "AU",
+					RegionName:      "Australia",
+					ElevationMeters: 39,
+					Lat:             -33.8607,
+					Lng:             151.2050,
+					LatE7:           -338607000,
+					LngE7:           1512050000,
+					S2CellID:        0x6b12ae438f85319d,
+					GeoHash:         "r3gx2gb55u5b",
+					PlusCode:        "4RRH46Q4+P2",
+					H3Cell:          0x8fbe0e35c31e491,
+					Timezone:        "Australia/Sydney",
+				},
+				Attributions: &ds.Attributions{
+					Datasets:  []string{"GHCN-D"},
+					Versions:  []string{"GHCN-D 3"},
+					DOIs:      []string{"10.7289/V5D21VHZ"},
+					Licenses:  []string{noaa.License},
+					Citations: []string{DatasetCitation},
+					Networks:  []string{"National Meteorological or Hydrological Center"},
 				},
-				Attributions: &ds.Attributions{},
-				StartDate:    "0000-01-01",
-				EndDate:      "9999-12-31",
-				LastUpdated:  "2023-04-15",
 			},
 			wantEmpty: false,
 		},
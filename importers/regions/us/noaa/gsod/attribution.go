@@ -0,0 +1,36 @@
+package gsod
+
+import (
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+const (
+	// DatasetName is the name used for GSOD in Attributions and as the
+	// Source of its observations.
+	DatasetName = "GSOD"
+	// DatasetVersion is the version of GSOD the parsers handle.
+	DatasetVersion = "1.0"
+
+	// DatasetCitation is the citation NCEI asks users of GSOD to include.
+	DatasetCitation = "NOAA National Centers of Environmental Information. 1999. " +
+		"Global Surface Summary of the Day - GSOD. 1.0. " +
+		"NOAA National Centers for Environmental Information."
+)
+
+var dataset = ds.Dataset{
+	Name:     DatasetName,
+	Version:  DatasetVersion,
+	License:  noaa.License,
+	Citation: DatasetCitation,
+}
+
+// Attribution returns the Attributions for data from GSOD. Retrieved is when
+// the data files were downloaded, and is left out if it is the zero time.
+//
+// GSOD does not have a DOI.
+func Attribution(retrieved time.Time) *ds.Attributions {
+	return dataset.Attributions(retrieved)
+}
@@ -10,6 +10,17 @@ File format documentation:
 
 	https://www.ncei.noaa.gov/pub/data/gsod/readme.txt
 
-TODO(rsned): Add more to this documentation for the package.
+There is one CSV file per station per year, named by the stations USAF and
+WBAN IDs, e.g. 2023/72494023234.csv. Each row is one day for the station, and
+repeats the stations name, location and elevation alongside the days values:
+
+	"STATION","DATE","LATITUDE","LONGITUDE","ELEVATION","NAME","TEMP","TEMP_ATTRIBUTES",...
+	"72494023234","2023-01-01","37.6196","-122.3656","3.0","SAN FRANCISCO INTERNATIONAL AIRPORT, CA US","53.4","24",...
+
+Temperatures are in degrees Fahrenheit, pressures in millibars, visibility in
+statute miles, wind speeds in knots and precipitation and snow depth in inches.
+Missing values are all 9s, (9999.9, 999.9 or 99.99 depending on the field).
+
+The days are summarized from the hourly and synoptic reports for the UTC day.
 */
 package gsod
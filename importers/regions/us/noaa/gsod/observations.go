@@ -0,0 +1,335 @@
+package gsod
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/units"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// The columns of a GSOD CSV file.
+//
+// https://www.ncei.noaa.gov/pub/data/gsod/readme.txt
+const (
+	colStation = iota
+	colDate
+	colLatitude
+	colLongitude
+	colElevation
+	colName
+	colTemp
+	colTempAttributes
+	colDewPoint
+	colDewPointAttributes
+	colSeaLevelPressure
+	colSeaLevelPressureAttributes
+	colStationPressure
+	colStationPressureAttributes
+	colVisibility
+	colVisibilityAttributes
+	colWindSpeed
+	colWindSpeedAttributes
+	colMaxWindSpeed
+	colGust
+	colMax
+	colMaxAttributes
+	colMin
+	colMinAttributes
+	colPrecip
+	colPrecipAttributes
+	colSnowDepth
+	colFRSHTT
+
+	numColumns
+)
+
+// Missing value markers. Each field uses the largest value that fits in its
+// original fixed width format.
+const (
+	missing4 = 9999.9
+	missing3 = 999.9
+	missing2 = 99.99
+)
+
+// errHeader is returned when parsing the header row of a file.
+var errHeader = errors.New("gsod: header row")
+
+// column describes where the value in one of the GSOD columns goes in a
+// DailyObservation.
+type column struct {
+	// name is the name of the field, used for its quality Flag.
+	name    string
+	index   int
+	missing float64
+	// from is the unit of the columns values, and to the unit of the field.
+	from, to units.Unit
+	field    func(*ds.DailyObservation) *float64
+	// count is the field for the number of observations in the next column
+	// that the value was computed from, if there is one.
+	count func(*ds.DailyObservation) *int32
+	// flag returns the Quality for the source flag in the next column, if
+	// there is one.
+	flag func(string) ds.Quality
+}
+
+// columns are the measured values in each row.
+var columns = []column{
+	{
+		name: "TempCMean", index: colTemp, missing: missing4, from: units.Fahrenheit, to: units.Celsius,
+		field: func(o *ds.DailyObservation) *float64 { return &o.TempCMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.TempCount },
+	},
+	{
+		name: "DewPointCMean", index: colDewPoint, missing: missing4, from: units.Fahrenheit, to: units.Celsius,
+		field: func(o *ds.DailyObservation) *float64 { return &o.DewPointCMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.DewPointCount },
+	},
+	{
+		name: "PressureSeaLevelHPaMean", index: colSeaLevelPressure, missing: missing4, from: units.Millibars, to: units.Hectopascals,
+		field: func(o *ds.DailyObservation) *float64 { return &o.PressureSeaLevelHPaMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.PressureSeaLevelCount },
+	},
+	{
+		name: "PressureStationHPaMean", index: colStationPressure, missing: missing4, from: units.Millibars, to: units.Hectopascals,
+		field: func(o *ds.DailyObservation) *float64 { return &o.PressureStationHPaMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.PressureStationCount },
+	},
+	{
+		name: "VisibilityMMean", index: colVisibility, missing: missing3, from: units.StatuteMiles, to: units.Meters,
+		field: func(o *ds.DailyObservation) *float64 { return &o.VisibilityMMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.VisibilityCount },
+	},
+	{
+		name: "WindSpeedMPSMean", index: colWindSpeed, missing: missing3, from: units.Knots, to: units.MetersPerSecond,
+		field: func(o *ds.DailyObservation) *float64 { return &o.WindSpeedMPSMean },
+		count: func(o *ds.DailyObservation) *int32 { return &o.WindSpeedCount },
+	},
+	{
+		name: "WindSpeedMPSMax", index: colMaxWindSpeed, missing: missing3, from: units.Knots, to: units.MetersPerSecond,
+		field: func(o *ds.DailyObservation) *float64 { return &o.WindSpeedMPSMax },
+	},
+	{
+		name: "WindGustMPSMax", index: colGust, missing: missing3, from: units.Knots, to: units.MetersPerSecond,
+		field: func(o *ds.DailyObservation) *float64 { return &o.WindGustMPSMax },
+	},
+	{
+		name: "TempCMax", index: colMax, missing: missing4, from: units.Fahrenheit, to: units.Celsius,
+		field: func(o *ds.DailyObservation) *float64 { return &o.TempCMax },
+		flag:  extremeQuality,
+	},
+	{
+		name: "TempCMin", index: colMin, missing: missing4, from: units.Fahrenheit, to: units.Celsius,
+		field: func(o *ds.DailyObservation) *float64 { return &o.TempCMin },
+		flag:  extremeQuality,
+	},
+	{
+		name: "PrecipMM", index: colPrecip, missing: missing2, from: units.Inches, to: units.Millimeters,
+		field: func(o *ds.DailyObservation) *float64 { return &o.PrecipMM },
+		flag:  precipQuality,
+	},
+	{
+		name: "SnowDepthMM", index: colSnowDepth, missing: missing3, from: units.Inches, to: units.Millimeters,
+		field: func(o *ds.DailyObservation) *float64 { return &o.SnowDepthMM },
+	},
+}
+
+// frshtt are the METAR weather codes for each of the digits of the FRSHTT
+// indicator: Fog, Rain or drizzle, Snow or ice pellets, Hail, Thunder, and
+// Tornado or funnel cloud.
+var frshtt = []string{"FG", "RA", "SN", "GR", "TS", "FC"}
+
+func init() {
+	register.DoFn2x0[string, func(*ds.DailyObservation)](&ObservationParserFn{})
+	register.Emitter1[*ds.DailyObservation]()
+}
+
+// ObservationParserFn is an Apache Beam structural DoFn to process rows from
+// GSOD files into DailyObservations. Header rows and malformed rows are
+// skipped.
+type ObservationParserFn struct {
+}
+
+// ProcessElement reads one row in and attempts to convert it into a DailyObservation.
+func (fn *ObservationParserFn) ProcessElement(line string, emit func(*ds.DailyObservation)) {
+	obs, err := ParseLine(line)
+	if err != nil {
+		return
+	}
+	emit(obs)
+}
+
+// ParseLine parses one row of a GSOD CSV file into a DailyObservation.
+func ParseLine(line string) (*ds.DailyObservation, error) {
+	fields, err := splitLine(line)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFields(fields)
+}
+
+// splitLine splits one CSV row into its fields.
+func splitLine(line string) ([]string, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = numColumns
+	fields, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("gsod: malformed row %q: %v", line, err)
+	}
+	return fields, nil
+}
+
+// ParseFields converts the fields of one GSOD row into a DailyObservation.
+// The StationID is the stations USAF and WBAN IDs joined with a "-", the
+// same form used to name the ISD files.
+func ParseFields(fields []string) (*ds.DailyObservation, error) {
+	if len(fields) != numColumns {
+		return nil, fmt.Errorf("gsod: row has %d fields, want %d", len(fields), numColumns)
+	}
+	if strings.TrimSpace(fields[colStation]) == "STATION" {
+		return nil, errHeader
+	}
+
+	id, err := stationID(fields[colStation])
+	if err != nil {
+		return nil, err
+	}
+	date, err := ds.ParseDate(fields[colDate])
+	if err != nil || date.IsZero() {
+		return nil, fmt.Errorf("gsod: invalid date %q for station %s", fields[colDate], id)
+	}
+
+	obs := ds.EmptyDailyObservation()
+	obs.StationID = id
+	obs.Source = DatasetName
+	obs.License = dataset.License
+	obs.Citation = dataset.Citation
+	obs.Date = date
+
+	for _, c := range columns {
+		v := utils.ParseFloat(fields[c.index], c.missing)
+		if v == c.missing {
+			continue
+		}
+		*c.field(obs) = units.MustConvert(v, c.from, c.to)
+
+		flag := &ds.Flag{Quality: ds.QualityPassed}
+		switch {
+		case c.count != nil:
+			*c.count(obs) = int32(utils.ParseInt(fields[c.index+1], ds.UnsetValue))
+		case c.flag != nil:
+			flag.Original = strings.TrimSpace(fields[c.index+1])
+			flag.Quality = c.flag(flag.Original)
+		}
+		obs.Flags.Set(c.name, flag)
+	}
+
+	indicators := strings.TrimSpace(fields[colFRSHTT])
+	for i, code := range frshtt {
+		if i < len(indicators) && indicators[i] == '1' {
+			obs.Weather = append(obs.Weather, code)
+		}
+	}
+
+	return obs, nil
+}
+
+// stationID returns the "USAF-WBAN" form of the 11 digit STATION field.
+func stationID(station string) (string, error) {
+	station = strings.TrimSpace(station)
+	if len(station) != 11 {
+		return "", fmt.Errorf("gsod: invalid station %q", station)
+	}
+	return station[0:6] + "-" + station[6:11], nil
+}
+
+// extremeQuality returns the Quality for the max and min temperature flags.
+//
+//	Blank indicates max temp was taken from the explicit max temp report and
+//	not from the 'hourly' data.
+//	* indicates max temp was derived from the hourly data (i.e., highest
+//	hourly or synoptic-reported temperature).
+func extremeQuality(flag string) ds.Quality {
+	if flag == "*" {
+		return ds.QualityEstimated
+	}
+	return ds.QualityPassed
+}
+
+// precipQuality returns the Quality for the precipitation flags.
+//
+//	A = 1 report of 6-hour precipitation amount.
+//	B = Summation of 2 reports of 6-hour precipitation amount.
+//	C = Summation of 3 reports of 6-hour precipitation amount.
+//	D = Summation of 4 reports of 6-hour precipitation amount.
+//	E = 1 report of 12-hour precipitation amount.
+//	F = Summation of 2 reports of 12-hour precipitation amount.
+//	G = 1 report of 24-hour precipitation amount.
+//	H = Station reported '0' as the amount for the day (eg, from 6-hour
+//	    reports), but also reported at least one occurrence of precipitation
+//	    in hourly observations--this could indicate a trace occurred, but
+//	    should be considered as incomplete data for the day.
+//	I = Station did not report any precip data for the day and did not report
+//	    any occurrences of precipitation in its hourly observations--it's
+//	    still possible that precip occurred but was not reported.
+//
+// Amounts which cover less than the full day are suspect, as is H, and I is
+// an amount presumed to be zero.
+func precipQuality(flag string) ds.Quality {
+	switch flag {
+	case "A", "B", "C", "E", "H":
+		return ds.QualitySuspect
+	case "I":
+		return ds.QualityEstimated
+	}
+	return ds.QualityPassed
+}
+
+// Reader reads DailyObservations from a GSOD CSV file without needing a Beam
+// pipeline. Either the plain text or gzip compressed files are accepted.
+type Reader struct {
+	r *csv.Reader
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	ur, err := utils.MaybeGunzip(r)
+	if err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(ur)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	return &Reader{r: cr}, nil
+}
+
+// Next returns the next DailyObservation in the file. io.EOF is returned
+// once there are no more observations. The header and malformed rows are
+// skipped.
+func (g *Reader) Next() (*ds.DailyObservation, error) {
+	for {
+		fields, err := g.r.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		obs, err := ParseFields(fields)
+		if err != nil {
+			continue
+		}
+		return obs, nil
+	}
+}
@@ -0,0 +1,228 @@
+package gsod
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+const (
+	testHeader = `"STATION","DATE","LATITUDE","LONGITUDE","ELEVATION","NAME","TEMP","TEMP_ATTRIBUTES","DEWP","DEWP_ATTRIBUTES","SLP","SLP_ATTRIBUTES","STP","STP_ATTRIBUTES","VISIB","VISIB_ATTRIBUTES","WDSP","WDSP_ATTRIBUTES","MXSPD","GUST","MAX","MAX_ATTRIBUTES","MIN","MIN_ATTRIBUTES","PRCP","PRCP_ATTRIBUTES","SNDP","FRSHTT"`
+	testSFO    = `"72494023234","2023-01-01","37.6196","-122.3656","3.0","SAN FRANCISCO INTERNATIONAL AIRPORT, CA US","  53.4","24","  45.9","24","1016.1","24","9999.9"," 0","  9.5","24","  8.5","24"," 15.0"," 22.0","  57.9","*","  48.9"," ","  0.74","G","999.9","010010"`
+	testSFO2   = `"72494023234","2023-01-02","37.6196","-122.3656","3.0","SAN FRANCISCO INTERNATIONAL AIRPORT, CA US","  50.0","24","  41.0","24","1020.0","24","9999.9"," 0","999.9"," 0","999.9"," 0","999.9","999.9","9999.9"," ","  32.0"," "," 99.99"," ","  1.0","100000"`
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *ds.DailyObservation
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testHeader,
+			wantErr: true,
+		},
+		{
+			// Too few fields.
+			have:    `"72494023234","2023-01-01","37.6196"`,
+			wantErr: true,
+		},
+		{
+			// Station ID too short.
+			have:    strings.Replace(testSFO, "72494023234", "7249402323", 1),
+			wantErr: true,
+		},
+		{
+			// Invalid date.
+			have:    strings.Replace(testSFO, "2023-01-01", "2023-02-30", 1),
+			wantErr: true,
+		},
+		{
+			have: testSFO,
+			want: &ds.DailyObservation{
+				StationID:               "724940-23234",
+				Source:                  DatasetName,
+				License:                 noaa.License,
+				Citation:                DatasetCitation,
+				Date:                    ds.Date{Year: 2023, Month: 1, Day: 1},
+				TempCMin:                9.388889,
+				TempCMean:               11.888889,
+				TempCMax:                14.388889,
+				DewPointCMean:           7.722222,
+				RelativeHumidityMean:    ds.UnsetValue,
+				PrecipMM:                18.796,
+				SnowfallMM:              ds.UnsetValue,
+				SnowDepthMM:             ds.UnsetValue,
+				WindSpeedMPSMean:        4.372778,
+				WindSpeedMPSMax:         7.716667,
+				WindDirectionDegMax:     ds.UnsetValue,
+				WindGustMPSMax:          11.317778,
+				PressureStationHPaMean:  ds.UnsetValue,
+				PressureSeaLevelHPaMean: 1016.1,
+				VisibilityMMean:         15288.768,
+				SunshineMinutes:         ds.UnsetValue,
+				SunshinePercent:         ds.UnsetValue,
+				TempCount:               24,
+				DewPointCount:           24,
+				PressureStationCount:    ds.UnsetValue,
+				PressureSeaLevelCount:   24,
+				VisibilityCount:         24,
+				WindSpeedCount:          24,
+				Weather:                 []string{"RA", "TS"},
+				Flags: ds.Flags{
+					"TempCMin":                {Quality: ds.QualityPassed},
+					"TempCMean":               {Quality: ds.QualityPassed},
+					"TempCMax":                {Quality: ds.QualityEstimated, Original: "*"},
+					"DewPointCMean":           {Quality: ds.QualityPassed},
+					"PrecipMM":                {Quality: ds.QualityPassed, Original: "G"},
+					"WindSpeedMPSMean":        {Quality: ds.QualityPassed},
+					"WindSpeedMPSMax":         {Quality: ds.QualityPassed},
+					"WindGustMPSMax":          {Quality: ds.QualityPassed},
+					"PressureSeaLevelHPaMean": {Quality: ds.QualityPassed},
+					"VisibilityMMean":         {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			// Missing values are left unset.
+			have: testSFO2,
+			want: &ds.DailyObservation{
+				StationID:               "724940-23234",
+				Source:                  DatasetName,
+				License:                 noaa.License,
+				Citation:                DatasetCitation,
+				Date:                    ds.Date{Year: 2023, Month: 1, Day: 2},
+				TempCMin:                0,
+				TempCMean:               10,
+				TempCMax:                ds.UnsetValue,
+				DewPointCMean:           5,
+				RelativeHumidityMean:    ds.UnsetValue,
+				PrecipMM:                ds.UnsetValue,
+				SnowfallMM:              ds.UnsetValue,
+				SnowDepthMM:             25.4,
+				WindSpeedMPSMean:        ds.UnsetValue,
+				WindSpeedMPSMax:         ds.UnsetValue,
+				WindDirectionDegMax:     ds.UnsetValue,
+				WindGustMPSMax:          ds.UnsetValue,
+				PressureStationHPaMean:  ds.UnsetValue,
+				PressureSeaLevelHPaMean: 1020,
+				VisibilityMMean:         ds.UnsetValue,
+				SunshineMinutes:         ds.UnsetValue,
+				SunshinePercent:         ds.UnsetValue,
+				TempCount:               24,
+				DewPointCount:           24,
+				PressureStationCount:    ds.UnsetValue,
+				PressureSeaLevelCount:   24,
+				VisibilityCount:         ds.UnsetValue,
+				WindSpeedCount:          ds.UnsetValue,
+				Weather:                 []string{"FG"},
+				Flags: ds.Flags{
+					"TempCMin":                {Quality: ds.QualityPassed},
+					"TempCMean":               {Quality: ds.QualityPassed},
+					"DewPointCMean":           {Quality: ds.QualityPassed},
+					"SnowDepthMM":             {Quality: ds.QualityPassed},
+					"PressureSeaLevelHPaMean": {Quality: ds.QualityPassed},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+			t.Errorf("ParseLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+// parseLine returns the observation for one of the test lines, whose parsing
+// is checked by TestParseLine.
+func parseLine(t *testing.T, line string) *ds.DailyObservation {
+	t.Helper()
+	obs, err := ParseLine(line)
+	if err != nil {
+		t.Fatalf("ParseLine(%q) error = %v", line, err)
+	}
+	return obs
+}
+
+func TestQualities(t *testing.T) {
+	tests := []struct {
+		fn   func(string) ds.Quality
+		flag string
+		want ds.Quality
+	}{
+		{fn: extremeQuality, flag: "", want: ds.QualityPassed},
+		{fn: extremeQuality, flag: "*", want: ds.QualityEstimated},
+		{fn: precipQuality, flag: "A", want: ds.QualitySuspect},
+		{fn: precipQuality, flag: "D", want: ds.QualityPassed},
+		{fn: precipQuality, flag: "G", want: ds.QualityPassed},
+		{fn: precipQuality, flag: "H", want: ds.QualitySuspect},
+		{fn: precipQuality, flag: "I", want: ds.QualityEstimated},
+	}
+
+	for _, test := range tests {
+		if got := test.fn(test.flag); got != test.want {
+			t.Errorf("Quality(%q) = %v, want %v", test.flag, got, test.want)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	input := strings.Join([]string{
+		testHeader,
+		testSFO,
+		`"pizza","hamburgers"`,
+		testSFO2,
+	}, "\n")
+
+	r, err := NewReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var got []*ds.DailyObservation
+	for {
+		obs, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, obs)
+	}
+
+	want := []*ds.DailyObservation{parseLine(t, testSFO), parseLine(t, testSFO2)}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+		t.Errorf("Reader diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestObservationParserFn(t *testing.T) {
+	beam.Init()
+	p, s := beam.NewPipelineWithRoot()
+	lines := beam.Create(s, testHeader, testSFO2)
+	passert.Equals(s, beam.ParDo(s, &ObservationParserFn{}, lines), parseLine(t, testSFO2))
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("ObservationParserFn failed: %v", err)
+	}
+}
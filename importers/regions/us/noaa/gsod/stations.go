@@ -0,0 +1,154 @@
+package gsod
+
+import (
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// SourceName identifies the GSOD stations when merging them with other sources.
+const SourceName = "gsod"
+
+func init() {
+	register.DoFn2x0[string, func(*ds.Station)](&StationParserFn{})
+	register.Function1x2(stationKeyFn)
+	register.Function3x0(combineStationsFn)
+	register.Iter1[*ds.Station]()
+	register.Emitter1[*ds.Station]()
+}
+
+// Stations returns a PCollection<*ds.Station> with one Station for each of
+// the stations in the given PCollection<string> of GSOD rows. The stations
+// period of record is the span of the days seen for it, and the name and
+// location are from its latest row.
+func Stations(s beam.Scope, retrieved time.Time, lines beam.PCollection) beam.PCollection {
+	s = s.Scope("gsod.Stations")
+	stations := beam.ParDo(s, &StationParserFn{Retrieved: retrieved}, lines)
+	grouped := beam.GroupByKey(s, beam.ParDo(s, stationKeyFn, stations))
+	return beam.ParDo(s, combineStationsFn, grouped)
+}
+
+// StationParserFn is an Apache Beam structural DoFn to process rows from GSOD
+// files into Stations. Every row repeats the station information, so one
+// Station is emitted per row with its StartDate and EndDate set to the day.
+// Use Stations to reduce these to one per station.
+type StationParserFn struct {
+	// Retrieved is when the files were downloaded, for the stations
+	// Attributions. It is left out if it is the zero time.
+	Retrieved time.Time
+}
+
+// ProcessElement reads one row in and attempts to convert it into a Station.
+func (fn *StationParserFn) ProcessElement(line string, emit func(*ds.Station)) {
+	fields, err := splitLine(line)
+	if err != nil {
+		return
+	}
+	station, err := ParseStation(fields)
+	if err != nil {
+		return
+	}
+	station.Attributions = Attribution(fn.Retrieved)
+	emit(station)
+}
+
+// ParseStation converts the station fields of one GSOD row into a Station.
+//
+// Only the latitude and longitude of the location are set, as every row
+// repeats them. Stations fills in the fields derived from them,
+// (geography.Normalize), once for each station.
+func ParseStation(fields []string) (*ds.Station, error) {
+	obs, err := ParseFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	station := ds.EmptyStation()
+	station.Identifiers.UsafID, station.Identifiers.WbanID, _ = strings.Cut(obs.StationID, "-")
+	station.StartDate = obs.Date
+	station.EndDate = obs.Date
+
+	station.Geography.Lat = float32(utils.ParseFloat(fields[colLatitude], 0))
+	station.Geography.Lng = float32(utils.ParseFloat(fields[colLongitude], 0))
+
+	// Elevation is in meters, and -999.9 when missing.
+	if elev := utils.ParseFloat(fields[colElevation], ds.UnsetValue); elev > -999 {
+		station.Geography.ElevationMeters = int32(elev)
+	} else {
+		station.Geography.ElevationMeters = ds.UnsetValue
+	}
+
+	// The NAME ends with the US state or Canadian province, if any, and the
+	// FIPS country code, e.g. "SAN FRANCISCO INTERNATIONAL AIRPORT, CA US".
+	name, country, state := splitName(fields[colName])
+	station.Name = name
+	if region, ok := geography.RegionForFIPS(country); ok {
+		region.Apply(station.Geography)
+	}
+	if sub, ok := geography.SubdivisionFor(station.Geography.RegionCode, state); ok {
+		sub.Apply(station.Geography)
+	}
+
+	return station, nil
+}
+
+// splitName splits the name, FIPS country and state off the NAME field.
+func splitName(field string) (name, country, state string) {
+	field = strings.TrimSpace(field)
+	i := strings.LastIndex(field, ",")
+	if i < 0 {
+		return field, "", ""
+	}
+
+	codes := strings.Fields(field[i+1:])
+	for _, c := range codes {
+		if len(c) != 2 {
+			return field, "", ""
+		}
+	}
+	switch len(codes) {
+	case 1:
+		country = codes[0]
+	case 2:
+		state, country = codes[0], codes[1]
+	default:
+		// Not a location suffix, just a comma in the name.
+		return field, "", ""
+	}
+	return strings.TrimSpace(field[:i]), country, state
+}
+
+// stationKeyFn keys the station by its USAF and WBAN IDs.
+func stationKeyFn(s *ds.Station) (string, *ds.Station) {
+	return s.Identifiers.UsafID + "-" + s.Identifiers.WbanID, s
+}
+
+// combineStationsFn reduces the Stations from each row for one station into a
+// single Station, and fills in the fields derived from its location.
+func combineStationsFn(_ string, iter func(**ds.Station) bool, emit func(*ds.Station)) {
+	var out, s *ds.Station
+	for iter(&s) {
+		if out == nil {
+			out = s
+			continue
+		}
+		start := out.StartDate
+		if s.StartDate.Before(start) {
+			start = s.StartDate
+		}
+		if s.EndDate.After(out.EndDate) {
+			out = s
+		}
+		out.StartDate = start
+	}
+	if out != nil {
+		geography.Normalize(out.Geography)
+		emit(out)
+	}
+}
@@ -0,0 +1,104 @@
+package gsod
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rsned/weather/importers/geography"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		have                 string
+		name, country, state string
+	}{
+		{have: "SAN FRANCISCO INTERNATIONAL AIRPORT, CA US", name: "SAN FRANCISCO INTERNATIONAL AIRPORT", country: "US", state: "CA"},
+		{have: "TORONTO LESTER B PEARSON INTL, ON CA", name: "TORONTO LESTER B PEARSON INTL", country: "CA", state: "ON"},
+		{have: "HEATHROW, UK", name: "HEATHROW", country: "UK"},
+		{have: "WXPOD 7018", name: "WXPOD 7018"},
+		{have: "", name: ""},
+		{have: "ST. JOHN'S, WEST, NF", name: "ST. JOHN'S, WEST", country: "NF"},
+		{have: "CAMP, NORTH FIELD", name: "CAMP, NORTH FIELD"},
+	}
+
+	for _, test := range tests {
+		name, country, state := splitName(test.have)
+		if name != test.name || country != test.country || state != test.state {
+			t.Errorf("splitName(%q) = %q, %q, %q, want %q, %q, %q", test.have,
+				name, country, state, test.name, test.country, test.state)
+		}
+	}
+}
+
+func wantSFOStation() *ds.Station {
+	return &ds.Station{
+		Name: "SAN FRANCISCO INTERNATIONAL AIRPORT",
+		Identifiers: &ds.Identifiers{
+			UsafID: "724940",
+			WbanID: "23234",
+		},
+		Geography: &ds.Geography{
+			Continent:        "North America",
+			MetaRegion:       "NA",
+			RegionCode:       "US",
+			RegionName:       "United States",
+			Subdivision1Code: "US-CA",
+			Subdivision1Name: "California",
+			ElevationMeters:  3,
+			Lat:              37.6196,
+			Lng:              -122.3656,
+		},
+		Attributions: &ds.Attributions{},
+		StartDate:    ds.Date{Year: 2023, Month: 1, Day: 1},
+		EndDate:      ds.Date{Year: 2023, Month: 1, Day: 1},
+	}
+}
+
+func TestParseStation(t *testing.T) {
+	fields, err := splitLine(testSFO)
+	if err != nil {
+		t.Fatalf("splitLine() error = %v", err)
+	}
+	got, err := ParseStation(fields)
+	if err != nil {
+		t.Fatalf("ParseStation() error = %v", err)
+	}
+	if diff := cmp.Diff(wantSFOStation(), got); diff != "" {
+		t.Errorf("ParseStation() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestStations(t *testing.T) {
+	beam.Init()
+	retrieved := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// The later row has an updated elevation which should be kept.
+	later := strings.Replace(testSFO2, `"3.0"`, `"4.0"`, 1)
+	other := strings.Replace(testSFO, "72494023234", "99999923234", 1)
+
+	want := wantSFOStation()
+	want.Geography.ElevationMeters = 4
+	want.EndDate = ds.Date{Year: 2023, Month: 1, Day: 2}
+	want.Attributions = Attribution(retrieved)
+	geography.Normalize(want.Geography)
+
+	wantOther := wantSFOStation()
+	wantOther.Identifiers.UsafID = "999999"
+	wantOther.Attributions = Attribution(retrieved)
+	geography.Normalize(wantOther.Geography)
+
+	p, s := beam.NewPipelineWithRoot()
+	lines := beam.Create(s, testHeader, later, testSFO, other)
+	passert.Equals(s, Stations(s, retrieved, lines), want, wantOther)
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("Stations failed: %v", err)
+	}
+}
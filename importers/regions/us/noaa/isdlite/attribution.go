@@ -0,0 +1,34 @@
+package isdlite
+
+import (
+	"time"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+const (
+	// DatasetName is the name used for ISD-Lite in Attributions and as the
+	// Source of its observations.
+	DatasetName = "ISD-Lite"
+
+	// DatasetCitation is the citation NCEI asks users of ISD, and the
+	// products derived from it, to include.
+	DatasetCitation = "Smith, A., N. Lott, and R. Vose, 2011: The Integrated Surface Database: " +
+		"Recent Developments and Partnerships. Bulletin of the American Meteorological Society, " +
+		"92, 704-708, doi:10.1175/2011BAMS3015.1"
+)
+
+var dataset = ds.Dataset{
+	Name:     DatasetName,
+	License:  noaa.License,
+	Citation: DatasetCitation,
+}
+
+// Attribution returns the Attributions for data from ISD-Lite. Retrieved is
+// when the data files were downloaded, and is left out if it is the zero time.
+//
+// ISD-Lite is not versioned and does not have a DOI of its own.
+func Attribution(retrieved time.Time) *ds.Attributions {
+	return dataset.Attributions(retrieved)
+}
@@ -10,6 +10,19 @@ File format documentation:
 
 	https://www.ncei.noaa.gov/pub/data/noaa/isd-lite/isd-lite-format.txt
 
-TODO(rsned): Add more to this documentation for the package.
+There is one gzip compressed file per station per year, named by the stations
+USAF and WBAN IDs, e.g. 2023/724940-23234-2023.gz. The station is only given
+in the file name, so the lines must be read along with the name of their file,
+(see utils.ReadFileLines).
+
+Each line is one hourly observation in UTC, with the values in fixed width
+columns scaled by 10 where noted and -9999 for missing values:
+
+	2023 01 01 00   122    94 10153   300    36     8     0 -9999
+
+The stations are listed in the ISD station history file, with their USAF and
+WBAN IDs, ICAO code, location and period of record:
+
+	https://www.ncei.noaa.gov/pub/data/noaa/isd-history.csv
 */
 package isdlite
@@ -0,0 +1,239 @@
+package isdlite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/units"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// lineLength is the length of every ISD-Lite line.
+const lineLength = 61
+
+// The ISD-Lite trace precipitation value.
+const tracePrecip = -1
+
+// column describes where the value in one of the ISD-Lite columns goes in an
+// Observation.
+type column struct {
+	// name is the name of the field, used for its quality Flag.
+	name string
+	// start and end are the offsets of the column in the line.
+	start, end int
+	// from is the unit of the columns values, and to the unit of the field.
+	from, to units.Unit
+	field    func(*ds.Observation) *float64
+}
+
+// columns are the values in each line, other than the sky cover.
+//
+// https://www.ncei.noaa.gov/pub/data/noaa/isd-lite/isd-lite-format.txt
+//
+//	Field 5: Pos 14-19, Length 6: Air Temperature, degrees Celsius, scaled by 10
+//	Field 6: Pos 20-25, Length 6: Dew Point Temperature, degrees Celsius, scaled by 10
+//	Field 7: Pos 26-31, Length 6: Sea Level Pressure, hectopascals, scaled by 10
+//	Field 8: Pos 32-37, Length 6: Wind Direction, angular degrees, calm is 0
+//	Field 9: Pos 38-43, Length 6: Wind Speed Rate, meters per second, scaled by 10
+//	Field 11: Pos 50-55, Length 6: Liquid Precipitation Depth Dimension - One Hour Duration, millimeters, scaled by 10
+//	Field 12: Pos 56-61, Length 6: Liquid Precipitation Depth Dimension - Six Hour Duration, millimeters, scaled by 10
+var columns = []column{
+	{"TempC", 13, 19, units.TenthsCelsius, units.Celsius, func(o *ds.Observation) *float64 { return &o.TempC }},
+	{"DewPointC", 19, 25, units.TenthsCelsius, units.Celsius, func(o *ds.Observation) *float64 { return &o.DewPointC }},
+	{"PressureSeaLevelHPa", 25, 31, units.TenthsHectopascals, units.Hectopascals, func(o *ds.Observation) *float64 { return &o.PressureSeaLevelHPa }},
+	{"WindDirectionDeg", 31, 37, units.Degrees, units.Degrees, func(o *ds.Observation) *float64 { return &o.WindDirectionDeg }},
+	{"WindSpeedMPS", 37, 43, units.TenthsMetersPerSecond, units.MetersPerSecond, func(o *ds.Observation) *float64 { return &o.WindSpeedMPS }},
+	{"Precip1HrMM", 49, 55, units.TenthsMillimeters, units.Millimeters, func(o *ds.Observation) *float64 { return &o.Precip1HrMM }},
+	{"Precip6HrMM", 55, 61, units.TenthsMillimeters, units.Millimeters, func(o *ds.Observation) *float64 { return &o.Precip6HrMM }},
+}
+
+func init() {
+	register.DoFn3x0[string, string, func(*ds.Observation)](&ObservationParserFn{})
+	register.Emitter1[*ds.Observation]()
+}
+
+// Observations reads the ISD-Lite files matching the glob, and returns a
+// PCollection<*ds.Observation> of their hourly observations.
+func Observations(s beam.Scope, glob string) beam.PCollection {
+	s = s.Scope("isdlite.Observations")
+	return beam.ParDo(s, &ObservationParserFn{}, utils.ReadFileLines(s, glob))
+}
+
+// ObservationParserFn is an Apache Beam structural DoFn to process the lines
+// of ISD-Lite files, keyed by the name of their file, into Observations.
+// Lines from files which are not named for their station, and malformed
+// lines, are skipped.
+type ObservationParserFn struct {
+}
+
+// ProcessElement reads one line in and attempts to convert it into an Observation.
+func (fn *ObservationParserFn) ProcessElement(filename, line string, emit func(*ds.Observation)) {
+	id, err := StationIDFromFilename(filename)
+	if err != nil {
+		return
+	}
+	obs, err := ParseLine(id, line)
+	if err != nil {
+		return
+	}
+	emit(obs)
+}
+
+// StationIDFromFilename returns the "USAF-WBAN" station ID from the name of
+// an ISD-Lite file, e.g. "724940-23234" for ".../2023/724940-23234-2023.gz".
+func StationIDFromFilename(filename string) (string, error) {
+	base := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	parts := strings.Split(base, "-")
+	if len(parts) != 3 || len(parts[0]) != 6 || len(parts[1]) != 5 {
+		return "", fmt.Errorf("isdlite: file %q is not named USAF-WBAN-YEAR", filename)
+	}
+	return parts[0] + "-" + parts[1], nil
+}
+
+// ParseLine parses one ISD-Lite line for the given station into an
+// Observation.
+//
+//	Field 1: Pos 1-4, Length 4: Observation Year
+//	Field 2: Pos 6-7, Length 2: Observation Month
+//	Field 3: Pos 9-11, Length 2: Observation Day
+//	Field 4: Pos 12-13, Length 2: Observation Hour
+func ParseLine(stationID, line string) (*ds.Observation, error) {
+	if len(line) != lineLength {
+		return nil, fmt.Errorf("isdlite: line has length %d, want %d", len(line), lineLength)
+	}
+
+	year := utils.ParseIntBounded(line[0:4], 1, 9999, ds.UnsetValue)
+	month := utils.ParseIntBounded(line[5:7], 1, 12, ds.UnsetValue)
+	day := utils.ParseIntBounded(line[8:10], 1, 31, ds.UnsetValue)
+	hour := utils.ParseIntBounded(line[11:13], 0, 23, ds.UnsetValue)
+	if year == ds.UnsetValue || month == ds.UnsetValue || day == ds.UnsetValue || hour == ds.UnsetValue {
+		return nil, fmt.Errorf("isdlite: malformed line %q", line)
+	}
+	t := time.Date(int(year), time.Month(month), int(day), int(hour), 0, 0, 0, time.UTC)
+	if t.Day() != int(day) {
+		return nil, fmt.Errorf("isdlite: invalid date in line %q", line)
+	}
+
+	obs := ds.EmptyObservation()
+	obs.StationID = stationID
+	obs.Source = DatasetName
+	obs.License = dataset.License
+	obs.Citation = dataset.Citation
+	obs.Time = t
+
+	for _, c := range columns {
+		v := utils.ParseInt(line[c.start:c.end], ds.UnsetValue)
+		if v == ds.UnsetValue {
+			continue
+		}
+
+		flag := &ds.Flag{Quality: ds.QualityPassed}
+		if v == tracePrecip && c.from == units.TenthsMillimeters {
+			v = 0
+			flag = &ds.Flag{Quality: ds.QualityTrace, Original: "-1"}
+		}
+		*c.field(obs) = units.MustConvert(float64(v), c.from, c.to)
+		obs.Flags.Set(c.name, flag)
+	}
+
+	// Calm winds are coded with a direction of 0.
+	if obs.WindSpeedMPS == 0 && obs.WindDirectionDeg == 0 {
+		obs.WindDirectionDeg = ds.UnsetValue
+		delete(obs.Flags, "WindDirectionDeg")
+	}
+
+	//	Field 10: Pos 44-49, Length 6: Sky Condition Total Coverage Code
+	if code := utils.ParseInt(line[43:49], ds.UnsetValue); code != ds.UnsetValue {
+		if oktas, quality, ok := skyCover(code); ok {
+			obs.SkyCoverOktas = oktas
+			obs.Flags.Set("SkyCoverOktas", &ds.Flag{Quality: quality, Original: fmt.Sprint(code)})
+		}
+	}
+
+	return obs, nil
+}
+
+// skyCover converts the sky condition total coverage code into oktas.
+//
+//	0: None, SKC or CLR
+//	1: One okta - 1/10 or less but not zero
+//	2: Two oktas - 2/10 - 3/10, or FEW
+//	3: Three oktas - 4/10
+//	4: Four oktas - 5/10, or SCT
+//	5: Five oktas - 6/10
+//	6: Six oktas - 7/10 - 8/10
+//	7: Seven oktas - 9/10 or more but not 10/10, or BKN
+//	8: Eight oktas - 10/10, or OVC
+//	9: Sky obscured, or cloud amount cannot be estimated
+//	10: Partial obscuration
+//	11: Thin scattered
+//	12: Scattered
+//	13: Dark scattered
+//	14: Thin broken
+//	15: Broken
+//	16: Dark broken
+//	17: Thin overcast
+//	18: Overcast
+//	19: Dark overcast
+//
+// The older codes from 11 up are given the oktas of their METAR equivalent,
+// and an obscured sky is taken to be fully covered. These are flagged as
+// estimated. Partial obscuration has no equivalent and is not returned.
+func skyCover(code int64) (float64, ds.Quality, bool) {
+	switch {
+	case code >= 0 && code <= 8:
+		return float64(code), ds.QualityPassed, true
+	case code == 9:
+		return 8, ds.QualityEstimated, true
+	case code >= 11 && code <= 13:
+		return 4, ds.QualityEstimated, true
+	case code >= 14 && code <= 16:
+		return 7, ds.QualityEstimated, true
+	case code >= 17 && code <= 19:
+		return 8, ds.QualityEstimated, true
+	}
+	return 0, "", false
+}
+
+// Reader reads Observations from an ISD-Lite file without needing a Beam
+// pipeline. The files are read as a stream, so the large gzip compressed
+// files do not need to be decompressed first. Plain text files are also
+// accepted.
+type Reader struct {
+	stationID string
+	scanner   *bufio.Scanner
+}
+
+// NewReader returns a Reader reading the observations for the given station
+// from r. Use StationIDFromFilename to get the station for a file.
+func NewReader(stationID string, r io.Reader) (*Reader, error) {
+	ur, err := utils.MaybeGunzip(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{stationID: stationID, scanner: bufio.NewScanner(ur)}, nil
+}
+
+// Next returns the next Observation in the file. io.EOF is returned once
+// there are no more observations. Malformed lines are skipped.
+func (r *Reader) Next() (*ds.Observation, error) {
+	for r.scanner.Scan() {
+		obs, err := ParseLine(r.stationID, r.scanner.Text())
+		if err != nil {
+			continue
+		}
+		return obs, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,251 @@
+package isdlite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/regions/us/noaa"
+)
+
+const (
+	testLine1 = "2023 01 01 00   122    94 10153   300    36     8     0 -9999"
+	testLine2 = "2023 01 01 01   117    89 -9999     0     0    15    -1    25"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *ds.Observation
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			// Too short.
+			have:    "2023 01 01 00   122    94 10153   300    36     8     0",
+			wantErr: true,
+		},
+		{
+			// Invalid hour.
+			have:    "2023 01 01 24   122    94 10153   300    36     8     0 -9999",
+			wantErr: true,
+		},
+		{
+			// Invalid day.
+			have:    "2023 02 30 00   122    94 10153   300    36     8     0 -9999",
+			wantErr: true,
+		},
+		{
+			have: testLine1,
+			want: &ds.Observation{
+				StationID:            "724940-23234",
+				Source:               DatasetName,
+				License:              noaa.License,
+				Citation:             DatasetCitation,
+				Time:                 time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				TempC:                12.2,
+				DewPointC:            9.4,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  1015.3,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     300,
+				WindSpeedMPS:         3.6,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        8,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          0,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          ds.UnsetValue,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"TempC":               {Quality: ds.QualityPassed},
+					"DewPointC":           {Quality: ds.QualityPassed},
+					"PressureSeaLevelHPa": {Quality: ds.QualityPassed},
+					"WindDirectionDeg":    {Quality: ds.QualityPassed},
+					"WindSpeedMPS":        {Quality: ds.QualityPassed},
+					"SkyCoverOktas":       {Quality: ds.QualityPassed, Original: "8"},
+					"Precip1HrMM":         {Quality: ds.QualityPassed},
+				},
+			},
+		},
+		{
+			// Calm winds, trace precipitation and an older sky cover code.
+			have: testLine2,
+			want: &ds.Observation{
+				StationID:            "724940-23234",
+				Source:               DatasetName,
+				License:              noaa.License,
+				Citation:             DatasetCitation,
+				Time:                 time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC),
+				TempC:                11.7,
+				DewPointC:            8.9,
+				RelativeHumidity:     ds.UnsetValue,
+				PressureStationHPa:   ds.UnsetValue,
+				PressureSeaLevelHPa:  ds.UnsetValue,
+				PressureAltimeterHPa: ds.UnsetValue,
+				WindDirectionDeg:     ds.UnsetValue,
+				WindSpeedMPS:         0,
+				WindGustMPS:          ds.UnsetValue,
+				VisibilityM:          ds.UnsetValue,
+				SkyCoverOktas:        7,
+				CeilingM:             ds.UnsetValue,
+				Precip1HrMM:          0,
+				Precip3HrMM:          ds.UnsetValue,
+				Precip6HrMM:          2.5,
+				Precip24HrMM:         ds.UnsetValue,
+				SnowDepthMM:          ds.UnsetValue,
+				Flags: ds.Flags{
+					"TempC":         {Quality: ds.QualityPassed},
+					"DewPointC":     {Quality: ds.QualityPassed},
+					"WindSpeedMPS":  {Quality: ds.QualityPassed},
+					"SkyCoverOktas": {Quality: ds.QualityEstimated, Original: "15"},
+					"Precip1HrMM":   {Quality: ds.QualityTrace, Original: "-1"},
+					"Precip6HrMM":   {Quality: ds.QualityPassed},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseLine("724940-23234", test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+// parseLine returns the observation for one of the test lines, whose parsing
+// is checked by TestParseLine.
+func parseLine(t *testing.T, line string) *ds.Observation {
+	t.Helper()
+	obs, err := ParseLine("724940-23234", line)
+	if err != nil {
+		t.Fatalf("ParseLine(%q) error = %v", line, err)
+	}
+	return obs
+}
+
+func TestStationIDFromFilename(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    string
+		wantErr bool
+	}{
+		{have: "724940-23234-2023.gz", want: "724940-23234"},
+		{have: "/data/isd-lite/2023/724940-23234-2023", want: "724940-23234"},
+		{have: "gs://bucket/isd-lite/2023/999999-93245-2023.gz", want: "999999-93245"},
+		{have: `C:\isd-lite\724940-23234-2023.gz`, want: "724940-23234"},
+		{have: "isd-history.csv", wantErr: true},
+		{have: "72494023234.csv", wantErr: true},
+		{have: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := StationIDFromFilename(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("StationIDFromFilename(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("StationIDFromFilename(%q) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
+
+func TestSkyCover(t *testing.T) {
+	tests := []struct {
+		code        int64
+		wantOktas   float64
+		wantQuality ds.Quality
+		wantOK      bool
+	}{
+		{code: 0, wantOktas: 0, wantQuality: ds.QualityPassed, wantOK: true},
+		{code: 4, wantOktas: 4, wantQuality: ds.QualityPassed, wantOK: true},
+		{code: 9, wantOktas: 8, wantQuality: ds.QualityEstimated, wantOK: true},
+		{code: 10, wantOK: false},
+		{code: 12, wantOktas: 4, wantQuality: ds.QualityEstimated, wantOK: true},
+		{code: 19, wantOktas: 8, wantQuality: ds.QualityEstimated, wantOK: true},
+		{code: 20, wantOK: false},
+		{code: -1, wantOK: false},
+	}
+
+	for _, test := range tests {
+		oktas, quality, ok := skyCover(test.code)
+		if ok != test.wantOK || (ok && (oktas != test.wantOktas || quality != test.wantQuality)) {
+			t.Errorf("skyCover(%d) = %v, %v, %v, want %v, %v, %v", test.code, oktas, quality, ok,
+				test.wantOktas, test.wantQuality, test.wantOK)
+		}
+	}
+}
+
+func TestReader(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(testLine1 + "\npizza\n" + testLine2 + "\n"))
+	w.Close()
+
+	r, err := NewReader("724940-23234", &buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var got []*ds.Observation
+	for {
+		obs, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		got = append(got, obs)
+	}
+
+	want := []*ds.Observation{parseLine(t, testLine1), parseLine(t, testLine2)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Reader diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestObservations(t *testing.T) {
+	beam.Init()
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(testLine1 + "\n" + testLine2 + "\n"))
+	w.Close()
+	if err := os.WriteFile(filepath.Join(dir, "724940-23234-2023.gz"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Files not named for a station are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "README.gz"), []byte(testLine1+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, s := beam.NewPipelineWithRoot()
+	passert.Equals(s, Observations(s, filepath.Join(dir, "*.gz")), parseLine(t, testLine1), parseLine(t, testLine2))
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("Observations failed: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package isdlite
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+	"github.com/rsned/weather/importers/geography"
+	"github.com/rsned/weather/importers/utils"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// SourceName identifies the ISD stations when merging them with other sources.
+const SourceName = "isd"
+
+// The columns of isd-history.csv.
+const (
+	histUSAF = iota
+	histWBAN
+	histName
+	histCountry
+	histState
+	histICAO
+	histLat
+	histLon
+	histElevation
+	histBegin
+	histEnd
+
+	histColumns
+)
+
+func init() {
+	register.DoFn2x0[string, func(*ds.Station)](&StationParserFn{})
+	register.Emitter1[*ds.Station]()
+}
+
+// StationParserFn is an Apache Beam structural DoFn to process rows from the
+// ISD station history file, isd-history.csv, into Stations. The header row and
+// malformed rows are skipped.
+//
+//	https://www.ncei.noaa.gov/pub/data/noaa/isd-history.csv
+type StationParserFn struct {
+	// Retrieved is when the history file was downloaded, for the stations
+	// Attributions. It is left out if it is the zero time.
+	Retrieved time.Time
+}
+
+// ProcessElement reads one row in and attempts to convert it into a Station.
+func (fn *StationParserFn) ProcessElement(line string, emit func(*ds.Station)) {
+	station, err := ParseHistoryLine(line)
+	if err != nil {
+		return
+	}
+	station.Attributions = Attribution(fn.Retrieved)
+	emit(station)
+}
+
+// ParseHistoryLine parses one row of isd-history.csv into a Station.
+//
+//	"USAF","WBAN","STATION NAME","CTRY","STATE","ICAO","LAT","LON","ELEV(M)","BEGIN","END"
+//	"724940","23234","SAN FRANCISCO INTERNATIONAL AIRPORT","US","CA","KSFO","+37.620","-122.365","+0002.4","19730101","20240101"
+//
+// CTRY is the FIPS country code, and STATE the US state or Canadian province
+// postal code. BEGIN and END are the period of record in YYYYMMDD format.
+func ParseHistoryLine(line string) (*ds.Station, error) {
+	r := csv.NewReader(strings.NewReader(line))
+	r.FieldsPerRecord = histColumns
+	fields, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("isdlite: malformed isd-history row %q: %v", line, err)
+	}
+	if fields[histUSAF] == "USAF" {
+		return nil, fmt.Errorf("isdlite: isd-history header row")
+	}
+
+	usaf := strings.TrimSpace(fields[histUSAF])
+	wban := strings.TrimSpace(fields[histWBAN])
+	if len(usaf) != 6 || len(wban) != 5 {
+		return nil, fmt.Errorf("isdlite: invalid USAF-WBAN %q-%q", usaf, wban)
+	}
+
+	station := ds.EmptyStation()
+	station.Name = strings.TrimSpace(fields[histName])
+	station.Identifiers.UsafID = usaf
+	station.Identifiers.WbanID = wban
+	station.Identifiers.ICAO = strings.TrimSpace(fields[histICAO])
+
+	station.StartDate, err = ds.ParseDate(fields[histBegin])
+	if err != nil {
+		return nil, fmt.Errorf("isdlite: invalid BEGIN for %s-%s: %v", usaf, wban, err)
+	}
+	station.EndDate, err = ds.ParseDate(fields[histEnd])
+	if err != nil {
+		return nil, fmt.Errorf("isdlite: invalid END for %s-%s: %v", usaf, wban, err)
+	}
+
+	// Stations without a known location have blank LAT and LON. Their
+	// Geography is left at the zero location so they can still be matched
+	// by their identifiers.
+	if lat, lng := strings.TrimSpace(fields[histLat]), strings.TrimSpace(fields[histLon]); lat != "" && lng != "" {
+		station.Geography.Lat = float32(utils.ParseFloat(lat, 0))
+		station.Geography.Lng = float32(utils.ParseFloat(lng, 0))
+		geography.Normalize(station.Geography)
+	}
+
+	// ELEV(M) is in meters, and -999.9 or -999.0 when missing.
+	if elev := utils.ParseFloat(fields[histElevation], ds.UnsetValue); elev > -999 {
+		station.Geography.ElevationMeters = int32(elev)
+	} else {
+		station.Geography.ElevationMeters = ds.UnsetValue
+	}
+
+	if region, ok := geography.RegionForFIPS(strings.TrimSpace(fields[histCountry])); ok {
+		region.Apply(station.Geography)
+	}
+	if sub, ok := geography.SubdivisionFor(station.Geography.RegionCode, strings.TrimSpace(fields[histState])); ok {
+		sub.Apply(station.Geography)
+	}
+
+	return station, nil
+}
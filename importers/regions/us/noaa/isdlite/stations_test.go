@@ -0,0 +1,129 @@
+package isdlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	testHistoryHeader = `"USAF","WBAN","STATION NAME","CTRY","STATE","ICAO","LAT","LON","ELEV(M)","BEGIN","END"`
+	testHistorySFO    = `"724940","23234","SAN FRANCISCO INTERNATIONAL AIRPORT","US","CA","KSFO","+37.620","-122.365","+0002.4","19730101","20240101"`
+)
+
+func wantSFOStation() *ds.Station {
+	return &ds.Station{
+		Name: "SAN FRANCISCO INTERNATIONAL AIRPORT",
+		Identifiers: &ds.Identifiers{
+			UsafID: "724940",
+			WbanID: "23234",
+			ICAO:   "KSFO",
+		},
+		Geography: &ds.Geography{
+			Continent:        "North America",
+			MetaRegion:       "NA",
+			RegionCode:       "US",
+			RegionName:       "United States",
+			Subdivision1Code: "US-CA",
+			Subdivision1Name: "California",
+			ElevationMeters:  2,
+			Lat:              37.62,
+			Lng:              -122.365,
+			LatE7:            376200000,
+			LngE7:            -1223650000,
+			S2CellID:         0x808f77e650bacbe5,
+			GeoHash:          "9q8yp8882u6c",
+			PlusCode:         "849VJJCP+22",
+			H3Cell:           0x8f2830905149c0c,
+			Timezone:         "America/Los_Angeles",
+		},
+		Attributions: &ds.Attributions{},
+		StartDate:    ds.Date{Year: 1973, Month: 1, Day: 1},
+		EndDate:      ds.Date{Year: 2024, Month: 1, Day: 1},
+	}
+}
+
+func TestParseHistoryLine(t *testing.T) {
+	tests := []struct {
+		have    string
+		want    *ds.Station
+		wantErr bool
+	}{
+		{
+			have:    "",
+			wantErr: true,
+		},
+		{
+			have:    testHistoryHeader,
+			wantErr: true,
+		},
+		{
+			// Too few fields.
+			have:    `"724940","23234","SAN FRANCISCO INTERNATIONAL AIRPORT"`,
+			wantErr: true,
+		},
+		{
+			// Invalid BEGIN date.
+			have:    `"724940","23234","SAN FRANCISCO INTERNATIONAL AIRPORT","US","CA","KSFO","+37.620","-122.365","+0002.4","19731301","20240101"`,
+			wantErr: true,
+		},
+		{
+			have: testHistorySFO,
+			want: wantSFOStation(),
+		},
+		{
+			// No location, elevation, state or ICAO.
+			have: `"999999","93245","BODEGA","UK","","","","","-0999.0","20010101","20011231"`,
+			want: &ds.Station{
+				Name: "BODEGA",
+				Identifiers: &ds.Identifiers{
+					UsafID: "999999",
+					WbanID: "93245",
+				},
+				Geography: &ds.Geography{
+					Continent:       "Europe",
+					MetaRegion:      "EMEA",
+					RegionCode:      "GB",
+					RegionName:      "United Kingdom",
+					ElevationMeters: ds.UnsetValue,
+				},
+				Attributions: &ds.Attributions{},
+				StartDate:    ds.Date{Year: 2001, Month: 1, Day: 1},
+				EndDate:      ds.Date{Year: 2001, Month: 12, Day: 31},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseHistoryLine(test.have)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseHistoryLine(%q) error = %v, wantErr %v", test.have, err, test.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ParseHistoryLine(%q) diff (-want +got):\n%s", test.have, diff)
+		}
+	}
+}
+
+func TestStationParserFn(t *testing.T) {
+	beam.Init()
+	retrieved := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	want := wantSFOStation()
+	want.Attributions = Attribution(retrieved)
+
+	p, s := beam.NewPipelineWithRoot()
+	lines := beam.Create(s, testHistoryHeader, testHistorySFO)
+	passert.Equals(s, beam.ParDo(s, &StationParserFn{Retrieved: retrieved}, lines), want)
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("StationParserFn failed: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+// Package noaa holds what is shared by the importers for the NOAA datasets in
+// the packages below it.
+package noaa
+
+import (
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// License summarizes the terms from the readmes of the global NOAA datasets,
+// (GHCN-D, GSOD and ISD). The data are a U.S. Government work, but some of the
+// non-U.S. data are shared under WMO Resolution 40 and may not be used
+// commercially.
+const License = ds.LicenseUSGovernment + "; non-U.S. data may be restricted under WMO Resolution 40"
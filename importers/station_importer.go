@@ -6,6 +6,8 @@ import (
 	"encoding/csv"
 	"flag"
 	"log"
+	"os"
+	"time"
 
 	"github.com/apache/beam/sdks/v2/go/pkg/beam"
 	"github.com/apache/beam/sdks/v2/go/pkg/beam/io/textio"
@@ -13,24 +15,64 @@ import (
 	"github.com/apache/beam/sdks/v2/go/pkg/beam/x/beamx"
 
 	ds "github.com/rsned/weather/datastructures"
+	"github.com/rsned/weather/importers/merge"
+	"github.com/rsned/weather/importers/regions/us/epa"
 	"github.com/rsned/weather/importers/regions/us/noaa/ghcnd"
+	"github.com/rsned/weather/importers/regions/us/noaa/gsod"
+	"github.com/rsned/weather/importers/regions/us/noaa/isdlite"
+	"github.com/rsned/weather/importers/stationid"
+	"github.com/rsned/weather/importers/utils"
 )
 
 var (
-	input  = flag.String("input", "", "File(s) to read.")
-	output = flag.String("output", "", "Output file (required).")
+	input       = flag.String("input", "", "File(s) to read.")
+	inventory   = flag.String("inventory", "", "GHCN-D inventory file to read the stations period of record from.")
+	isdHistory  = flag.String("isd_history", "", "ISD station history file (isd-history.csv) to read additional stations from.")
+	gsodInput   = flag.String("gsod", "", "GSOD daily summary CSV file(s) to read additional stations from.")
+	epaSites    = flag.String("epa_sites", "", "EPA AQS site file (aqs_sites.csv) to read additional stations from.")
+	epaMonitors = flag.String("epa_monitors", "", "EPA AQS monitor file (aqs_monitors.csv) to read the EPA stations coverage from.")
+	output      = flag.String("output", "", "Output file (required).")
+	retrieved   = flag.String("retrieved", "", "When the input files were downloaded, in RFC 3339 format, for the stations attributions.")
+	policy      = flag.String("merge_policy", "", "JSON file with the source precedence to use when merging stations.")
+
+	idMapping    = flag.String("id_mapping", "", "Station ID mapping file from the previous run, to keep IDs stable.")
+	idMappingOut = flag.String("id_mapping_out", "", "File to write the updated station ID mapping to.")
+	idCollisions = flag.String("id_collisions", "", "File to write the station ID collision report to.")
 )
 
 func init() {
-	register.Function2x0(generateID)
+	register.Function1x2(keyByGhcnID)
+	register.DoFn4x0[string, func(**ds.Station) bool, func(**ds.ElementCoverage) bool, func(*ds.Station)](&joinCoverageFn{})
+	register.Iter1[*ds.Station]()
+	register.Iter1[*ds.ElementCoverage]()
 	register.Function2x0(stationToCSV)
 	register.Emitter1[*ds.Station]()
 	register.Emitter1[string]()
 }
 
-func generateID(s *ds.Station, emit func(*ds.Station)) {
-	s.ID = s.Identifiers.GhcnID
-	emit(s)
+func keyByGhcnID(s *ds.Station) (string, *ds.Station) {
+	return s.Identifiers.GhcnID, s
+}
+
+// joinCoverageFn adds the GHCN-D inventory coverage onto the matching station.
+type joinCoverageFn struct {
+	// LastUpdated is the date stamp to mark the joined stations with.
+	LastUpdated ds.Date
+}
+
+func (fn *joinCoverageFn) ProcessElement(_ string, stations func(**ds.Station) bool, coverage func(**ds.ElementCoverage) bool, emit func(*ds.Station)) {
+	var cov []*ds.ElementCoverage
+	var c *ds.ElementCoverage
+	for coverage(&c) {
+		cov = append(cov, c)
+	}
+
+	var s *ds.Station
+	for stations(&s) {
+		ghcnd.ApplyCoverage(s, cov)
+		s.LastUpdated = fn.LastUpdated
+		emit(s)
+	}
 }
 
 // Convert the station to a form that is serializable.
@@ -52,6 +94,14 @@ func main() {
 		log.Fatal("No output provided")
 	}
 
+	var retrievedTime time.Time
+	if *retrieved != "" {
+		var err error
+		if retrievedTime, err = time.Parse(time.RFC3339, *retrieved); err != nil {
+			log.Fatalf("Invalid -retrieved time: %v", err)
+		}
+	}
+
 	pipeline := beam.NewPipeline()
 	scope := pipeline.Root()
 
@@ -62,16 +112,75 @@ func main() {
 	lines := textio.Read(scope, *input)
 
 	// Create the initial partial station objects for the lines.
-	initial := beam.ParDo(scope, &ghcnd.StationParserFn{}, lines)
+	initial := beam.ParDo(scope, &ghcnd.StationParserFn{Retrieved: retrievedTime}, lines)
+
+	// Join on the period of record from the inventory.
+	if *inventory != "" {
+		coverage := beam.ParDo(scope, &ghcnd.InventoryParserFn{}, textio.Read(scope, *inventory))
+		keyed := beam.ParDo(scope, keyByGhcnID, initial)
+		joined := beam.CoGroupByKey(scope, keyed, coverage)
+		initial = beam.ParDo(scope, &joinCoverageFn{
+			LastUpdated: ds.DateOf(time.Now().UTC()),
+		}, joined)
+	}
 
-	// For each additional source to try to merge in:
-	//   Read in its lines and convert to partial station objects.
-	//   Run the merge and straggler function on existing PCol and the new PCol.
+	sources := []merge.Source{
+		{Name: ghcnd.SourceName, Stations: initial},
+	}
 
-	// Merge all records into one PCollection.
+	// For each additional source to try to merge in, read in its lines and
+	// convert to partial station objects, then add it to the sources in order
+	// of preference.
+	if *isdHistory != "" {
+		sources = append(sources, merge.Source{
+			Name:     isdlite.SourceName,
+			Stations: beam.ParDo(scope, &isdlite.StationParserFn{Retrieved: retrievedTime}, textio.Read(scope, *isdHistory)),
+		})
+	}
+	if *gsodInput != "" {
+		sources = append(sources, merge.Source{
+			Name:     gsod.SourceName,
+			Stations: gsod.Stations(scope, retrievedTime, utils.ReadLines(scope, *gsodInput)),
+		})
+	}
+	if *epaSites != "" {
+		monitors := beam.CreateList(scope, []string{})
+		if *epaMonitors != "" {
+			monitors = textio.Read(scope, *epaMonitors)
+		}
+		sources = append(sources, merge.Source{
+			Name:     epa.SourceName,
+			Stations: epa.Stations(scope, retrievedTime, textio.Read(scope, *epaSites), monitors),
+		})
+	}
+
+	// Merge all records into one PCollection, with one station per site.
+	opts := merge.DefaultOptions
+	if *policy != "" {
+		f, err := os.Open(*policy)
+		if err != nil {
+			log.Fatalf("Failed to open merge policy: %v", err)
+		}
+		opts.Policy, err = merge.ReadPolicy(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	merged := merge.StationsWithOptions(scope, opts, sources...)
 
 	// Now that all merges have completed, generate the final station ID.
-	stations := beam.ParDo(scope, generateID, initial)
+	mapping := beam.CreateList(scope, []string{})
+	if *idMapping != "" {
+		mapping = textio.Read(scope, *idMapping)
+	}
+	stations, newMapping, collisions := stationid.Stations(scope, merged, mapping)
+	if *idMappingOut != "" {
+		textio.Write(scope, *idMappingOut, newMapping)
+	}
+	if *idCollisions != "" {
+		textio.Write(scope, *idCollisions, collisions)
+	}
 
 	// Convert the station to a form that is serializable.
 	formatted := beam.ParDo(scope, stationToCSV, stations)
@@ -0,0 +1,258 @@
+package stationid
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+// Reasons for a Collision.
+const (
+	// ReasonDuplicate is a station which would have had the same ID as an
+	// earlier station, and was given an alternate ID.
+	ReasonDuplicate = "duplicate"
+	// ReasonMerged is a station whose unique keys had more than one ID in the
+	// mapping, typically because previously separate stations were merged.
+	// It keeps the ID of its most preferred key.
+	ReasonMerged = "merged"
+	// ReasonNoKey is a station with no identifiers, location or name, which
+	// could not be given an ID.
+	ReasonNoKey = "no_key"
+)
+
+// Collision is an entry in the collision report.
+type Collision struct {
+	Reason string
+	// Key is the canonical key of the affected station.
+	Key string
+	// ID is the ID the station was given.
+	ID string
+	// Other is the canonical key of the station that already had the ID for
+	// ReasonDuplicate, or the ID that was dropped for ReasonMerged.
+	Other string
+
+	// Name and the Lat and Lng of the affected station, to find it in the
+	// sources, (especially for ReasonNoKey, which has no Key).
+	Name     string
+	Lat, Lng float32
+}
+
+// newCollision returns a Collision for the station.
+func newCollision(reason string, s *ds.Station, key, id, other string) *Collision {
+	c := &Collision{Reason: reason, Key: key, ID: id, Other: other, Name: s.Name}
+	if s.Geography != nil {
+		c.Lat, c.Lng = s.Geography.Lat, s.Geography.Lng
+	}
+	return c
+}
+
+// CSV returns the collision as a row of the collision report.
+func (c *Collision) CSV() string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{c.Reason, c.Key, c.ID, c.Other, c.Name,
+		strconv.FormatFloat(float64(c.Lat), 'f', -1, 32),
+		strconv.FormatFloat(float64(c.Lng), 'f', -1, 32)})
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// ParseMappingLine parses one "key,id" row of a mapping file.
+func ParseMappingLine(line string) (string, string, error) {
+	i := strings.LastIndexByte(line, ',')
+	if i <= 0 || i == len(line)-1 {
+		return "", "", fmt.Errorf("stationid: malformed mapping row %q", line)
+	}
+	return line[:i], line[i+1:], nil
+}
+
+// MappingLine returns the row of the mapping file for the key and id.
+func MappingLine(key, id string) string {
+	return key + "," + id
+}
+
+// Assign sets the ID on each of the stations using the mapping of keys to IDs
+// from the previous run. It returns the mapping to save for the next run, (the
+// previous entries updated with the IDs the stations were given), and the
+// collisions found. Merges and duplicates are updated in the mapping, so they
+// are only reported once.
+//
+// A station keeps the ID from the previous run of its canonical key, or of
+// any of its unique keys, (see UniqueKey). Its other keys, which can be shared
+// with other stations, are not used to find its ID, and are not added to the
+// mapping.
+func Assign(stations []*ds.Station, mapping map[string]string) (map[string]string, []*Collision) {
+	out := make(map[string]string, len(mapping))
+	for k, v := range mapping {
+		out[k] = v
+	}
+
+	// The ranks of the stations, so that stations which already had an ID
+	// claim it before any new stations could collide with it.
+	const (
+		rankCanonical = iota
+		rankUnique
+		rankNew
+	)
+	type keyed struct {
+		station *ds.Station
+		// keys are the canonical key followed by the unique keys.
+		keys []string
+		rank int
+	}
+	var all []keyed
+	for _, s := range stations {
+		k := keyed{station: s, rank: rankNew}
+		for i, key := range Keys(s) {
+			if i > 0 && !UniqueKey(key) {
+				continue
+			}
+			k.keys = append(k.keys, key)
+			if _, ok := mapping[key]; ok {
+				if i == 0 {
+					k.rank = rankCanonical
+				} else {
+					k.rank = min(k.rank, rankUnique)
+				}
+			}
+		}
+		all = append(all, k)
+	}
+	// Earlier stations win any collisions, so make the order independent of
+	// the order the stations arrived in.
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].rank != all[j].rank {
+			return all[i].rank < all[j].rank
+		}
+		ki, kj := strings.Join(all[i].keys, "|"), strings.Join(all[j].keys, "|")
+		return ki < kj
+	})
+
+	var collisions []*Collision
+	used := map[string]string{}
+	for _, k := range all {
+		s := k.station
+		if len(k.keys) == 0 {
+			s.ID = ""
+			collisions = append(collisions, newCollision(ReasonNoKey, s, "", "", ""))
+			continue
+		}
+		canonical := k.keys[0]
+
+		// Prefer the ID from the previous run for the most preferred key.
+		id := ""
+		dropped := map[string]bool{}
+		for _, key := range k.keys {
+			prev, ok := mapping[key]
+			if !ok {
+				continue
+			}
+			if id == "" {
+				id = prev
+			} else if prev != id && !dropped[prev] {
+				dropped[prev] = true
+				collisions = append(collisions, newCollision(ReasonMerged, s, canonical, id, prev))
+			}
+		}
+		if id == "" {
+			id = ID(canonical)
+		}
+
+		if other, ok := used[id]; ok {
+			for salt := 1; ; salt++ {
+				id = idWithSalt(canonical, salt)
+				if _, ok := used[id]; !ok {
+					break
+				}
+			}
+			collisions = append(collisions, newCollision(ReasonDuplicate, s, canonical, id, other))
+		}
+
+		s.ID = id
+		used[id] = canonical
+		for _, key := range k.keys {
+			out[key] = id
+		}
+	}
+	return out, collisions
+}
+
+func init() {
+	register.DoFn6x0[string, func(**ds.Station) bool, func(*string) bool, func(*ds.Station), func(string), func(string)](&assignFn{})
+	register.Function1x2(keyStation)
+	register.Function1x2(keyMappingLine)
+	register.Iter1[*ds.Station]()
+	register.Iter1[string]()
+	register.Emitter1[*ds.Station]()
+	register.Emitter1[string]()
+}
+
+// Stations assigns the IDs to the PCollection<*ds.Station> of merged stations
+// using the PCollection<string> of rows from the previous runs mapping file,
+// (which may be empty). It returns the stations with their IDs, the rows of
+// the new mapping file, and the rows of the collision report.
+func Stations(s beam.Scope, stations, mapping beam.PCollection) (beam.PCollection, beam.PCollection, beam.PCollection) {
+	s = s.Scope("stationid.Stations")
+
+	// Collisions can only be found by looking at every station, so they are
+	// all grouped together and assigned in memory.
+	keyedStations := beam.ParDo(s, keyStation, stations)
+	keyedMapping := beam.ParDo(s, keyMappingLine, mapping)
+	grouped := beam.CoGroupByKey(s, keyedStations, keyedMapping)
+	return beam.ParDo3(s, &assignFn{}, grouped)
+}
+
+func keyStation(st *ds.Station) (string, *ds.Station) {
+	return "", st
+}
+
+func keyMappingLine(line string) (string, string) {
+	return "", line
+}
+
+// assignFn runs Assign over the grouped stations and mapping.
+type assignFn struct{}
+
+func (fn *assignFn) ProcessElement(_ string, stations func(**ds.Station) bool, lines func(*string) bool,
+	emitStation func(*ds.Station), emitMapping func(string), emitCollision func(string)) {
+	mapping := map[string]string{}
+	var line string
+	for lines(&line) {
+		key, id, err := ParseMappingLine(line)
+		if err != nil {
+			continue
+		}
+		mapping[key] = id
+	}
+
+	var all []*ds.Station
+	var st *ds.Station
+	for stations(&st) {
+		all = append(all, st)
+	}
+
+	out, collisions := Assign(all, mapping)
+	for _, st := range all {
+		emitStation(st)
+	}
+
+	keys := make([]string, 0, len(out))
+	for k := range out {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		emitMapping(MappingLine(k, out[k]))
+	}
+	for _, c := range collisions {
+		emitCollision(c.CSV())
+	}
+}
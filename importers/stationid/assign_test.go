@@ -0,0 +1,261 @@
+package stationid
+
+import (
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func withIDs(name string, ids ds.Identifiers) *ds.Station {
+	s := ds.EmptyStation()
+	s.Name = name
+	s.Identifiers = &ids
+	return s
+}
+
+func TestAssign(t *testing.T) {
+	tests := []struct {
+		name           string
+		stations       []*ds.Station
+		mapping        map[string]string
+		wantIDs        map[string]string
+		wantMapping    map[string]string
+		wantCollisions []*Collision
+	}{
+		{
+			name: "new stations",
+			stations: []*ds.Station{
+				withIDs("SFO", ds.Identifiers{GhcnID: "USW00023234", WmoID: "72494"}),
+				withIDs("LAX", ds.Identifiers{ICAO: "KLAX"}),
+			},
+			wantIDs: map[string]string{
+				"SFO": ID("ghcn:USW00023234"),
+				"LAX": ID("icao:KLAX"),
+			},
+			wantMapping: map[string]string{
+				"ghcn:USW00023234": ID("ghcn:USW00023234"),
+				"icao:KLAX":        ID("icao:KLAX"),
+			},
+		},
+		{
+			// A new source has added a more preferred key, but the station
+			// keeps the ID it had from its USAF-WBAN ID last time, and unused
+			// entries are carried forward.
+			name: "stable across new sources",
+			stations: []*ds.Station{
+				withIDs("LAX", ds.Identifiers{GhcnID: "USW00023174", UsafID: "722950", WbanID: "23174", ICAO: "KLAX"}),
+			},
+			mapping: map[string]string{
+				"usaf-wban:722950-23174": "00000000000000aa",
+				"icao:KSEA":              "00000000000000bb",
+			},
+			wantIDs: map[string]string{
+				"LAX": "00000000000000aa",
+			},
+			wantMapping: map[string]string{
+				"ghcn:USW00023174":       "00000000000000aa",
+				"usaf-wban:722950-23174": "00000000000000aa",
+				"icao:KSEA":              "00000000000000bb",
+			},
+		},
+		{
+			// ICAO codes are not unique, so the ID is only kept while the
+			// code is the canonical key.
+			name: "shared keys are not reused",
+			stations: []*ds.Station{
+				withIDs("LAX", ds.Identifiers{GhcnID: "USW00023174", ICAO: "KLAX"}),
+				withIDs("LAX TOWER", ds.Identifiers{ICAO: "KLAX"}),
+			},
+			mapping: map[string]string{
+				"icao:KLAX": "00000000000000aa",
+			},
+			wantIDs: map[string]string{
+				"LAX":       ID("ghcn:USW00023174"),
+				"LAX TOWER": "00000000000000aa",
+			},
+			wantMapping: map[string]string{
+				"ghcn:USW00023174": ID("ghcn:USW00023174"),
+				"icao:KLAX":        "00000000000000aa",
+			},
+		},
+		{
+			name: "merged stations",
+			stations: []*ds.Station{
+				withIDs("LAX", ds.Identifiers{GhcnID: "USW00023174", UsafID: "722950", WbanID: "23174", ICAO: "KLAX"}),
+			},
+			mapping: map[string]string{
+				"ghcn:USW00023174":       "00000000000000cc",
+				"usaf-wban:722950-23174": "00000000000000aa",
+				"icao:KLAX":              "00000000000000dd",
+			},
+			wantIDs: map[string]string{
+				"LAX": "00000000000000cc",
+			},
+			wantMapping: map[string]string{
+				"ghcn:USW00023174":       "00000000000000cc",
+				"usaf-wban:722950-23174": "00000000000000cc",
+				"icao:KLAX":              "00000000000000dd",
+			},
+			wantCollisions: []*Collision{
+				{Reason: ReasonMerged, Key: "ghcn:USW00023174", ID: "00000000000000cc", Other: "00000000000000aa", Name: "LAX"},
+			},
+		},
+		{
+			// Two stations sharing a WMO ID, which was the only key for the
+			// first one last time.
+			name: "shared WMO ID",
+			stations: []*ds.Station{
+				withIDs("B", ds.Identifiers{GhcnID: "B0000000002", WmoID: "11111"}),
+				withIDs("A", ds.Identifiers{GhcnID: "A0000000001", WmoID: "11111"}),
+			},
+			mapping: map[string]string{
+				"wmo:11111": "00000000000000dd",
+			},
+			wantIDs: map[string]string{
+				"A": ID("ghcn:A0000000001"),
+				"B": ID("ghcn:B0000000002"),
+			},
+			wantMapping: map[string]string{
+				"wmo:11111":        "00000000000000dd",
+				"ghcn:A0000000001": ID("ghcn:A0000000001"),
+				"ghcn:B0000000002": ID("ghcn:B0000000002"),
+			},
+		},
+		{
+			// The station which still has its canonical key keeps the ID,
+			// even though the other sorts first.
+			name: "duplicate",
+			stations: []*ds.Station{
+				withIDs("A", ds.Identifiers{GhcnID: "A0000000001", UsafID: "111111", WbanID: "11111"}),
+				withIDs("B", ds.Identifiers{GhcnID: "B0000000002"}),
+			},
+			mapping: map[string]string{
+				"usaf-wban:111111-11111": "00000000000000dd",
+				"ghcn:B0000000002":       "00000000000000dd",
+			},
+			wantIDs: map[string]string{
+				"A": idWithSalt("ghcn:A0000000001", 1),
+				"B": "00000000000000dd",
+			},
+			wantMapping: map[string]string{
+				"usaf-wban:111111-11111": idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:A0000000001":       idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:B0000000002":       "00000000000000dd",
+			},
+			wantCollisions: []*Collision{
+				{Reason: ReasonDuplicate, Key: "ghcn:A0000000001", ID: idWithSalt("ghcn:A0000000001", 1), Other: "ghcn:B0000000002", Name: "A"},
+			},
+		},
+		{
+			// The next run with the mapping from the duplicate, where the
+			// stations keep their IDs without being reported again.
+			name: "stable duplicate",
+			stations: []*ds.Station{
+				withIDs("A", ds.Identifiers{GhcnID: "A0000000001", UsafID: "111111", WbanID: "11111"}),
+				withIDs("B", ds.Identifiers{GhcnID: "B0000000002"}),
+			},
+			mapping: map[string]string{
+				"usaf-wban:111111-11111": idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:A0000000001":       idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:B0000000002":       "00000000000000dd",
+			},
+			wantIDs: map[string]string{
+				"A": idWithSalt("ghcn:A0000000001", 1),
+				"B": "00000000000000dd",
+			},
+			wantMapping: map[string]string{
+				"usaf-wban:111111-11111": idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:A0000000001":       idWithSalt("ghcn:A0000000001", 1),
+				"ghcn:B0000000002":       "00000000000000dd",
+			},
+		},
+		{
+			// Not yet normalized, so there is no S2 cell for a geo key.
+			name: "no key",
+			stations: []*ds.Station{
+				{Identifiers: &ds.Identifiers{}, Geography: &ds.Geography{Lat: 37.6197, Lng: -122.3656}},
+			},
+			wantIDs:        map[string]string{"": ""},
+			wantMapping:    map[string]string{},
+			wantCollisions: []*Collision{{Reason: ReasonNoKey, Lat: 37.6197, Lng: -122.3656}},
+		},
+	}
+
+	for _, test := range tests {
+		gotMapping, gotCollisions := Assign(test.stations, test.mapping)
+
+		gotIDs := map[string]string{}
+		for _, s := range test.stations {
+			gotIDs[s.Name] = s.ID
+		}
+		if diff := cmp.Diff(test.wantIDs, gotIDs); diff != "" {
+			t.Errorf("%s: Assign() IDs diff (-want +got):\n%s", test.name, diff)
+		}
+		if diff := cmp.Diff(test.wantMapping, gotMapping); diff != "" {
+			t.Errorf("%s: Assign() mapping diff (-want +got):\n%s", test.name, diff)
+		}
+		if diff := cmp.Diff(test.wantCollisions, gotCollisions); diff != "" {
+			t.Errorf("%s: Assign() collisions diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestCollisionCSV(t *testing.T) {
+	tests := []struct {
+		have *Collision
+		want string
+	}{
+		{
+			have: &Collision{Reason: ReasonDuplicate, Key: "ghcn:A0000000001", ID: "00000000000000aa", Other: "ghcn:B0000000002", Name: "A"},
+			want: "duplicate,ghcn:A0000000001,00000000000000aa,ghcn:B0000000002,A,0,0",
+		},
+		{
+			have: &Collision{Reason: ReasonNoKey, Name: "SAN FRANCISCO, CA", Lat: 37.6197, Lng: -122.3656},
+			want: `no_key,,,,"SAN FRANCISCO, CA",37.6197,-122.3656`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.CSV(); got != test.want {
+			t.Errorf("%+v.CSV() = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
+
+func TestParseMappingLine(t *testing.T) {
+	key, id, err := ParseMappingLine(MappingLine("geo:808f77e4:SFO", "00000000000000aa"))
+	if err != nil || key != "geo:808f77e4:SFO" || id != "00000000000000aa" {
+		t.Errorf("ParseMappingLine() = %q, %q, %v, want the inputs back", key, id, err)
+	}
+
+	for _, line := range []string{"", "nocomma", ",id", "key,"} {
+		if _, _, err := ParseMappingLine(line); err == nil {
+			t.Errorf("ParseMappingLine(%q) should have failed", line)
+		}
+	}
+}
+
+func TestStations(t *testing.T) {
+	beam.Init()
+	pipeline, scope := beam.NewPipelineWithRoot()
+
+	stations := beam.Create(scope, withIDs("LAX", ds.Identifiers{ICAO: "KLAX"}))
+	mapping := beam.Create(scope, "icao:KLAX,00000000000000aa", "bad row")
+
+	got, gotMapping, gotCollisions := Stations(scope, stations, mapping)
+
+	want := withIDs("LAX", ds.Identifiers{ICAO: "KLAX"})
+	want.ID = "00000000000000aa"
+	passert.Equals(scope, got, want)
+	passert.Equals(scope, gotMapping, "icao:KLAX,00000000000000aa")
+	passert.Empty(scope, gotCollisions)
+
+	if err := ptest.Run(pipeline); err != nil {
+		t.Errorf("Failed to execute job: %v", err)
+	}
+}
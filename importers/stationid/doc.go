@@ -0,0 +1,23 @@
+/*
+Package stationid generates the Station.ID for merged stations.
+
+IDs are derived from the stations identifiers rather than any one source, so a
+station keeps its ID regardless of which sources it was built from. Each ID is
+the hash of the stations canonical key, the first it has of:
+
+	ghcn:<GHCN ID>
+	usaf-wban:<USAF ID>-<WBAN ID>
+	epa:<EPA AQS site ID>
+	wmo:<WMO ID>
+	icao:<ICAO code>
+	geo:<S2 cell token>:<name>
+
+To keep IDs stable as sources are added, (which can change which key is
+canonical), every run writes out a mapping of each stations canonical key and
+its unique keys, (ghcn, usaf-wban and epa), to its ID. Passing the previous runs
+mapping back in means a station whose canonical key or any unique key is
+already in the mapping keeps its ID. WMO IDs, ICAO codes and geo keys can be
+shared by several stations, so they only carry an ID forward while they are
+the canonical key.
+*/
+package stationid
@@ -0,0 +1,132 @@
+package stationid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+const (
+	// geoKeyLevel is the S2 cell level used for location based keys. Level 13
+	// cells are about 1km across.
+	geoKeyLevel = 13
+
+	// The ISD history uses these for stations without the given identifier.
+	missingUsafID = "999999"
+	missingWbanID = "99999"
+
+	// epaIDPrefix is put before the EPA AQS site IDs in the RegionalIDs,
+	// (see epa.RegionalIDPrefix), under the region the site is in.
+	epaIDPrefix = "EPA:"
+)
+
+// Keys returns all of the keys which identify the station, in order from the
+// most to least preferred. The first is the stations canonical key.
+func Keys(s *ds.Station) []string {
+	var keys []string
+	if ids := s.Identifiers; ids != nil {
+		if ids.GhcnID != "" {
+			keys = append(keys, "ghcn:"+ids.GhcnID)
+		}
+		if ids.GhcnIDAlt != "" {
+			keys = append(keys, "ghcn:"+ids.GhcnIDAlt)
+		}
+		if ids.UsafID != "" && ids.WbanID != "" &&
+			(ids.UsafID != missingUsafID || ids.WbanID != missingWbanID) {
+			keys = append(keys, "usaf-wban:"+ids.UsafID+"-"+ids.WbanID)
+		}
+		keys = append(keys, epaKeys(ids)...)
+		if ids.WmoID != "" {
+			keys = append(keys, "wmo:"+ids.WmoID)
+		}
+		if ids.ICAO != "" {
+			keys = append(keys, "icao:"+ids.ICAO)
+		}
+	}
+	if k := geoKey(s); k != "" {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// uniqueKeyPrefixes are the prefixes of the keys which identify at most one
+// station. WMO IDs and ICAO codes are shared by co-located stations and
+// reassigned over time, and geo keys are shared by nearby stations with the
+// same name.
+var uniqueKeyPrefixes = []string{"ghcn:", "usaf-wban:", "epa:"}
+
+// UniqueKey reports if the key identifies at most one station, so that a
+// station can be given the ID another station had for it.
+func UniqueKey(key string) bool {
+	for _, p := range uniqueKeyPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// epaKeys returns the keys for the EPA AQS site IDs in the RegionalIDs, sorted.
+func epaKeys(ids *ds.Identifiers) []string {
+	var keys []string
+	for region := range ids.RegionalIDs {
+		for _, id := range ids.RegionalIDsIn(region) {
+			if site, ok := strings.CutPrefix(id, epaIDPrefix); ok && site != "" {
+				keys = append(keys, "epa:"+site)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// geoKey returns the key made from the stations location and name, or "" if
+// it has neither.
+func geoKey(s *ds.Station) string {
+	name := strings.Join(strings.FieldsFunc(strings.ToUpper(s.Name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-")
+
+	cell := ""
+	if g := s.Geography; g != nil && g.S2CellID != 0 && (g.Lat != 0 || g.Lng != 0) {
+		cell = cellToken(cellParent(g.S2CellID, geoKeyLevel))
+	}
+
+	if name == "" && cell == "" {
+		return ""
+	}
+	return "geo:" + cell + ":" + name
+}
+
+// cellParent returns the id of the cell at the given level containing the leaf
+// cell id.
+func cellParent(id uint64, level int) uint64 {
+	lsb := uint64(1) << (2 * (30 - level))
+	return (id & -lsb) | lsb
+}
+
+// cellToken returns the s2 token for the cell id, the hex form with trailing
+// zeros removed.
+func cellToken(id uint64) string {
+	return strings.TrimRight(fmt.Sprintf("%016x", id), "0")
+}
+
+// ID returns the station ID for the canonical key.
+func ID(key string) string {
+	return idWithSalt(key, 0)
+}
+
+// idWithSalt returns the ID for the key, or for salt > 0, an alternate ID used
+// to separate stations that would otherwise collide.
+func idWithSalt(key string, salt int) string {
+	if salt > 0 {
+		key = fmt.Sprintf("%s#%d", key, salt)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%016x", binary.BigEndian.Uint64(sum[:8]))
+}
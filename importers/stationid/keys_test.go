@@ -0,0 +1,105 @@
+package stationid
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	ds "github.com/rsned/weather/datastructures"
+)
+
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		have *ds.Station
+		want []string
+	}{
+		{
+			name: "empty",
+			have: ds.EmptyStation(),
+			want: nil,
+		},
+		{
+			name: "all identifiers",
+			have: &ds.Station{
+				Name: "SAN FRANCISCO INTL AP",
+				Identifiers: &ds.Identifiers{
+					WmoID:  "72494",
+					GhcnID: "USW00023234",
+					UsafID: "724940",
+					WbanID: "23234",
+					ICAO:   "KSFO",
+				},
+				Geography: &ds.Geography{
+					Lat:      37.6197,
+					Lng:      -122.3656,
+					S2CellID: 0x808f77e64ed8f301,
+				},
+			},
+			want: []string{
+				"ghcn:USW00023234",
+				"usaf-wban:724940-23234",
+				"wmo:72494",
+				"icao:KSFO",
+				"geo:808f77e4:SAN-FRANCISCO-INTL-AP",
+			},
+		},
+		{
+			name: "epa site",
+			have: &ds.Station{
+				Name: "Windsor West",
+				Identifiers: &ds.Identifiers{
+					RegionalIDs: map[string]string{"CA": "EPA:CC-040-0207 HCDN:02GG003"},
+				},
+			},
+			want: []string{"epa:CC-040-0207", "geo::WINDSOR-WEST"},
+		},
+		{
+			name: "placeholder usaf-wban",
+			have: &ds.Station{
+				Name: "Shemya",
+				Identifiers: &ds.Identifiers{
+					UsafID: "999999",
+					WbanID: "99999",
+				},
+			},
+			want: []string{"geo::SHEMYA"},
+		},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.want, Keys(test.have)); diff != "" {
+			t.Errorf("%s: Keys() diff (-want +got):\n%s", test.name, diff)
+		}
+	}
+}
+
+func TestCellParent(t *testing.T) {
+	leaf := uint64(0x808f77e64ed8f301)
+	tests := []struct {
+		level int
+		want  string
+	}{
+		{level: 0, want: "9"},
+		{level: 13, want: "808f77e4"},
+		{level: 30, want: "808f77e64ed8f301"},
+	}
+
+	for _, test := range tests {
+		if got := cellToken(cellParent(leaf, test.level)); got != test.want {
+			t.Errorf("cellToken(cellParent(%x, %d)) = %q, want %q", leaf, test.level, got, test.want)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	if got, want := ID("ghcn:USW00023234"), ID("ghcn:USW00023234"); got != want {
+		t.Errorf("ID() is not deterministic, got %q and %q", got, want)
+	}
+	if len(ID("ghcn:USW00023234")) != 16 {
+		t.Errorf("ID() = %q, want 16 hex characters", ID("ghcn:USW00023234"))
+	}
+	if ID("ghcn:USW00023234") == idWithSalt("ghcn:USW00023234", 1) {
+		t.Errorf("idWithSalt() should differ from ID()")
+	}
+}
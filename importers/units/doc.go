@@ -0,0 +1,12 @@
+/*
+Package units converts the values reported by the data sources into the SI
+units, (or the common meteorological ones for them such as hPa and mm), used
+throughout the datastructures package.
+
+Each source declares the Unit its values are in, e.g. GHCN-D temperatures are
+in TenthsCelsius and GSOD wind speeds in Knots, and converts them with Convert
+or MustConvert instead of scattering scale factors through the parsers.
+
+Precipitation and snow depths are lengths, reported in Millimeters.
+*/
+package units
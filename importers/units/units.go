@@ -0,0 +1,134 @@
+package units
+
+import "fmt"
+
+// Quantity is the kind of physical quantity a Unit measures. Values can only
+// be converted between units of the same Quantity.
+type Quantity int
+
+// The quantities measured by the sources.
+const (
+	QuantityTemperature Quantity = iota + 1
+	QuantityLength
+	QuantitySpeed
+	QuantityPressure
+	QuantityAngle
+	QuantityRatio
+	QuantityDuration
+)
+
+func (q Quantity) String() string {
+	switch q {
+	case QuantityTemperature:
+		return "temperature"
+	case QuantityLength:
+		return "length"
+	case QuantitySpeed:
+		return "speed"
+	case QuantityPressure:
+		return "pressure"
+	case QuantityAngle:
+		return "angle"
+	case QuantityRatio:
+		return "ratio"
+	case QuantityDuration:
+		return "duration"
+	}
+	return fmt.Sprintf("Quantity(%d)", int(q))
+}
+
+// Unit is a unit of measure for one Quantity.
+//
+// A value v in the unit is (v + offset) * num / den in the base unit of its
+// Quantity. The factors are kept as exact ratios where possible so that the
+// common conversions, such as tenths to whole units, give the same float64 as
+// the decimal literal, e.g. 56 tenths of a millimeter is exactly 5.6.
+type Unit struct {
+	// Symbol is the short name of the unit, e.g. "degC" or "kn".
+	Symbol   string
+	Quantity Quantity
+
+	num, den float64
+	offset   float64
+}
+
+func (u Unit) String() string {
+	return u.Symbol
+}
+
+// Temperature units. The base unit is Celsius.
+var (
+	Celsius       = Unit{Symbol: "degC", Quantity: QuantityTemperature, num: 1, den: 1}
+	TenthsCelsius = Unit{Symbol: "0.1degC", Quantity: QuantityTemperature, num: 1, den: 10}
+	Fahrenheit    = Unit{Symbol: "degF", Quantity: QuantityTemperature, num: 5, den: 9, offset: -32}
+	Kelvin        = Unit{Symbol: "K", Quantity: QuantityTemperature, num: 1, den: 1, offset: -273.15}
+)
+
+// Length units. The base unit is Millimeters so that the small precipitation
+// depths convert exactly.
+var (
+	Millimeters       = Unit{Symbol: "mm", Quantity: QuantityLength, num: 1, den: 1}
+	TenthsMillimeters = Unit{Symbol: "0.1mm", Quantity: QuantityLength, num: 1, den: 10}
+	Centimeters       = Unit{Symbol: "cm", Quantity: QuantityLength, num: 10, den: 1}
+	Meters            = Unit{Symbol: "m", Quantity: QuantityLength, num: 1000, den: 1}
+	Kilometers        = Unit{Symbol: "km", Quantity: QuantityLength, num: 1000000, den: 1}
+	Inches            = Unit{Symbol: "in", Quantity: QuantityLength, num: 254, den: 10}
+	HundredthsInches  = Unit{Symbol: "0.01in", Quantity: QuantityLength, num: 254, den: 1000}
+	Feet              = Unit{Symbol: "ft", Quantity: QuantityLength, num: 3048, den: 10}
+	StatuteMiles      = Unit{Symbol: "mi", Quantity: QuantityLength, num: 1609344, den: 1}
+	NauticalMiles     = Unit{Symbol: "nmi", Quantity: QuantityLength, num: 1852000, den: 1}
+)
+
+// Speed units. The base unit is MetersPerSecond.
+var (
+	MetersPerSecond       = Unit{Symbol: "m/s", Quantity: QuantitySpeed, num: 1, den: 1}
+	TenthsMetersPerSecond = Unit{Symbol: "0.1m/s", Quantity: QuantitySpeed, num: 1, den: 10}
+	KilometersPerHour     = Unit{Symbol: "km/h", Quantity: QuantitySpeed, num: 1000, den: 3600}
+	Knots                 = Unit{Symbol: "kn", Quantity: QuantitySpeed, num: 1852, den: 3600}
+	TenthsKnots           = Unit{Symbol: "0.1kn", Quantity: QuantitySpeed, num: 1852, den: 36000}
+	MilesPerHour          = Unit{Symbol: "mph", Quantity: QuantitySpeed, num: 1609344, den: 3600000}
+)
+
+// Pressure units. The base unit is Hectopascals. Inches of mercury use the
+// conventional 3386.389 Pa used by the NWS for altimeter settings.
+var (
+	Hectopascals         = Unit{Symbol: "hPa", Quantity: QuantityPressure, num: 1, den: 1}
+	TenthsHectopascals   = Unit{Symbol: "0.1hPa", Quantity: QuantityPressure, num: 1, den: 10}
+	Millibars            = Unit{Symbol: "mb", Quantity: QuantityPressure, num: 1, den: 1}
+	Pascals              = Unit{Symbol: "Pa", Quantity: QuantityPressure, num: 1, den: 100}
+	Kilopascals          = Unit{Symbol: "kPa", Quantity: QuantityPressure, num: 10, den: 1}
+	InchesOfMercury      = Unit{Symbol: "inHg", Quantity: QuantityPressure, num: 3386389, den: 100000}
+	HundredthsInchesOfHg = Unit{Symbol: "0.01inHg", Quantity: QuantityPressure, num: 3386389, den: 10000000}
+)
+
+// Units for the remaining values, which the sources already report in the
+// units used by the datastructures.
+var (
+	Degrees = Unit{Symbol: "deg", Quantity: QuantityAngle, num: 1, den: 1}
+	Percent = Unit{Symbol: "%", Quantity: QuantityRatio, num: 1, den: 1}
+	Minutes = Unit{Symbol: "min", Quantity: QuantityDuration, num: 1, den: 1}
+)
+
+// Convert converts the value v from one unit to another. An error is returned
+// if the units are for different quantities.
+func Convert(v float64, from, to Unit) (float64, error) {
+	if from.Quantity != to.Quantity || from.den == 0 || to.num == 0 {
+		return 0, fmt.Errorf("units: cannot convert %s %s to %s %s", from.Quantity, from, to.Quantity, to)
+	}
+	if from == to {
+		return v, nil
+	}
+	base := (v + from.offset) * from.num / from.den
+	return base*to.den/to.num - to.offset, nil
+}
+
+// MustConvert is like Convert but panics if the units are for different
+// quantities. It is meant for the fixed unit tables in the importers, where
+// a mismatch is a programming error.
+func MustConvert(v float64, from, to Unit) float64 {
+	out, err := Convert(v, from, to)
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		v        float64
+		from, to Unit
+		want     float64
+		// tolerance is the allowed difference, or 0 if the result must be
+		// exactly the wanted value.
+		tolerance float64
+	}{
+		// Temperature.
+		{v: 139, from: TenthsCelsius, to: Celsius, want: 13.9},
+		{v: -72, from: TenthsCelsius, to: Celsius, want: -7.2},
+		{v: 32, from: Fahrenheit, to: Celsius, want: 0},
+		{v: 212, from: Fahrenheit, to: Celsius, want: 100},
+		{v: -40, from: Fahrenheit, to: Celsius, want: -40},
+		{v: 71.1, from: Fahrenheit, to: Celsius, want: 21.72, tolerance: 0.01},
+		{v: 0, from: Celsius, to: Fahrenheit, want: 32},
+		{v: 37, from: Celsius, to: Fahrenheit, want: 98.6, tolerance: 1e-9},
+		{v: 273.15, from: Kelvin, to: Celsius, want: 0},
+		{v: 0, from: Celsius, to: Kelvin, want: 273.15},
+
+		// Length.
+		{v: 56, from: TenthsMillimeters, to: Millimeters, want: 5.6},
+		{v: 12, from: HundredthsInches, to: Millimeters, want: 3.048},
+		{v: 1, from: Inches, to: Millimeters, want: 25.4},
+		{v: 10, from: StatuteMiles, to: Meters, want: 16093.44},
+		{v: 16093.44, from: Meters, to: StatuteMiles, want: 10},
+		{v: 1000, from: Feet, to: Meters, want: 304.8},
+		{v: 1, from: NauticalMiles, to: Meters, want: 1852},
+		{v: 5, from: Centimeters, to: Millimeters, want: 50},
+		{v: 2, from: Kilometers, to: Meters, want: 2000},
+
+		// Speed.
+		{v: 45, from: TenthsMetersPerSecond, to: MetersPerSecond, want: 4.5},
+		{v: 10, from: Knots, to: MetersPerSecond, want: 5.144, tolerance: 0.001},
+		{v: 100, from: TenthsKnots, to: Knots, want: 10, tolerance: 1e-12},
+		{v: 60, from: MilesPerHour, to: MetersPerSecond, want: 26.8224},
+		{v: 36, from: KilometersPerHour, to: MetersPerSecond, want: 10},
+		{v: 1, from: MetersPerSecond, to: Knots, want: 1.9438, tolerance: 0.0001},
+
+		// Pressure.
+		{v: 10152, from: TenthsHectopascals, to: Hectopascals, want: 1015.2},
+		{v: 1013.2, from: Millibars, to: Hectopascals, want: 1013.2},
+		{v: 29.92, from: InchesOfMercury, to: Hectopascals, want: 1013.21, tolerance: 0.01},
+		{v: 2992, from: HundredthsInchesOfHg, to: Hectopascals, want: 1013.21, tolerance: 0.01},
+		{v: 101325, from: Pascals, to: Hectopascals, want: 1013.25},
+		{v: 101.325, from: Kilopascals, to: Hectopascals, want: 1013.25, tolerance: 1e-9},
+		{v: 1013.25, from: Hectopascals, to: InchesOfMercury, want: 29.92, tolerance: 0.01},
+
+		// Pass through units.
+		{v: 270, from: Degrees, to: Degrees, want: 270},
+		{v: 83, from: Percent, to: Percent, want: 83},
+		{v: 426, from: Minutes, to: Minutes, want: 426},
+	}
+
+	for _, test := range tests {
+		got, err := Convert(test.v, test.from, test.to)
+		if err != nil {
+			t.Errorf("Convert(%v, %s, %s) error = %v", test.v, test.from, test.to, err)
+			continue
+		}
+		if test.tolerance == 0 && got != test.want || math.Abs(got-test.want) > test.tolerance {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", test.v, test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestConvertMismatch(t *testing.T) {
+	tests := []struct {
+		from, to Unit
+	}{
+		{from: Celsius, to: Meters},
+		{from: Knots, to: StatuteMiles},
+		{from: Millibars, to: Inches},
+		{from: Unit{}, to: Unit{}},
+	}
+
+	for _, test := range tests {
+		if got, err := Convert(1, test.from, test.to); err == nil {
+			t.Errorf("Convert(1, %s, %s) = %v, want error", test.from, test.to, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustConvert(1, Knots, Meters) did not panic")
+		}
+	}()
+	MustConvert(1, Knots, Meters)
+}
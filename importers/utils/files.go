@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/io/filesystem"
+	_ "github.com/apache/beam/sdks/v2/go/pkg/beam/io/filesystem/local"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/register"
+)
+
+// The first two bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// The first four bytes of every zip archive with at least one file.
+var zipMagic = []byte("PK\x03\x04")
+
+func init() {
+	register.Function3x1(expandGlobFn)
+	register.Function3x1(readLinesFn)
+	register.Function3x1(readFileLinesFn)
+	register.Emitter1[string]()
+	register.Emitter2[string, string]()
+}
+
+// MaybeGunzip returns a reader over the uncompressed contents of r. If r does
+// not start with the gzip magic bytes, the data is passed through unchanged.
+//
+// Many NOAA files are available both as plain text and gzip compressed, so
+// this lets importers accept either one without needing to be told which.
+func MaybeGunzip(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(magic) < len(gzipMagic) || magic[0] != gzipMagic[0] || magic[1] != gzipMagic[1] {
+		return br, nil
+	}
+	return gzip.NewReader(br)
+}
+
+// ReadLines reads all the lines in the files matching the given glob, transparently
+// decompressing any files that are gzip compressed. Files which are zip archives,
+// (such as the EPA AQS files), have the lines of every file in them read in turn.
+//
+// Unlike textio.Read, files are not split, so each file is read in its entirety
+// by a single worker.
+func ReadLines(s beam.Scope, glob string) beam.PCollection {
+	s = s.Scope("utils.ReadLines")
+	files := beam.ParDo(s, expandGlobFn, beam.Create(s, glob))
+	return beam.ParDo(s, readLinesFn, files)
+}
+
+// ReadFileLines is like ReadLines, but returns a PCollection<KV<string, string>>
+// of each line keyed by the name of the file it is from. This is for sources
+// such as ISD-Lite where the station the lines are for is only given in the
+// file name.
+func ReadFileLines(s beam.Scope, glob string) beam.PCollection {
+	s = s.Scope("utils.ReadFileLines")
+	files := beam.ParDo(s, expandGlobFn, beam.Create(s, glob))
+	return beam.ParDo(s, readFileLinesFn, files)
+}
+
+// expandGlobFn expands a glob pattern into all matching file names.
+func expandGlobFn(ctx context.Context, glob string, emit func(string)) error {
+	if strings.TrimSpace(glob) == "" {
+		return nil
+	}
+
+	fs, err := filesystem.New(ctx, glob)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	files, err := fs.List(ctx, glob)
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		emit(filename)
+	}
+	return nil
+}
+
+// readLinesFn emits every line in the given file.
+func readLinesFn(ctx context.Context, filename string, emit func(string)) error {
+	return eachLine(ctx, filename, emit)
+}
+
+// readFileLinesFn emits every line in the given file keyed by the file name.
+func readFileLinesFn(ctx context.Context, filename string, emit func(string, string)) error {
+	return eachLine(ctx, filename, func(line string) {
+		emit(filename, line)
+	})
+}
+
+// eachLine calls fn with every line in the given file.
+func eachLine(ctx context.Context, filename string, fn func(string)) error {
+	fs, err := filesystem.New(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	fd, err := fs.OpenRead(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	br := bufio.NewReader(fd)
+	if magic, _ := br.Peek(len(zipMagic)); bytes.Equal(magic, zipMagic) {
+		return eachZipLine(br, fn)
+	}
+
+	r, err := MaybeGunzip(br)
+	if err != nil {
+		return err
+	}
+	return scanLines(r, fn)
+}
+
+// eachZipLine calls fn with every line of every file in the zip archive read
+// from r. Zip archives can only be read with random access, so the whole
+// archive is read into memory first.
+func eachZipLine(r io.Reader, fn func(string)) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = scanLines(rc, fn)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanLines calls fn with every line read from r.
+func scanLines(r io.Reader, fn func(string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+	return scanner.Err()
+}
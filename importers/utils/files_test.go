@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/beam/sdks/v2/go/pkg/beam"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/passert"
+	"github.com/apache/beam/sdks/v2/go/pkg/beam/testing/ptest"
+)
+
+func gzipped(s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+// zipped returns a zip archive holding the given files, keyed by name.
+func zipped(files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, contents := range files {
+		f, _ := w.Create(name)
+		f.Write([]byte(contents))
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestMaybeGunzip(t *testing.T) {
+	tests := []struct {
+		have []byte
+		want string
+	}{
+		// Empty inputs.
+		{
+			have: nil,
+			want: "",
+		},
+		{
+			have: gzipped(""),
+			want: "",
+		},
+		// Too short to hold the magic bytes.
+		{
+			have: []byte{0x1f},
+			want: "\x1f",
+		},
+		// Normal cases.
+		{
+			have: []byte("USW00023234,20230101,TMAX,139,,,W,2400\n"),
+			want: "USW00023234,20230101,TMAX,139,,,W,2400\n",
+		},
+		{
+			have: gzipped("USW00023234,20230101,TMAX,139,,,W,2400\n"),
+			want: "USW00023234,20230101,TMAX,139,,,W,2400\n",
+		},
+	}
+
+	for _, test := range tests {
+		r, err := MaybeGunzip(bytes.NewReader(test.have))
+		if err != nil {
+			t.Errorf("MaybeGunzip(%q) error = %v", test.have, err)
+			continue
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Errorf("MaybeGunzip(%q) read error = %v", test.have, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("MaybeGunzip(%q) = %q, want %q", test.have, got, test.want)
+		}
+	}
+}
+
+func TestReadFileLines(t *testing.T) {
+	beam.Init()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a1\na2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt.gz"), gzipped("b1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.zip"), zipped(map[string]string{"c.csv": "c1\nc2\n", "d.csv": "d1"}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, s := beam.NewPipelineWithRoot()
+	lines := ReadFileLines(s, filepath.Join(dir, "*"))
+	joined := beam.ParDo(s, func(filename, line string) string {
+		return filepath.Base(filename) + ":" + line
+	}, lines)
+	passert.Equals(s, joined, "a.txt:a1", "a.txt:a2", "b.txt.gz:b1", "c.zip:c1", "c.zip:c2", "c.zip:d1")
+
+	if err := ptest.Run(p); err != nil {
+		t.Errorf("ReadFileLines failed: %v", err)
+	}
+}
@@ -69,6 +69,9 @@ func ParseFloatBounded(s string, min, max, def float64) float64 {
 
 // ParseFloatScaled attempts to parse an float value from the given string, and
 // if successful, scale it by the given amount. Otherwise the default is returned.
+//
+// To convert values between units, use ParseFloat and the units package
+// instead, so the units are declared explicitly.
 func ParseFloatScaled(s string, scale, def float64) float64 {
 	f := ParseFloat(s, def)
 